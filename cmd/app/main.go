@@ -3,13 +3,21 @@ package main
 import (
 	"chrononewsapi/internal/bootstrap"
 	"chrononewsapi/internal/config"
+	"context"
+	"errors"
 	"log"
 	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 )
 
+const shutdownTimeout = 30 * time.Second
+
 func main() {
 	appConfig := config.NewConfig()
 	db := config.NewDatabase(appConfig)
@@ -26,10 +34,33 @@ func main() {
 		}
 	}
 
-	bootstrap.Init(chi, db, appConfig, validator, httpClient, s3Client)
-	slog.Info("Server run on port " + appConfig.Web.Port)
-	err := http.ListenAndServe("0.0.0.0:"+appConfig.Web.Port, chi)
-	if err != nil {
-		log.Fatal(err)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Workers get their own context so they are stopped only after the
+	// server has finished the requests that may still record views.
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	workers := bootstrap.Init(workerCtx, chi, db, appConfig, validator, httpClient, s3Client)
+
+	server := &http.Server{Addr: "0.0.0.0:" + appConfig.Web.Port, Handler: chi}
+	go func() {
+		slog.Info("Server run on port " + appConfig.Web.Port)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+	slog.Info("Shutting down server")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Error("Failed to shut down server gracefully", "error", err)
 	}
+
+	stopWorkers()
+	workers.Wait()
+	slog.Info("Server stopped")
 }
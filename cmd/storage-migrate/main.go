@@ -0,0 +1,95 @@
+package main
+
+import (
+	"chrononewsapi/internal/adapter"
+	"chrononewsapi/internal/config"
+	"chrononewsapi/internal/repository"
+	"chrononewsapi/internal/service"
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// storage-migrate copies every file object from the storage configured for
+// the API (or -source) to the storage described in -target. Both files use
+// the same "storage" section as config.json.
+func main() {
+	sourcePath := flag.String("source", "", "JSON config file whose storage section is the source (defaults to the API config)")
+	targetPath := flag.String("target", "", "JSON config file whose storage section is the destination")
+	concurrency := flag.Int("concurrency", 4, "number of objects copied at the same time")
+	batchSize := flag.Int("batch", 100, "number of files read from the database per batch")
+	statePath := flag.String("state", "storage-migrate.state", "checkpoint file used to resume an interrupted run")
+	dryRun := flag.Bool("dry-run", false, "report what would be copied without writing anything")
+	flag.Parse()
+
+	if *targetPath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	appConfig := config.NewConfig()
+	db := config.NewDatabase(appConfig)
+	httpClient := config.NewClient()
+
+	sourceConfig := *appConfig
+	if *sourcePath != "" {
+		storage, err := config.NewStorageConfig(*sourcePath)
+		if err != nil {
+			log.Fatalf("failed to load source storage config: %v", err)
+		}
+		sourceConfig.Storage = *storage
+	}
+
+	targetConfig := *appConfig
+	storage, err := config.NewStorageConfig(*targetPath)
+	if err != nil {
+		log.Fatalf("failed to load target storage config: %v", err)
+	}
+	targetConfig.Storage = *storage
+
+	source := newStorage(&sourceConfig, httpClient)
+	target := newStorage(&targetConfig, httpClient)
+
+	migrator := service.NewStorageMigrator(db, repository.NewFileRepository(), source, target, &sourceConfig, &targetConfig)
+	migrator.Concurrency = *concurrency
+	migrator.BatchSize = *batchSize
+	migrator.StatePath = *statePath
+	migrator.DryRun = *dryRun
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	report, runErr := migrator.Run(ctx)
+	if report != nil {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			log.Printf("failed to write report: %v", err)
+		}
+	}
+	if runErr != nil {
+		log.Fatalf("storage migration stopped: %v", runErr)
+	}
+	if len(report.Failed) > 0 {
+		os.Exit(1)
+	}
+}
+
+func newStorage(cfg *config.Config, httpClient *http.Client) adapter.StorageAdapter {
+	var s3Client *s3.Client
+	if cfg.Storage.Mode == "s3" {
+		var err error
+		s3Client, err = config.NewS3Client(cfg.Storage.S3)
+		if err != nil {
+			log.Fatalf("failed to create s3 client: %v", err)
+		}
+	}
+	return adapter.NewStorageAdapter(cfg, s3Client, httpClient)
+}
@@ -0,0 +1,44 @@
+package adapter
+
+import (
+	"context"
+	"io"
+	"time"
+
+	appConfig "chrononewsapi/internal/config"
+)
+
+type ScanResult struct {
+	Clean     bool
+	Signature string
+}
+
+// ScannerAdapter is implemented by every malware scanner. Scan reads r to the
+// end and returns an error only when no verdict could be reached.
+type ScannerAdapter interface {
+	Scan(ctx context.Context, r io.Reader) (*ScanResult, error)
+}
+
+func NewScannerAdapter(cfg *appConfig.Config) ScannerAdapter {
+	switch cfg.Scanner.Mode {
+	case "clamd":
+		return NewClamdScanner(cfg.Scanner.Clamd.Address, time.Duration(cfg.Scanner.Clamd.Timeout)*time.Second)
+	default:
+		return NewNoopScanner()
+	}
+}
+
+// NoopScanner reports everything as clean. It is used when no scanner is
+// configured.
+type NoopScanner struct{}
+
+func NewNoopScanner() *NoopScanner {
+	return &NoopScanner{}
+}
+
+func (s *NoopScanner) Scan(_ context.Context, r io.Reader) (*ScanResult, error) {
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		return nil, err
+	}
+	return &ScanResult{Clean: true}, nil
+}
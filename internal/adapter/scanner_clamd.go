@@ -0,0 +1,106 @@
+package adapter
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+const clamdChunkSize = 64 << 10
+
+// ClamdScanner streams content to a ClamAV daemon over TCP with the INSTREAM
+// command.
+type ClamdScanner struct {
+	Address string
+	Timeout time.Duration
+}
+
+func NewClamdScanner(address string, timeout time.Duration) *ClamdScanner {
+	if timeout <= 0 {
+		timeout = time.Minute
+	}
+	return &ClamdScanner{Address: address, Timeout: timeout}
+}
+
+func (s *ClamdScanner) Scan(ctx context.Context, r io.Reader) (*ScanResult, error) {
+	dialer := net.Dialer{Timeout: s.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", s.Address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to clamd: %w", err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	deadline := time.Now().Add(s.Timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	// clamd stops reading once a stream passes its StreamMaxLength and replies
+	// with an error, so a failed write is followed by reading that reply.
+	readErr, writeErr := writeClamdStream(conn, r)
+	if readErr != nil {
+		return nil, readErr
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && reply == "" {
+		if writeErr != nil {
+			return nil, fmt.Errorf("failed to send stream to clamd: %w", writeErr)
+		}
+		return nil, fmt.Errorf("failed to read clamd reply: %w", err)
+	}
+	reply = strings.TrimSpace(strings.TrimSuffix(reply, "\x00"))
+
+	switch {
+	case strings.HasSuffix(reply, " FOUND"):
+		signature := strings.TrimSuffix(reply, " FOUND")
+		if index := strings.Index(signature, ": "); index >= 0 {
+			signature = signature[index+2:]
+		}
+		return &ScanResult{Signature: signature}, nil
+	case strings.HasSuffix(reply, " OK") && writeErr == nil:
+		return &ScanResult{Clean: true}, nil
+	case writeErr != nil:
+		return nil, fmt.Errorf("failed to send stream to clamd: %w", writeErr)
+	}
+	return nil, errors.New("clamd: " + reply)
+}
+
+// writeClamdStream returns the error from reading r separately, since clamd
+// has nothing to reply to a stream that was cut short by the source.
+func writeClamdStream(conn net.Conn, r io.Reader) (readErr, writeErr error) {
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 4+clamdChunkSize)
+	for {
+		n, err := r.Read(buf[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(buf, uint32(n))
+			if _, werr := conn.Write(buf[:4+n]); werr != nil {
+				return nil, werr
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err, nil
+		}
+	}
+
+	_, err := conn.Write([]byte{0, 0, 0, 0})
+	return nil, err
+}
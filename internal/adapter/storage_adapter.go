@@ -5,173 +5,85 @@ import (
 	"errors"
 	"io"
 	"log/slog"
-	"mime/multipart"
-	"net/url"
-	"os"
+	"net/http"
 	"path/filepath"
+	"strings"
+	"time"
 
 	appConfig "chrononewsapi/internal/config"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 )
 
-type StorageAdapter struct {
-	mode   string
-	client *s3.Client
-	bucket string
-}
+var (
+	ErrObjectNotFound      = errors.New("storage object not found")
+	ErrPresignNotSupported = errors.New("storage backend does not support presigned requests")
+)
 
-func NewStorageAdapter(cfg *appConfig.Config, s3Client *s3.Client) *StorageAdapter {
-	return &StorageAdapter{
-		mode:   cfg.Storage.Mode,
-		client: s3Client,
-		bucket: cfg.Storage.S3.Bucket,
-	}
+type ObjectInfo struct {
+	Path       string
+	Size       int64
+	ModifiedAt time.Time
 }
 
-func (s *StorageAdapter) Store(file *multipart.FileHeader, path string) error {
-	if s.mode == "s3" {
-		if s.client == nil {
-
-			return errors.New("s3 client is not initialized")
-		}
-		fileOpened, err := file.Open()
-		if err != nil {
-			return err
-		}
-
-		defer func() {
-			if cerr := fileOpened.Close(); cerr != nil {
-				slog.Warn("Error closing S3 file source", "error", cerr)
-			}
-		}()
-
-		s3Key := filepath.ToSlash(path)
-
-		contentType := file.Header.Get("Content-Type")
-		if contentType == "" {
-			contentType = "application/octet-stream"
-		}
-
-		_, err = s.client.PutObject(context.TODO(), &s3.PutObjectInput{
-			Bucket:      aws.String(s.bucket),
-			Key:         aws.String(s3Key),
-			Body:        fileOpened,
-			ContentType: aws.String(contentType),
-		})
-		return err
-	}
-
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return err
-	}
-	fileOpened, err := file.Open()
-	if err != nil {
-		return err
-	}
-
-	defer func() {
-		if cerr := fileOpened.Close(); cerr != nil {
-			slog.Warn("Error closing opened file", "error", cerr)
-		}
-	}()
-
-	fileStored, err := os.Create(path)
-	if err != nil {
-		return err
-	}
-
-	defer func() {
-		if cerr := fileStored.Close(); cerr != nil {
-			slog.Warn("Error closing stored file", "error", cerr)
-		}
-	}()
-
-	_, err = io.Copy(fileStored, fileOpened)
-	if err != nil {
-		_ = os.Remove(path)
-		return err
-	}
-	return nil
+// StorageAdapter is implemented by every storage backend. Paths are the same
+// slash- or OS-separated paths built from the storage folders in the config.
+type StorageAdapter interface {
+	Put(ctx context.Context, path string, body io.Reader, contentType string) error
+	Get(ctx context.Context, path string) (io.ReadCloser, error)
+	Delete(ctx context.Context, path string) error
+	Copy(ctx context.Context, sourcePath, destinationPath string) error
+	Stat(ctx context.Context, path string) (*ObjectInfo, error)
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	PresignPut(ctx context.Context, path string, contentType string, size int64, expires time.Duration) (string, error)
+	PresignGet(ctx context.Context, path string, expires time.Duration) (string, error)
+	URL(path string) string
 }
 
-func (s *StorageAdapter) Delete(path string) error {
-	if s.mode == "s3" {
-		if s.client == nil {
-
-			return errors.New("s3 client is not initialized")
-		}
-		s3Key := filepath.ToSlash(path)
-		_, err := s.client.DeleteObject(context.TODO(), &s3.DeleteObjectInput{
-			Bucket: aws.String(s.bucket),
-			Key:    aws.String(s3Key),
-		})
-		return err
-	}
-
-	err := os.Remove(path)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			slog.Warn("Attempted to delete a non-existent local file", "path", path)
-			return nil
-		}
-		return err
-	}
-
-	return nil
+// RangeReader is implemented by backends that can fetch part of an object
+// without downloading the rest of it.
+type RangeReader interface {
+	GetRange(ctx context.Context, path string, offset, length int64) (io.ReadCloser, error)
 }
 
-func (s *StorageAdapter) Copy(fileName, tempDir, destDir string) error {
-	if s.mode == "s3" {
-		if s.client == nil {
-
-			return errors.New("s3 client is not initialized")
-		}
-		sourceKey := filepath.ToSlash(filepath.Join(tempDir, fileName))
-		destKey := filepath.ToSlash(filepath.Join(destDir, fileName))
-
-		copySource := "/" + s.bucket + "/" + sourceKey
-		encodedCopySource := url.PathEscape(copySource)
+// ObjectKey normalizes a storage path so paths built from the config and paths
+// returned by List can be compared with each other.
+func ObjectKey(path string) string {
+	return strings.TrimLeft(filepath.ToSlash(filepath.Clean(path)), "/")
+}
 
-		_, err := s.client.CopyObject(context.TODO(), &s3.CopyObjectInput{
-			Bucket:     aws.String(s.bucket),
-			Key:        aws.String(destKey),
-			CopySource: aws.String(encodedCopySource),
-		})
-		return err
+func NewStorageAdapter(cfg *appConfig.Config, s3Client *s3.Client, httpClient *http.Client) StorageAdapter {
+	switch cfg.Storage.Mode {
+	case "s3":
+		return NewS3Storage(s3Client, cfg.Storage.S3.Bucket, cfg.Storage.CdnURL)
+	case "webdav":
+		return NewWebDAVStorage(httpClient, cfg.Storage.WebDAV, cfg.Storage.CdnURL)
+	case "memory":
+		return NewMemoryStorage(cfg.Web.BaseURL)
+	default:
+		return NewLocalStorage(cfg.Web.BaseURL)
 	}
+}
 
-	tempPath := filepath.Join(tempDir, fileName)
-	destPath := filepath.Join(destDir, fileName)
-
-	if err := os.MkdirAll(destDir, 0755); err != nil {
-		return err
+// ReadHead returns at most n bytes from the start of an object, using a ranged
+// read when the backend supports one.
+func ReadHead(ctx context.Context, storage StorageAdapter, path string, n int64) ([]byte, error) {
+	var body io.ReadCloser
+	var err error
+	if rangeReader, ok := storage.(RangeReader); ok {
+		body, err = rangeReader.GetRange(ctx, path, 0, n)
+	} else {
+		body, err = storage.Get(ctx, path)
 	}
-
-	tempFile, err := os.Open(tempPath)
-	if err != nil {
-		return err
-	}
-
-	defer func() {
-		if cerr := tempFile.Close(); cerr != nil {
-			slog.Warn("Error closing temp file", "error", cerr)
-		}
-	}()
-
-	destFile, err := os.Create(destPath)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	defer func() {
-		if cerr := destFile.Close(); cerr != nil {
-			slog.Warn("Error closing destination file", "error", cerr)
+		if cerr := body.Close(); cerr != nil {
+			slog.Warn("Error closing storage object", "error", cerr)
 		}
 	}()
 
-	_, err = io.Copy(destFile, tempFile)
-	return err
+	return io.ReadAll(io.LimitReader(body, n))
 }
@@ -0,0 +1,126 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type LocalStorage struct {
+	baseURL string
+}
+
+func NewLocalStorage(baseURL string) *LocalStorage {
+	return &LocalStorage{baseURL: baseURL}
+}
+
+func (s *LocalStorage) Put(_ context.Context, path string, body io.Reader, _ string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	fileStored, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if cerr := fileStored.Close(); cerr != nil {
+			slog.Warn("Error closing stored file", "error", cerr)
+		}
+	}()
+
+	if _, err := io.Copy(fileStored, body); err != nil {
+		_ = os.Remove(path)
+		return err
+	}
+
+	return nil
+}
+
+func (s *LocalStorage) Get(_ context.Context, path string) (io.ReadCloser, error) {
+	fileOpened, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrObjectNotFound
+	}
+	return fileOpened, err
+}
+
+func (s *LocalStorage) Delete(_ context.Context, path string) error {
+	err := os.Remove(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			slog.Warn("Attempted to delete a non-existent local file", "path", path)
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (s *LocalStorage) Copy(ctx context.Context, sourcePath, destinationPath string) error {
+	source, err := s.Get(ctx, sourcePath)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if cerr := source.Close(); cerr != nil {
+			slog.Warn("Error closing source file", "error", cerr)
+		}
+	}()
+
+	return s.Put(ctx, destinationPath, source, "")
+}
+
+func (s *LocalStorage) Stat(_ context.Context, path string) (*ObjectInfo, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrObjectNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ObjectInfo{Path: path, Size: info.Size(), ModifiedAt: info.ModTime()}, nil
+}
+
+func (s *LocalStorage) List(_ context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	err := filepath.WalkDir(prefix, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, ObjectInfo{Path: path, Size: info.Size(), ModifiedAt: info.ModTime()})
+		return nil
+	})
+	return objects, err
+}
+
+func (s *LocalStorage) PresignPut(context.Context, string, string, int64, time.Duration) (string, error) {
+	return "", ErrPresignNotSupported
+}
+
+func (s *LocalStorage) PresignGet(context.Context, string, time.Duration) (string, error) {
+	return "", ErrPresignNotSupported
+}
+
+func (s *LocalStorage) URL(path string) string {
+	return s.baseURL + "/" + strings.TrimLeft(filepath.ToSlash(path), "/")
+}
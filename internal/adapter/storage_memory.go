@@ -0,0 +1,123 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type memoryObject struct {
+	data        []byte
+	contentType string
+	modifiedAt  time.Time
+}
+
+// MemoryStorage keeps objects in process memory. It is meant for tests and
+// loses everything on restart.
+type MemoryStorage struct {
+	baseURL string
+
+	mu      sync.RWMutex
+	objects map[string]memoryObject
+}
+
+func NewMemoryStorage(baseURL string) *MemoryStorage {
+	return &MemoryStorage{
+		baseURL: baseURL,
+		objects: make(map[string]memoryObject),
+	}
+}
+
+func (s *MemoryStorage) Put(_ context.Context, path string, body io.Reader, contentType string) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[ObjectKey(path)] = memoryObject{data: data, contentType: contentType, modifiedAt: time.Now()}
+	return nil
+}
+
+func (s *MemoryStorage) Get(_ context.Context, path string) (io.ReadCloser, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	object, ok := s.objects[ObjectKey(path)]
+	if !ok {
+		return nil, ErrObjectNotFound
+	}
+	return io.NopCloser(bytes.NewReader(object.data)), nil
+}
+
+func (s *MemoryStorage) Delete(_ context.Context, path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.objects, ObjectKey(path))
+	return nil
+}
+
+func (s *MemoryStorage) Copy(_ context.Context, sourcePath, destinationPath string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	object, ok := s.objects[ObjectKey(sourcePath)]
+	if !ok {
+		return ErrObjectNotFound
+	}
+	object.data = bytes.Clone(object.data)
+	object.modifiedAt = time.Now()
+	s.objects[ObjectKey(destinationPath)] = object
+	return nil
+}
+
+func (s *MemoryStorage) Stat(_ context.Context, path string) (*ObjectInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	key := ObjectKey(path)
+	object, ok := s.objects[key]
+	if !ok {
+		return nil, ErrObjectNotFound
+	}
+	return &ObjectInfo{Path: key, Size: int64(len(object.data)), ModifiedAt: object.modifiedAt}, nil
+}
+
+func (s *MemoryStorage) List(_ context.Context, prefix string) ([]ObjectInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keyPrefix := ObjectKey(prefix)
+	if keyPrefix == "." {
+		keyPrefix = ""
+	} else {
+		keyPrefix += "/"
+	}
+
+	var objects []ObjectInfo
+	for key, object := range s.objects {
+		if strings.HasPrefix(key, keyPrefix) {
+			objects = append(objects, ObjectInfo{Path: key, Size: int64(len(object.data)), ModifiedAt: object.modifiedAt})
+		}
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Path < objects[j].Path })
+
+	return objects, nil
+}
+
+func (s *MemoryStorage) PresignPut(context.Context, string, string, int64, time.Duration) (string, error) {
+	return "", ErrPresignNotSupported
+}
+
+func (s *MemoryStorage) PresignGet(context.Context, string, time.Duration) (string, error) {
+	return "", ErrPresignNotSupported
+}
+
+func (s *MemoryStorage) URL(path string) string {
+	return s.baseURL + "/" + ObjectKey(path)
+}
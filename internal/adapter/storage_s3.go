@@ -0,0 +1,209 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+type S3Storage struct {
+	client *s3.Client
+	bucket string
+	cdnURL string
+}
+
+func NewS3Storage(client *s3.Client, bucket string, cdnURL string) *S3Storage {
+	return &S3Storage{
+		client: client,
+		bucket: bucket,
+		cdnURL: cdnURL,
+	}
+}
+
+func (s *S3Storage) Put(ctx context.Context, path string, body io.Reader, contentType string) error {
+	if s.client == nil {
+		return errors.New("s3 client is not initialized")
+	}
+
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(filepath.ToSlash(path)),
+		Body:        body,
+		ContentType: aws.String(contentType),
+	})
+	return err
+}
+
+func (s *S3Storage) Get(ctx context.Context, path string) (io.ReadCloser, error) {
+	return s.get(ctx, path, nil)
+}
+
+func (s *S3Storage) GetRange(ctx context.Context, path string, offset, length int64) (io.ReadCloser, error) {
+	return s.get(ctx, path, aws.String(fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)))
+}
+
+func (s *S3Storage) get(ctx context.Context, path string, byteRange *string) (io.ReadCloser, error) {
+	if s.client == nil {
+		return nil, errors.New("s3 client is not initialized")
+	}
+
+	output, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(filepath.ToSlash(path)),
+		Range:  byteRange,
+	})
+	if err != nil {
+		return nil, s3Error(err)
+	}
+	return output.Body, nil
+}
+
+func (s *S3Storage) Delete(ctx context.Context, path string) error {
+	if s.client == nil {
+		return errors.New("s3 client is not initialized")
+	}
+
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(filepath.ToSlash(path)),
+	})
+	return err
+}
+
+func (s *S3Storage) Copy(ctx context.Context, sourcePath, destinationPath string) error {
+	if s.client == nil {
+		return errors.New("s3 client is not initialized")
+	}
+
+	copySource := "/" + s.bucket + "/" + filepath.ToSlash(sourcePath)
+
+	_, err := s.client.CopyObject(ctx, &s3.CopyObjectInput{
+		Bucket:     aws.String(s.bucket),
+		Key:        aws.String(filepath.ToSlash(destinationPath)),
+		CopySource: aws.String(url.PathEscape(copySource)),
+	})
+	return s3Error(err)
+}
+
+func (s *S3Storage) Stat(ctx context.Context, path string) (*ObjectInfo, error) {
+	if s.client == nil {
+		return nil, errors.New("s3 client is not initialized")
+	}
+
+	output, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(filepath.ToSlash(path)),
+	})
+	if err != nil {
+		return nil, s3Error(err)
+	}
+
+	return &ObjectInfo{
+		Path:       path,
+		Size:       aws.ToInt64(output.ContentLength),
+		ModifiedAt: aws.ToTime(output.LastModified),
+	}, nil
+}
+
+func (s *S3Storage) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	if s.client == nil {
+		return nil, errors.New("s3 client is not initialized")
+	}
+
+	keyPrefix := ObjectKey(prefix)
+	if keyPrefix == "." {
+		keyPrefix = ""
+	} else {
+		keyPrefix += "/"
+	}
+
+	var objects []ObjectInfo
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(keyPrefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, object := range page.Contents {
+			objects = append(objects, ObjectInfo{
+				Path:       aws.ToString(object.Key),
+				Size:       aws.ToInt64(object.Size),
+				ModifiedAt: aws.ToTime(object.LastModified),
+			})
+		}
+	}
+
+	return objects, nil
+}
+
+func (s *S3Storage) PresignPut(ctx context.Context, path string, contentType string, size int64, expires time.Duration) (string, error) {
+	if s.client == nil {
+		return "", errors.New("s3 client is not initialized")
+	}
+
+	request, err := s3.NewPresignClient(s.client).PresignPutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(s.bucket),
+		Key:           aws.String(filepath.ToSlash(path)),
+		ContentType:   aws.String(contentType),
+		ContentLength: aws.Int64(size),
+	}, s3.WithPresignExpires(expires))
+	if err != nil {
+		return "", err
+	}
+	return request.URL, nil
+}
+
+func (s *S3Storage) PresignGet(ctx context.Context, path string, expires time.Duration) (string, error) {
+	if s.client == nil {
+		return "", errors.New("s3 client is not initialized")
+	}
+
+	request, err := s3.NewPresignClient(s.client).PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(filepath.ToSlash(path)),
+	}, s3.WithPresignExpires(expires))
+	if err != nil {
+		return "", err
+	}
+	return request.URL, nil
+}
+
+func (s *S3Storage) URL(path string) string {
+	return s.cdnURL + "/" + strings.TrimLeft(filepath.ToSlash(path), "/")
+}
+
+func s3Error(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var noSuchKey *types.NoSuchKey
+	var notFound *types.NotFound
+	if errors.As(err, &noSuchKey) || errors.As(err, &notFound) {
+		return ErrObjectNotFound
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NotFound" {
+		return ErrObjectNotFound
+	}
+
+	return err
+}
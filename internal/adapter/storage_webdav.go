@@ -0,0 +1,287 @@
+package adapter
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	appConfig "chrononewsapi/internal/config"
+)
+
+// WebDAVStorage stores objects on a WebDAV server (Nextcloud, Apache mod_dav,
+// rclone serve webdav and similar). Public URLs are served from the CDN URL.
+type WebDAVStorage struct {
+	client   *http.Client
+	endpoint string
+	username string
+	password string
+	cdnURL   string
+}
+
+func NewWebDAVStorage(client *http.Client, cfg appConfig.WebDAVConfig, cdnURL string) *WebDAVStorage {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &WebDAVStorage{
+		client:   client,
+		endpoint: strings.TrimRight(cfg.Endpoint, "/"),
+		username: cfg.Username,
+		password: cfg.Password,
+		cdnURL:   cdnURL,
+	}
+}
+
+func (s *WebDAVStorage) objectURL(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return s.endpoint + "/" + strings.Join(segments, "/")
+}
+
+func (s *WebDAVStorage) do(ctx context.Context, method, target string, body io.Reader, header http.Header) (*http.Response, error) {
+	request, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		for _, value := range values {
+			request.Header.Add(key, value)
+		}
+	}
+	if s.username != "" {
+		request.SetBasicAuth(s.username, s.password)
+	}
+	return s.client.Do(request)
+}
+
+func closeWebDAVBody(response *http.Response) {
+	_, _ = io.Copy(io.Discard, response.Body)
+	if err := response.Body.Close(); err != nil {
+		slog.Warn("Error closing WebDAV response body", "error", err)
+	}
+}
+
+func webDAVStatusError(method, key string, response *http.Response) error {
+	if response.StatusCode == http.StatusNotFound {
+		return ErrObjectNotFound
+	}
+	return fmt.Errorf("webdav %s %s: unexpected status %s", method, key, response.Status)
+}
+
+// mkcol creates every parent collection of key. Servers answer 405 for
+// collections that already exist, which is not an error here.
+func (s *WebDAVStorage) mkcol(ctx context.Context, key string) error {
+	parts := strings.Split(key, "/")
+	for i := 1; i < len(parts); i++ {
+		response, err := s.do(ctx, "MKCOL", s.objectURL(strings.Join(parts[:i], "/"))+"/", nil, nil)
+		if err != nil {
+			return err
+		}
+		closeWebDAVBody(response)
+		if response.StatusCode != http.StatusCreated && response.StatusCode != http.StatusMethodNotAllowed && response.StatusCode != http.StatusOK {
+			return webDAVStatusError("MKCOL", key, response)
+		}
+	}
+	return nil
+}
+
+func (s *WebDAVStorage) Put(ctx context.Context, path string, body io.Reader, contentType string) error {
+	key := ObjectKey(path)
+	if err := s.mkcol(ctx, key); err != nil {
+		return err
+	}
+
+	header := http.Header{}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+
+	response, err := s.do(ctx, http.MethodPut, s.objectURL(key), body, header)
+	if err != nil {
+		return err
+	}
+	defer closeWebDAVBody(response)
+
+	if response.StatusCode/100 != 2 {
+		return webDAVStatusError(http.MethodPut, key, response)
+	}
+	return nil
+}
+
+func (s *WebDAVStorage) Get(ctx context.Context, path string) (io.ReadCloser, error) {
+	return s.get(ctx, path, nil)
+}
+
+func (s *WebDAVStorage) GetRange(ctx context.Context, path string, offset, length int64) (io.ReadCloser, error) {
+	header := http.Header{}
+	header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+	return s.get(ctx, path, header)
+}
+
+func (s *WebDAVStorage) get(ctx context.Context, path string, header http.Header) (io.ReadCloser, error) {
+	key := ObjectKey(path)
+	response, err := s.do(ctx, http.MethodGet, s.objectURL(key), nil, header)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusPartialContent {
+		defer closeWebDAVBody(response)
+		return nil, webDAVStatusError(http.MethodGet, key, response)
+	}
+	return response.Body, nil
+}
+
+func (s *WebDAVStorage) Delete(ctx context.Context, path string) error {
+	key := ObjectKey(path)
+	response, err := s.do(ctx, http.MethodDelete, s.objectURL(key), nil, nil)
+	if err != nil {
+		return err
+	}
+	defer closeWebDAVBody(response)
+
+	if response.StatusCode == http.StatusNotFound {
+		slog.Warn("Attempted to delete a non-existent WebDAV object", "path", key)
+		return nil
+	}
+	if response.StatusCode/100 != 2 {
+		return webDAVStatusError(http.MethodDelete, key, response)
+	}
+	return nil
+}
+
+func (s *WebDAVStorage) Copy(ctx context.Context, sourcePath, destinationPath string) error {
+	sourceKey := ObjectKey(sourcePath)
+	destinationKey := ObjectKey(destinationPath)
+	if err := s.mkcol(ctx, destinationKey); err != nil {
+		return err
+	}
+
+	header := http.Header{}
+	header.Set("Destination", s.objectURL(destinationKey))
+	header.Set("Overwrite", "T")
+
+	response, err := s.do(ctx, "COPY", s.objectURL(sourceKey), nil, header)
+	if err != nil {
+		return err
+	}
+	defer closeWebDAVBody(response)
+
+	if response.StatusCode/100 != 2 {
+		return webDAVStatusError("COPY", sourceKey, response)
+	}
+	return nil
+}
+
+func (s *WebDAVStorage) Stat(ctx context.Context, path string) (*ObjectInfo, error) {
+	key := ObjectKey(path)
+	response, err := s.do(ctx, http.MethodHead, s.objectURL(key), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer closeWebDAVBody(response)
+
+	if response.StatusCode != http.StatusOK {
+		return nil, webDAVStatusError(http.MethodHead, key, response)
+	}
+
+	modifiedAt, _ := http.ParseTime(response.Header.Get("Last-Modified"))
+	return &ObjectInfo{Path: key, Size: response.ContentLength, ModifiedAt: modifiedAt}, nil
+}
+
+type webDAVMultistatus struct {
+	Responses []struct {
+		Href         string    `xml:"href"`
+		Collection   *struct{} `xml:"propstat>prop>resourcetype>collection"`
+		Size         int64     `xml:"propstat>prop>getcontentlength"`
+		LastModified string    `xml:"propstat>prop>getlastmodified"`
+	} `xml:"response"`
+}
+
+const webDAVPropfindBody = `<?xml version="1.0" encoding="utf-8"?>
+<propfind xmlns="DAV:"><prop><resourcetype/><getcontentlength/><getlastmodified/></prop></propfind>`
+
+// List walks the collections under prefix with Depth 1 PROPFIND requests,
+// since many servers refuse Depth infinity.
+func (s *WebDAVStorage) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	endpointURL, err := url.Parse(s.endpoint)
+	if err != nil {
+		return nil, err
+	}
+	basePath := strings.TrimRight(endpointURL.Path, "/") + "/"
+
+	var objects []ObjectInfo
+	pending := []string{ObjectKey(prefix)}
+	for len(pending) > 0 {
+		collection := pending[0]
+		pending = pending[1:]
+		if collection == "." {
+			collection = ""
+		}
+
+		header := http.Header{}
+		header.Set("Depth", "1")
+		header.Set("Content-Type", "application/xml")
+
+		target := s.endpoint + "/"
+		if collection != "" {
+			target = s.objectURL(collection) + "/"
+		}
+		response, err := s.do(ctx, "PROPFIND", target, strings.NewReader(webDAVPropfindBody), header)
+		if err != nil {
+			return nil, err
+		}
+		if response.StatusCode == http.StatusNotFound {
+			closeWebDAVBody(response)
+			continue
+		}
+		if response.StatusCode != http.StatusMultiStatus {
+			closeWebDAVBody(response)
+			return nil, webDAVStatusError("PROPFIND", collection, response)
+		}
+
+		var status webDAVMultistatus
+		err = xml.NewDecoder(response.Body).Decode(&status)
+		closeWebDAVBody(response)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range status.Responses {
+			href, err := url.Parse(entry.Href)
+			if err != nil {
+				return nil, err
+			}
+			key := strings.Trim(strings.TrimPrefix(href.Path, basePath), "/")
+			if key == collection {
+				continue
+			}
+			if entry.Collection != nil {
+				pending = append(pending, key)
+				continue
+			}
+			modifiedAt, _ := http.ParseTime(entry.LastModified)
+			objects = append(objects, ObjectInfo{Path: key, Size: entry.Size, ModifiedAt: modifiedAt})
+		}
+	}
+
+	return objects, nil
+}
+
+func (s *WebDAVStorage) PresignPut(context.Context, string, string, int64, time.Duration) (string, error) {
+	return "", ErrPresignNotSupported
+}
+
+func (s *WebDAVStorage) PresignGet(context.Context, string, time.Duration) (string, error) {
+	return "", ErrPresignNotSupported
+}
+
+func (s *WebDAVStorage) URL(path string) string {
+	return s.cdnURL + "/" + ObjectKey(path)
+}
@@ -7,7 +7,9 @@ import (
 	"chrononewsapi/internal/handler/middleware"
 	"chrononewsapi/internal/repository"
 	"chrononewsapi/internal/service"
+	"context"
 	"net/http"
+	"sync"
 
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/go-chi/chi/v5"
@@ -15,26 +17,48 @@ import (
 	"gorm.io/gorm"
 )
 
-func Init(app *chi.Mux, db *gorm.DB, config *config.Config, validator *validator.Validate, httpClient *http.Client, s3Client *s3.Client) {
+// Init wires the application and starts its background workers. They run
+// until ctx is cancelled; the returned WaitGroup is done once they have
+// stopped and flushed what they buffered.
+func Init(ctx context.Context, app *chi.Mux, db *gorm.DB, config *config.Config, validator *validator.Validate, httpClient *http.Client, s3Client *s3.Client) *sync.WaitGroup {
+	workers := &sync.WaitGroup{}
+
 	// Repository
 	userRepository := repository.NewUserRepository()
 	categoryRepository := repository.NewCategoryRepository()
 	fileRepository := repository.NewFileRepository()
 	postRepository := repository.NewPostRepository()
 	resetRepository := repository.NewResetRepository()
+	reviewRepository := repository.NewReviewRepository()
+	frontpageRepository := repository.NewFrontpageRepository()
+	analyticsRepository := repository.NewAnalyticsRepository()
+	statsRepository := repository.NewStatsRepository()
+	uploadUsageRepository := repository.NewUploadUsageRepository()
 
 	// Adapter
-	storageAdapter := adapter.NewStorageAdapter(config, s3Client)
+	storageAdapter := adapter.NewStorageAdapter(config, s3Client, httpClient)
+	scannerAdapter := adapter.NewScannerAdapter(config)
 	captchaAdapter := adapter.NewCaptchaAdapter(httpClient)
 	emailAdapter := adapter.NewEmailAdapter()
 
 	// Service
-	userService := service.NewUserService(db, userRepository, postRepository, fileRepository, resetRepository, storageAdapter, captchaAdapter, emailAdapter, validator, config)
+	uploadQuota := service.NewUploadQuota(fileRepository, userRepository, uploadUsageRepository, config)
+	userService := service.NewUserService(db, userRepository, postRepository, fileRepository, resetRepository, storageAdapter, scannerAdapter, captchaAdapter, emailAdapter, uploadQuota, validator, config)
 	categoryService := service.NewCategoryService(db, categoryRepository, userRepository, postRepository, validator)
-	postService := service.NewPostService(db, postRepository, userRepository, fileRepository, categoryRepository, storageAdapter, validator, config)
+	viewCounter := service.NewViewCounter(db, postRepository, analyticsRepository, config)
+	viewCounter.Start(ctx, workers)
+	postService := service.NewPostService(db, postRepository, userRepository, fileRepository, categoryRepository, storageAdapter, scannerAdapter, viewCounter, validator, config)
 	resetService := service.NewResetService(db, resetRepository, userRepository, emailAdapter, captchaAdapter, validator, config)
-	fileService := service.NewFileService(db, fileRepository, storageAdapter, config, validator)
-	sitemapService := service.NewSitemapService(postRepository, categoryRepository, config)
+	fileService := service.NewFileService(db, fileRepository, userRepository, storageAdapter, scannerAdapter, uploadQuota, config, validator)
+	sitemapService := service.NewSitemapService(postRepository, categoryRepository, userRepository, config)
+	reviewService := service.NewReviewService(db, reviewRepository, postRepository, userRepository, fileRepository, storageAdapter, emailAdapter, validator, config)
+	authorService := service.NewAuthorService(db, userRepository, postService, validator, config)
+	frontpageService := service.NewFrontpageService(db, frontpageRepository, postRepository, userRepository, postService, validator)
+	analyticsService := service.NewAnalyticsService(db, analyticsRepository, postRepository, userRepository, postService, validator, config)
+	analyticsService.StartRollup(ctx, workers)
+	storageReconciler := service.NewStorageReconciler(db, fileRepository, storageAdapter, config)
+	storageReconciler.Start(ctx, workers)
+	adminService := service.NewAdminService(db, statsRepository, userRepository, fileRepository, storageAdapter, storageReconciler, validator, config)
 
 	// Controller
 	userController := controller.NewUserController(userService)
@@ -43,20 +67,34 @@ func Init(app *chi.Mux, db *gorm.DB, config *config.Config, validator *validator
 	resetController := controller.NewResetController(resetService)
 	fileController := controller.NewFileController(fileService)
 	sitemapController := controller.NewSitemapController(sitemapService, db)
+	reviewController := controller.NewReviewController(reviewService)
+	authorController := controller.NewAuthorController(authorService)
+	frontpageController := controller.NewFrontpageController(frontpageService)
+	analyticsController := controller.NewAnalyticsController(analyticsService)
+	adminController := controller.NewAdminController(adminService)
+	mediaController := controller.NewMediaController(fileService)
 
 	// Middleware
 	userMiddleware := middleware.NewUserMiddleware(userService)
 
 	router := Route{
-		App:                app,
-		UserController:     userController,
-		UserMiddleware:     userMiddleware,
-		CategoryController: categoryController,
-		PostController:     postController,
-		ResetController:    resetController,
-		FileController:     fileController,
-		SitemapController:  sitemapController,
-		Config:             config,
+		App:                 app,
+		UserController:      userController,
+		UserMiddleware:      userMiddleware,
+		CategoryController:  categoryController,
+		PostController:      postController,
+		ResetController:     resetController,
+		FileController:      fileController,
+		SitemapController:   sitemapController,
+		ReviewController:    reviewController,
+		AuthorController:    authorController,
+		FrontpageController: frontpageController,
+		AnalyticsController: analyticsController,
+		AdminController:     adminController,
+		MediaController:     mediaController,
+		Config:              config,
 	}
 	router.Setup()
+
+	return workers
 }
@@ -14,15 +14,21 @@ import (
 )
 
 type Route struct {
-	App                *chi.Mux
-	UserMiddleware     *middleware.UserMiddleware
-	UserController     *controller.UserController
-	CategoryController *controller.CategoryController
-	PostController     *controller.PostController
-	ResetController    *controller.ResetController
-	FileController     *controller.FileController
-	SitemapController  *controller.SitemapController
-	Config             *config.Config
+	App                 *chi.Mux
+	UserMiddleware      *middleware.UserMiddleware
+	UserController      *controller.UserController
+	CategoryController  *controller.CategoryController
+	PostController      *controller.PostController
+	ResetController     *controller.ResetController
+	FileController      *controller.FileController
+	SitemapController   *controller.SitemapController
+	ReviewController    *controller.ReviewController
+	AuthorController    *controller.AuthorController
+	FrontpageController *controller.FrontpageController
+	AnalyticsController *controller.AnalyticsController
+	AdminController     *controller.AdminController
+	MediaController     *controller.MediaController
+	Config              *config.Config
 }
 
 func (r *Route) Setup() {
@@ -39,9 +45,14 @@ func (r *Route) Setup() {
 		c.Group(func(guest chi.Router) {
 			guest.Post("/user/login", r.UserController.Login)
 			guest.Get("/post", r.PostController.Search)
+			guest.Get("/post/trending", r.AnalyticsController.Trending)
 			guest.Get("/post/{id}", r.PostController.Get)
 			guest.Patch("/post/{id}/view", r.PostController.IncrementViewCount)
 			guest.Get("/category", r.CategoryController.List)
+			guest.Get("/author/{slug}", r.AuthorController.Get)
+			guest.Get("/frontpage", r.FrontpageController.Get)
+			guest.Put("/file/upload/{token}", r.FileController.DirectUpload)
+			guest.Get("/file/private/{token}", r.FileController.ServePrivate)
 			guest.Post("/reset/request", r.ResetController.RequestResetEmail)
 			guest.Patch("/reset", r.ResetController.Reset)
 		})
@@ -65,14 +76,41 @@ func (r *Route) Setup() {
 			auth.Post("/post", r.PostController.Create)
 			auth.Put("/post/{id}", r.PostController.Update)
 			auth.Delete("/post/{id}", r.PostController.Delete)
+			auth.Get("/post/{id}/preview", r.PostController.Preview)
+			auth.Patch("/post/{id}/placement", r.FrontpageController.UpdatePlacement)
+			auth.Get("/post/{id}/analytics", r.AnalyticsController.PostAnalytics)
+			auth.Put("/frontpage/{name}", r.FrontpageController.UpdateSlot)
+
+			auth.Get("/review", r.ReviewController.Search)
+			auth.Get("/post/{id}/review", r.ReviewController.Get)
+			auth.Post("/post/{id}/review", r.ReviewController.Submit)
+			auth.Patch("/post/{id}/review/editor", r.ReviewController.Assign)
+			auth.Post("/post/{id}/review/comment", r.ReviewController.Comment)
+			auth.Patch("/post/{id}/review/decision", r.ReviewController.Decide)
 
 			auth.Post("/image", r.FileController.UploadImage)
+			auth.Post("/media", r.FileController.UploadMedia)
+			auth.Post("/file/presign", r.FileController.PresignUpload)
+			auth.Post("/file/{id}/confirm", r.FileController.ConfirmUpload)
+			auth.Get("/file", r.FileController.Search)
+			auth.Get("/file/{id}", r.FileController.Get)
+			auth.Patch("/file/{id}/metadata", r.FileController.UpdateMetadata)
+			auth.Patch("/file/{id}/crop", r.FileController.UpdateCrop)
+			auth.Delete("/file/{id}", r.FileController.Delete)
+
+			auth.Get("/admin/stats", r.AdminController.Stats)
+			auth.Get("/admin/file", r.AdminController.ListFiles)
+			auth.Get("/admin/dlq", r.AdminController.ListDeadLetters)
+			auth.Post("/admin/file/requeue", r.AdminController.Requeue)
+			auth.Delete("/admin/file/{id}", r.AdminController.DiscardFile)
+			auth.Post("/admin/storage/reconcile", r.AdminController.ReconcileStorage)
 		})
 	})
 
 	r.App.Get("/sitemap.xml", r.SitemapController.GetSitemapIndex)
 	r.App.Get("/sitemap/posts-{page}.xml", r.SitemapController.GetPostsSitemap)
 	r.App.Get("/sitemap/categories.xml", r.SitemapController.GetCategoriesSitemap)
+	r.App.Get("/sitemap/authors.xml", r.SitemapController.GetAuthorsSitemap)
 
 	if r.Config.Storage.Mode == "local" {
 
@@ -87,7 +125,7 @@ func (r *Route) Setup() {
 			routePattern := fmt.Sprintf("/%s/*", urlPath)
 			prefix := fmt.Sprintf("/%s", urlPath)
 
-			r.App.Handle(routePattern, http.StripPrefix(prefix, http.FileServer(http.Dir(physicalPath))))
+			r.App.Handle(routePattern, http.StripPrefix(prefix, r.MediaController.Serve(physicalPath)))
 		}
 
 		serveStatic(r.Config.Storage.Thumbnail)
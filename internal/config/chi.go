@@ -14,6 +14,12 @@ import (
 
 func NewChi(config *Config) *chi.Mux {
 	r := chi.NewRouter()
+
+	trustedProxies, err := ParseTrustedProxies(config.Web.TrustedProxies)
+	if err != nil {
+		slog.Error("Ignoring invalid trusted proxies", "error", err)
+	}
+	r.Use(TrustedRealIP(trustedProxies))
 	r.Use(slogchi.New(slog.Default()))
 
 	originsStr := config.Web.CorsOrigins
@@ -33,7 +39,7 @@ func NewChi(config *Config) *chi.Mux {
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   allowedOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Visitor-ID"},
 		ExposedHeaders:   []string{"Link"},
 		AllowCredentials: true,
 		MaxAge:           300,
@@ -19,14 +19,16 @@ type ClientPathConfig struct {
 	Category string `mapstructure:"category"`
 	Reset    string `mapstructure:"reset"`
 	Forgot   string `mapstructure:"forgot"`
+	Author   string `mapstructure:"author"`
 }
 
 type WebConfig struct {
-	BaseURL     string           `mapstructure:"base_url"`
-	Port        string           `mapstructure:"port"`
-	CorsOrigins string           `mapstructure:"cors_origins"`
-	ClientURL   string           `mapstructure:"client_url"`
-	ClientPaths ClientPathConfig `mapstructure:"client_paths"`
+	BaseURL        string           `mapstructure:"base_url"`
+	Port           string           `mapstructure:"port"`
+	CorsOrigins    string           `mapstructure:"cors_origins"`
+	TrustedProxies string           `mapstructure:"trusted_proxies"`
+	ClientURL      string           `mapstructure:"client_url"`
+	ClientPaths    ClientPathConfig `mapstructure:"client_paths"`
 }
 
 type DBConfig struct {
@@ -54,15 +56,29 @@ type S3Config struct {
 	AccessKey string `mapstructure:"access_key"`
 	SecretKey string `mapstructure:"secret_key"`
 	Endpoint  string `mapstructure:"endpoint"`
+	PathStyle bool   `mapstructure:"path_style"`
+}
+
+type WebDAVConfig struct {
+	Endpoint string `mapstructure:"endpoint"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
 }
 
 type StorageConfig struct {
-	Mode       string   `mapstructure:"mode"`
-	CdnURL     string   `mapstructure:"cdn_url"`
-	Thumbnail  string   `mapstructure:"thumbnail"`
-	Attachment string   `mapstructure:"attachment"`
-	Profile    string   `mapstructure:"profile"`
-	S3         S3Config `mapstructure:"s3"`
+	Mode              string       `mapstructure:"mode"`
+	CdnURL            string       `mapstructure:"cdn_url"`
+	Thumbnail         string       `mapstructure:"thumbnail"`
+	Attachment        string       `mapstructure:"attachment"`
+	Profile           string       `mapstructure:"profile"`
+	Private           string       `mapstructure:"private"`
+	PrivateURLTTL     int          `mapstructure:"private_url_ttl"`
+	UploadTTL         int          `mapstructure:"upload_ttl"`
+	OrphanGracePeriod int          `mapstructure:"orphan_grace_period"`
+	ReconcileInterval int          `mapstructure:"reconcile_interval"`
+	KeepCopyright     bool         `mapstructure:"keep_copyright"`
+	S3                S3Config     `mapstructure:"s3"`
+	WebDAV            WebDAVConfig `mapstructure:"webdav"`
 }
 
 type ResetConfig struct {
@@ -77,6 +93,38 @@ type SMTPConfig struct {
 	Password string     `mapstructure:"password"`
 }
 
+type ViewConfig struct {
+	FlushInterval   int `mapstructure:"flush_interval"`
+	DedupWindow     int `mapstructure:"dedup_window"`
+	HourlyRetention int `mapstructure:"hourly_retention"`
+}
+
+type ClamdConfig struct {
+	Address string `mapstructure:"address"`
+	Timeout int    `mapstructure:"timeout"`
+}
+
+type ScannerConfig struct {
+	Mode  string      `mapstructure:"mode"`
+	Clamd ClamdConfig `mapstructure:"clamd"`
+}
+
+type QuotaConfig struct {
+	StorageBytes int64 `mapstructure:"storage_bytes"`
+	DailyBytes   int64 `mapstructure:"daily_bytes"`
+}
+
+type UploadConfig struct {
+	Admin      QuotaConfig `mapstructure:"admin"`
+	Journalist QuotaConfig `mapstructure:"journalist"`
+}
+
+type ContentConfig struct {
+	AllowedTags       string `mapstructure:"allowed_tags"`
+	AllowedAttributes string `mapstructure:"allowed_attributes"`
+	AllowedSchemes    string `mapstructure:"allowed_schemes"`
+}
+
 type Config struct {
 	Web     WebConfig     `mapstructure:"web"`
 	DB      DBConfig      `mapstructure:"db"`
@@ -85,6 +133,10 @@ type Config struct {
 	Storage StorageConfig `mapstructure:"storage"`
 	Reset   ResetConfig   `mapstructure:"reset"`
 	SMTP    SMTPConfig    `mapstructure:"smtp"`
+	View    ViewConfig    `mapstructure:"view"`
+	Upload  UploadConfig  `mapstructure:"upload"`
+	Scanner ScannerConfig `mapstructure:"scanner"`
+	Content ContentConfig `mapstructure:"content"`
 }
 
 func NewConfig() *Config {
@@ -92,8 +144,8 @@ func NewConfig() *Config {
 	config.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 
 	envKeys := []string{
-		"web.base_url", "web.port", "web.cors_origins", "web.client_url",
-		"web.client_paths.post", "web.client_paths.category", "web.client_paths.reset", "web.client_paths.forgot",
+		"web.base_url", "web.port", "web.cors_origins", "web.trusted_proxies", "web.client_url",
+		"web.client_paths.post", "web.client_paths.category", "web.client_paths.reset", "web.client_paths.forgot", "web.client_paths.author",
 
 		"db.user", "db.password", "db.host", "db.port", "db.name", "db.sslmode", "db.migration",
 
@@ -101,13 +153,24 @@ func NewConfig() *Config {
 
 		"captcha.secret",
 
-		"storage.mode", "storage.cdn_url", "storage.thumbnail", "storage.attachment", "storage.profile",
-		"storage.s3.bucket", "storage.s3.region", "storage.s3.access_key", "storage.s3.secret_key", "storage.s3.endpoint",
+		"storage.mode", "storage.cdn_url", "storage.thumbnail", "storage.attachment", "storage.profile", "storage.private", "storage.private_url_ttl", "storage.upload_ttl",
+		"storage.orphan_grace_period", "storage.reconcile_interval", "storage.keep_copyright",
+		"storage.s3.bucket", "storage.s3.region", "storage.s3.access_key", "storage.s3.secret_key", "storage.s3.endpoint", "storage.s3.path_style",
+		"storage.webdav.endpoint", "storage.webdav.username", "storage.webdav.password",
 
 		"reset.exp",
 
 		"smtp.host", "smtp.port", "smtp.username", "smtp.password",
 		"smtp.from.name", "smtp.from.email",
+
+		"view.flush_interval", "view.dedup_window", "view.hourly_retention",
+
+		"upload.admin.storage_bytes", "upload.admin.daily_bytes",
+		"upload.journalist.storage_bytes", "upload.journalist.daily_bytes",
+
+		"scanner.mode", "scanner.clamd.address", "scanner.clamd.timeout",
+
+		"content.allowed_tags", "content.allowed_attributes", "content.allowed_schemes",
 	}
 
 	for _, key := range envKeys {
@@ -118,8 +181,24 @@ func NewConfig() *Config {
 	}
 
 	config.SetDefault("storage.mode", "local")
+	config.SetDefault("storage.private", "./storage/private")
+	config.SetDefault("storage.private_url_ttl", 3600)
+	config.SetDefault("storage.upload_ttl", 900)
+	config.SetDefault("storage.orphan_grace_period", 86400)
+	config.SetDefault("storage.reconcile_interval", 0)
+	config.SetDefault("storage.keep_copyright", true)
 	config.SetDefault("db.sslmode", "require")
 	config.SetDefault("db.migration", false)
+	config.SetDefault("web.client_paths.author", "/author")
+	config.SetDefault("view.flush_interval", 10)
+	config.SetDefault("view.dedup_window", 1800)
+	config.SetDefault("view.hourly_retention", 7)
+	config.SetDefault("upload.admin.storage_bytes", 0)
+	config.SetDefault("upload.admin.daily_bytes", 0)
+	config.SetDefault("upload.journalist.storage_bytes", 5368709120)
+	config.SetDefault("upload.journalist.daily_bytes", 524288000)
+	config.SetDefault("scanner.mode", "none")
+	config.SetDefault("scanner.clamd.timeout", 60)
 
 	config.SetConfigName("config")
 	config.SetConfigType("json")
@@ -159,6 +238,9 @@ func validateConfig(cfg *Config) error {
 	if cfg.Web.ClientURL == "" {
 		missingFields = append(missingFields, "web.client_url")
 	}
+	if _, err := ParseTrustedProxies(cfg.Web.TrustedProxies); err != nil {
+		missingFields = append(missingFields, "web.trusted_proxies ("+err.Error()+")")
+	}
 	if cfg.Web.ClientPaths.Post == "" {
 		missingFields = append(missingFields, "web.client_paths.post")
 	}
@@ -199,61 +281,107 @@ func validateConfig(cfg *Config) error {
 		missingFields = append(missingFields, "captcha.secret")
 	}
 
-	if cfg.Storage.Mode == "s3" {
-		if cfg.Storage.S3.Bucket == "" {
+	missingFields = append(missingFields, validateStorageConfig(&cfg.Storage)...)
+
+	if cfg.Scanner.Mode == "clamd" && cfg.Scanner.Clamd.Address == "" {
+		missingFields = append(missingFields, "scanner.clamd.address")
+	} else if cfg.Scanner.Mode != "clamd" && cfg.Scanner.Mode != "none" && cfg.Scanner.Mode != "" {
+		missingFields = append(missingFields, "scanner.mode (must be 'none' or 'clamd')")
+	}
+
+	if cfg.Reset.Exp <= 0 {
+		missingFields = append(missingFields, "reset.exp")
+	}
+
+	if cfg.SMTP.Host == "" {
+		missingFields = append(missingFields, "smtp.host")
+	}
+	if cfg.SMTP.Port <= 0 {
+		missingFields = append(missingFields, "smtp.port")
+	}
+	if cfg.SMTP.From.Name == "" {
+		missingFields = append(missingFields, "smtp.from.name")
+	}
+	if cfg.SMTP.From.Email == "" {
+		missingFields = append(missingFields, "smtp.from.email")
+	}
+
+	if len(missingFields) > 0 {
+		return errors.New("missing required configuration fields: " + strings.Join(missingFields, ", "))
+	}
+
+	return nil
+}
+
+func validateStorageConfig(storage *StorageConfig) []string {
+	var missingFields []string
+
+	if storage.Mode == "s3" {
+		if storage.S3.Bucket == "" {
 			missingFields = append(missingFields, "storage.s3.bucket")
 		}
-		if cfg.Storage.S3.Region == "" {
+		if storage.S3.Region == "" {
 			slog.Warn("storage.s3.region is not set, defaulting to 'auto' for R2 compatibility")
-			cfg.Storage.S3.Region = "auto"
+			storage.S3.Region = "auto"
 		}
-		if cfg.Storage.S3.AccessKey == "" {
+		if storage.S3.AccessKey == "" {
 			missingFields = append(missingFields, "storage.s3.access_key")
 		}
-		if cfg.Storage.S3.SecretKey == "" {
+		if storage.S3.SecretKey == "" {
 			missingFields = append(missingFields, "storage.s3.secret_key")
 		}
-		if cfg.Storage.S3.Endpoint == "" {
+		if storage.S3.Endpoint == "" {
 			missingFields = append(missingFields, "storage.s3.endpoint (required for Cloudflare R2)")
 		}
 
-		if cfg.Storage.CdnURL == "" {
+		if storage.CdnURL == "" {
 			missingFields = append(missingFields, "storage.cdn_url (required for S3 mode)")
 		}
-	} else if cfg.Storage.Mode == "local" {
-		if cfg.Storage.Thumbnail == "" {
+	} else if storage.Mode == "webdav" {
+		if storage.WebDAV.Endpoint == "" {
+			missingFields = append(missingFields, "storage.webdav.endpoint")
+		}
+		if storage.CdnURL == "" {
+			missingFields = append(missingFields, "storage.cdn_url (required for WebDAV mode)")
+		}
+	} else if storage.Mode == "local" || storage.Mode == "memory" {
+		if storage.Thumbnail == "" {
 			missingFields = append(missingFields, "storage.thumbnail")
 		}
-		if cfg.Storage.Attachment == "" {
+		if storage.Attachment == "" {
 			missingFields = append(missingFields, "storage.attachment")
 		}
-		if cfg.Storage.Profile == "" {
+		if storage.Profile == "" {
 			missingFields = append(missingFields, "storage.profile")
 		}
-	} else if cfg.Storage.Mode != "" {
-		missingFields = append(missingFields, "storage.mode (must be 'local' or 's3')")
+	} else if storage.Mode != "" {
+		missingFields = append(missingFields, "storage.mode (must be 'local', 's3', 'webdav' or 'memory')")
 	}
 
-	if cfg.Reset.Exp <= 0 {
-		missingFields = append(missingFields, "reset.exp")
-	}
+	return missingFields
+}
 
-	if cfg.SMTP.Host == "" {
-		missingFields = append(missingFields, "smtp.host")
-	}
-	if cfg.SMTP.Port <= 0 {
-		missingFields = append(missingFields, "smtp.port")
-	}
-	if cfg.SMTP.From.Name == "" {
-		missingFields = append(missingFields, "smtp.from.name")
+// NewStorageConfig reads only the storage section of the JSON config file at
+// path. It is used by tools that move objects between two storage setups.
+func NewStorageConfig(path string) (*StorageConfig, error) {
+	config := viper.New()
+	config.SetConfigFile(path)
+	config.SetConfigType("json")
+	config.SetDefault("storage.mode", "local")
+	config.SetDefault("storage.private", "./storage/private")
+
+	if err := config.ReadInConfig(); err != nil {
+		return nil, err
 	}
-	if cfg.SMTP.From.Email == "" {
-		missingFields = append(missingFields, "smtp.from.email")
+
+	var storage StorageConfig
+	if err := config.UnmarshalKey("storage", &storage); err != nil {
+		return nil, err
 	}
 
-	if len(missingFields) > 0 {
-		return errors.New("missing required configuration fields: " + strings.Join(missingFields, ", "))
+	if missingFields := validateStorageConfig(&storage); len(missingFields) > 0 {
+		return nil, errors.New("missing required storage configuration fields: " + strings.Join(missingFields, ", "))
 	}
 
-	return nil
+	return &storage, nil
 }
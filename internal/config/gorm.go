@@ -83,6 +83,25 @@ func Migrate(ctx context.Context, db *gorm.DB) error {
 		return err
 	}
 
+	if err := tx.Exec(`ALTER TYPE file_status ADD VALUE IF NOT EXISTS 'uploading' BEFORE 'pending';`).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Exec(`ALTER TYPE file_status ADD VALUE IF NOT EXISTS 'private' BEFORE 'pending';`).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Exec(`
+    DO $$
+    BEGIN
+        IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'post_status') THEN
+            CREATE TYPE post_status AS ENUM ('draft','in_review','changes_requested','rejected','published');
+        END IF;
+    END $$;
+	`).Error; err != nil {
+		return err
+	}
+
 	entities := []interface{}{
 		&entity.User{},
 		&entity.Post{},
@@ -91,6 +110,13 @@ func Migrate(ctx context.Context, db *gorm.DB) error {
 		&entity.Reset{},
 		&entity.DeadLetterQueue{},
 		&entity.SourceFileToDelete{},
+		&entity.PostAuthor{},
+		&entity.Review{},
+		&entity.ReviewComment{},
+		&entity.ReviewEvent{},
+		&entity.FrontpageSlot{},
+		&entity.PostViewBucket{},
+		&entity.UploadUsage{},
 	}
 
 	for _, e := range entities {
@@ -99,6 +125,14 @@ func Migrate(ctx context.Context, db *gorm.DB) error {
 		}
 	}
 
+	if err := tx.Exec(`
+    UPDATE "user"
+    SET slug = COALESCE(NULLIF(TRIM(BOTH '-' FROM REGEXP_REPLACE(LOWER(name), '[^a-z0-9]+', '-', 'g')), ''), 'author') || '-' || id
+    WHERE slug IS NULL;
+	`).Error; err != nil {
+		return err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return err
 	}
@@ -0,0 +1,200 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"io"
+	"net/http"
+)
+
+// The standard library and the modules available to this project have no
+// WebP or AVIF decoder. These formats are registered with config readers only,
+// so dimensions can be checked while decoding them reports an error.
+var errDecodeNotSupported = errors.New("decoding this image format is not supported")
+
+func init() {
+	image.RegisterFormat("webp", "RIFF????WEBPVP8", decodeUnsupported, decodeWebPConfig)
+	image.RegisterFormat("avif", "????ftypavif", decodeUnsupported, decodeAVIFConfig)
+}
+
+func decodeUnsupported(io.Reader) (image.Image, error) {
+	return nil, errDecodeNotSupported
+}
+
+// DetectImageType works like http.DetectContentType but also recognizes AVIF.
+func DetectImageType(head []byte) string {
+	if len(head) >= 12 && string(head[4:8]) == "ftyp" && (string(head[8:12]) == "avif" || string(head[8:12]) == "avis") {
+		return "image/avif"
+	}
+	return http.DetectContentType(head)
+}
+
+func decodeWebPConfig(r io.Reader) (image.Config, error) {
+	header := make([]byte, 30)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return image.Config{}, err
+	}
+
+	chunk, data := string(header[12:16]), header[20:]
+	switch {
+	case chunk == "VP8 " && data[3] == 0x9D && data[4] == 0x01 && data[5] == 0x2A:
+		return image.Config{
+			ColorModel: color.YCbCrModel,
+			Width:      int(binary.LittleEndian.Uint16(data[6:]) & 0x3FFF),
+			Height:     int(binary.LittleEndian.Uint16(data[8:]) & 0x3FFF),
+		}, nil
+	case chunk == "VP8L" && data[0] == 0x2F:
+		bits := binary.LittleEndian.Uint32(data[1:])
+		return image.Config{
+			ColorModel: color.NRGBAModel,
+			Width:      int(bits&0x3FFF) + 1,
+			Height:     int(bits>>14&0x3FFF) + 1,
+		}, nil
+	case chunk == "VP8X":
+		return image.Config{
+			ColorModel: color.NRGBAModel,
+			Width:      int(uint32(data[4])|uint32(data[5])<<8|uint32(data[6])<<16) + 1,
+			Height:     int(uint32(data[7])|uint32(data[8])<<8|uint32(data[9])<<16) + 1,
+		}, nil
+	}
+	return image.Config{}, errors.New("invalid webp header")
+}
+
+// decodeAVIFConfig reads the size from the image spatial extents property. The
+// largest extent is used, since grid images also describe each tile.
+func decodeAVIFConfig(r io.Reader) (image.Config, error) {
+	data, err := io.ReadAll(io.LimitReader(bufio.NewReader(r), 1<<20))
+	if err != nil {
+		return image.Config{}, err
+	}
+
+	config := image.Config{ColorModel: color.NRGBAModel}
+	var walk func(boxes []byte)
+	walk = func(boxes []byte) {
+		for len(boxes) >= 8 {
+			size := int(binary.BigEndian.Uint32(boxes))
+			boxType := string(boxes[4:8])
+			if size < 8 || size > len(boxes) {
+				return
+			}
+			body := boxes[8:size]
+			switch boxType {
+			case "meta":
+				if len(body) >= 4 {
+					walk(body[4:])
+				}
+			case "iprp", "ipco":
+				walk(body)
+			case "ispe":
+				if len(body) >= 12 {
+					config.Width = max(config.Width, int(binary.BigEndian.Uint32(body[4:])))
+					config.Height = max(config.Height, int(binary.BigEndian.Uint32(body[8:])))
+				}
+			}
+			boxes = boxes[size:]
+		}
+	}
+	walk(data)
+
+	if config.Width == 0 || config.Height == 0 {
+		return image.Config{}, errors.New("invalid avif header")
+	}
+	return config, nil
+}
+
+// ImageFrames returns the number of animation frames in a GIF, APNG or WebP
+// image, or 1 for still images. Malformed data counts as a single frame; it is
+// rejected by the decoder instead.
+func ImageFrames(data []byte) int {
+	var frames int
+	switch {
+	case bytes.HasPrefix(data, []byte("GIF8")):
+		frames = gifFrames(data)
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		frames = apngFrames(data)
+	case len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WEBP":
+		frames = webpFrames(data)
+	}
+	return max(frames, 1)
+}
+
+func gifFrames(data []byte) int {
+	if len(data) < 13 {
+		return 0
+	}
+	offset := 13
+	if data[10]&0x80 != 0 {
+		offset += 3 << (data[10]&0x07 + 1)
+	}
+
+	skipSubBlocks := func() bool {
+		for offset < len(data) {
+			size := int(data[offset])
+			offset += 1 + size
+			if size == 0 {
+				return true
+			}
+		}
+		return false
+	}
+
+	frames := 0
+	for offset < len(data) {
+		switch data[offset] {
+		case 0x21:
+			offset += 2
+			if !skipSubBlocks() {
+				return frames
+			}
+		case 0x2C:
+			frames++
+			if offset+10 > len(data) {
+				return frames
+			}
+			packed := data[offset+9]
+			offset += 10
+			if packed&0x80 != 0 {
+				offset += 3 << (packed&0x07 + 1)
+			}
+			offset++
+			if !skipSubBlocks() {
+				return frames
+			}
+		default:
+			return frames
+		}
+	}
+	return frames
+}
+
+func apngFrames(data []byte) int {
+	for offset := 8; offset+12 <= len(data); {
+		length := int(binary.BigEndian.Uint32(data[offset:]))
+		chunkType := string(data[offset+4 : offset+8])
+		if chunkType == "acTL" && length >= 8 && offset+16 <= len(data) {
+			return int(binary.BigEndian.Uint32(data[offset+8:]))
+		}
+		if chunkType == "IDAT" || length < 0 || offset+12+length > len(data) {
+			return 0
+		}
+		offset += 12 + length
+	}
+	return 0
+}
+
+func webpFrames(data []byte) int {
+	frames := 0
+	for offset := 12; offset+8 <= len(data); {
+		size := int(binary.LittleEndian.Uint32(data[offset+4:]))
+		if string(data[offset:offset+4]) == "ANMF" {
+			frames++
+		}
+		offset += 8 + size + size%2
+	}
+	return frames
+}
@@ -24,6 +24,8 @@ func NewS3Client(cfg S3Config) (*s3.Client, error) {
 		if cfg.Endpoint != "" {
 			o.BaseEndpoint = aws.String(cfg.Endpoint)
 		}
+		// MinIO and most other self-hosted S3 stand-ins only serve path-style URLs.
+		o.UsePathStyle = cfg.PathStyle
 	})
 
 	return client, nil
@@ -1,16 +1,18 @@
 package config
 
 import (
+	"bytes"
 	"image"
 	_ "image/jpeg"
 	_ "image/png"
+	"io"
 	"log/slog"
 	"mime/multipart"
-	"net/http"
 	"os"
 	"path/filepath"
 	"reflect"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -52,10 +54,26 @@ func ExclusiveOr(fl validator.FieldLevel) bool {
 	return true
 }
 
-func Image(fl validator.FieldLevel) bool {
+// imageFormats lists the extensions and sniffed content types accepted for
+// each format name that can appear in an image validation policy.
+var imageFormats = map[string]struct {
+	extensions   []string
+	contentTypes []string
+}{
+	"png":  {[]string{".png"}, []string{"image/png", "image/apng"}},
+	"jpeg": {[]string{".jpg", ".jpeg", ".jpe", ".jfif", ".jif", ".jfi"}, []string{"image/jpeg", "image/pjpeg"}},
+	"gif":  {[]string{".gif"}, []string{"image/gif"}},
+	"webp": {[]string{".webp"}, []string{"image/webp"}},
+	"avif": {[]string{".avif"}, []string{"image/avif"}},
+}
 
-	allowedExtensions := []string{".png", ".jpg", ".jpeg", ".jpe", ".jfif", ".jif", ".jfi"}
-	allowedContentTypes := []string{"image/png", "image/jpeg", "image/pjpeg", "image/apng"}
+// Image validates an uploaded image against a policy written as
+// width_height_size[_formats[_frames]], for example 1200_675_2_png+jpeg+webp_1.
+// The size is in megabytes, formats are joined with "+" and frames limits the
+// animation length. Without a format list only PNG and JPEG are accepted, and
+// without a frame limit only still images are.
+func Image(fl validator.FieldLevel) bool {
+	allowedFormats := []string{"png", "jpeg"}
 	var defaultMaxSize int64 = 2
 
 	defaultMaxWidth, defaultMaxHeight := 800, 800
@@ -63,6 +81,7 @@ func Image(fl validator.FieldLevel) bool {
 	params := fl.Param()
 	maxWidth, maxHeight := defaultMaxWidth, defaultMaxHeight
 	maxSize := defaultMaxSize
+	maxFrames := 1
 
 	if params != "" {
 		parts := strings.Split(params, "_")
@@ -73,11 +92,19 @@ func Image(fl validator.FieldLevel) bool {
 			if h, err := strconv.Atoi(parts[1]); err == nil {
 				maxHeight = h
 			}
-			if len(parts) == 3 {
+			if len(parts) >= 3 {
 				if s, err := strconv.ParseInt(parts[2], 10, 64); err == nil {
 					maxSize = s
 				}
 			}
+			if len(parts) >= 4 && parts[3] != "" {
+				allowedFormats = strings.Split(parts[3], "+")
+			}
+			if len(parts) >= 5 {
+				if f, err := strconv.Atoi(parts[4]); err == nil {
+					maxFrames = f
+				}
+			}
 		}
 	}
 
@@ -90,13 +117,11 @@ func Image(fl validator.FieldLevel) bool {
 		return false
 	}
 
-	extension := filepath.Ext(file.Filename)
-	for i, allowedExtension := range allowedExtensions {
-		if extension == allowedExtension {
-			break
-		} else if i == len(allowedExtensions)-1 {
-			return false
-		}
+	extension := strings.ToLower(filepath.Ext(file.Filename))
+	if !slices.ContainsFunc(allowedFormats, func(format string) bool {
+		return slices.Contains(imageFormats[format].extensions, extension)
+	}) {
+		return false
 	}
 
 	fileOpened, err := file.Open()
@@ -111,32 +136,29 @@ func Image(fl validator.FieldLevel) bool {
 		}
 	}(fileOpened)
 
-	fileHeader := make([]byte, 512)
-	if _, err := fileOpened.Read(fileHeader); err != nil {
-		slog.Error("Failed to read file header for image validation", "err", err)
+	data, err := io.ReadAll(io.LimitReader(fileOpened, maxSize*1024*1024+1))
+	if err != nil {
+		slog.Error("Failed to read file for image validation", "err", err)
 		return false
 	}
 
-	contentType := http.DetectContentType(fileHeader)
-	for i, allowedContentType := range allowedContentTypes {
-		if contentType == allowedContentType {
-			break
-		} else if i == len(allowedContentTypes)-1 {
-			return false
-		}
+	contentType := DetectImageType(data)
+	if !slices.ContainsFunc(allowedFormats, func(format string) bool {
+		return slices.Contains(imageFormats[format].contentTypes, contentType)
+	}) {
+		return false
 	}
 
-	if _, err := fileOpened.Seek(0, 0); err != nil {
-		slog.Error("Failed to seek file for image validation", "err", err)
+	img, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || !slices.Contains(allowedFormats, format) {
 		return false
 	}
 
-	img, _, err := image.DecodeConfig(fileOpened)
-	if err != nil {
+	if img.Width > maxWidth || img.Height > maxHeight {
 		return false
 	}
 
-	if img.Width > maxWidth || img.Height > maxHeight {
+	if ImageFrames(data) > maxFrames {
 		return false
 	}
 
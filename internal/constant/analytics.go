@@ -0,0 +1,6 @@
+package constant
+
+const (
+	BucketHour = "hour"
+	BucketDay  = "day"
+)
@@ -0,0 +1,7 @@
+package constant
+
+const (
+	AuthorRoleReporter     = "reporter"
+	AuthorRolePhotographer = "photographer"
+	AuthorRoleEditor       = "editor"
+)
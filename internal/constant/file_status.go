@@ -1,5 +1,10 @@
 package constant
 
 const (
-	FileStatusPending string = "pending"
+	FileStatusUploading  string = "uploading"
+	FileStatusPrivate    string = "private"
+	FileStatusPending    string = "pending"
+	FileStatusProcessing string = "processing"
+	FileStatusCompressed string = "compressed"
+	FileStatusFailed     string = "failed"
 )
@@ -0,0 +1,13 @@
+package constant
+
+const (
+	FrontpageSlotHero      = "hero"
+	FrontpageSlotSecondary = "secondary"
+	FrontpageSlotHighlight = "highlight"
+)
+
+var FrontpageSlots = []string{
+	FrontpageSlotHero,
+	FrontpageSlotSecondary,
+	FrontpageSlotHighlight,
+}
@@ -0,0 +1,14 @@
+package constant
+
+const (
+	ImageVariantOriginal = "original"
+	ImageFormatWebP      = "webp"
+	ImageSizes           = "(max-width: 768px) 100vw, 768px"
+)
+
+// ImageCropRatios are the aspect ratios crop hints are computed for.
+var ImageCropRatios = map[string][2]int{
+	"16:9": {16, 9},
+	"1:1":  {1, 1},
+	"4:5":  {4, 5},
+}
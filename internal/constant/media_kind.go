@@ -0,0 +1,8 @@
+package constant
+
+const (
+	MediaKindImage    string = "image"
+	MediaKindDocument string = "document"
+	MediaKindAudio    string = "audio"
+	MediaKindVideo    string = "video"
+)
@@ -0,0 +1,15 @@
+package constant
+
+const (
+	PostStatusDraft            = "draft"
+	PostStatusInReview         = "in_review"
+	PostStatusChangesRequested = "changes_requested"
+	PostStatusRejected         = "rejected"
+	PostStatusPublished        = "published"
+)
+
+const (
+	ReviewDecisionApprove        = "approve"
+	ReviewDecisionReject         = "reject"
+	ReviewDecisionRequestChanges = "request_changes"
+)
@@ -0,0 +1,7 @@
+package constant
+
+const (
+	ScanStatusPending  string = "pending"
+	ScanStatusClean    string = "clean"
+	ScanStatusInfected string = "infected"
+)
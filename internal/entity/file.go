@@ -1,18 +1,55 @@
 package entity
 
 type File struct {
-	ID             int32   `gorm:"column:id;primaryKey;type:integer;autoIncrement;not null"`
-	CreatedAt      int64   `gorm:"column:created_at;autoCreateTime:unixtime"`
-	UpdatedAt      int64   `gorm:"column:updated_at;autoCreateTime:unixtime;autoUpdateTime:unixtime"`
-	Name           string  `gorm:"column:name;type:varchar(255);index"`
-	Type           string  `gorm:"column:type;type:file_type;default:'attachment';index"`
-	Status         string  `gorm:"column:status;type:file_status;default:'pending';index"`
-	FailedAttempts int     `gorm:"column:failed_attempts;default:0"`
-	LastError      *string `gorm:"column:last_error;type:varchar(255)"`
-	UsedByPostID   *int32  `gorm:"column:used_by_post_id;index"`
-	Post           *Post   `gorm:"foreignKey:UsedByPostID"`
-	UsedByUserID   *int32  `gorm:"column:used_by_user_id;index"`
-	User           *User   `gorm:"foreignKey:UsedByUserID"`
+	ID             int32         `gorm:"column:id;primaryKey;type:integer;autoIncrement;not null"`
+	CreatedAt      int64         `gorm:"column:created_at;autoCreateTime:unixtime"`
+	UpdatedAt      int64         `gorm:"column:updated_at;autoCreateTime:unixtime;autoUpdateTime:unixtime"`
+	Name           string        `gorm:"column:name;type:varchar(255);index"`
+	Type           string        `gorm:"column:type;type:file_type;default:'attachment';index"`
+	Status         string        `gorm:"column:status;type:file_status;default:'pending';index"`
+	FailedAttempts int           `gorm:"column:failed_attempts;default:0"`
+	LastError      *string       `gorm:"column:last_error;type:varchar(255)"`
+	UsedByPostID   *int32        `gorm:"column:used_by_post_id;index"`
+	Post           *Post         `gorm:"foreignKey:UsedByPostID"`
+	UsedByUserID   *int32        `gorm:"column:used_by_user_id;index"`
+	User           *User         `gorm:"foreignKey:UsedByUserID"`
+	UnlinkedAt     *int64        `gorm:"column:unlinked_at;index"`
+	UploadedByID   *int32        `gorm:"column:uploaded_by_id;index"`
+	Uploader       *User         `gorm:"foreignKey:UploadedByID;constraint:OnDelete:SET NULL"`
+	AltText        string        `gorm:"column:alt_text;type:varchar(500)"`
+	Caption        string        `gorm:"column:caption;type:varchar(1000)"`
+	Credit         string        `gorm:"column:credit;type:varchar(255)"`
+	License        string        `gorm:"column:license;type:varchar(100)"`
+	Size           int64         `gorm:"column:size;default:0"`
+	Hash           string        `gorm:"column:hash;type:varchar(64);index"`
+	Width          int           `gorm:"column:width;default:0"`
+	Height         int           `gorm:"column:height;default:0"`
+	Variants       []FileVariant `gorm:"column:variants;type:jsonb;serializer:json"`
+	Kind           string        `gorm:"column:kind;type:varchar(20);default:'image';index"`
+	MimeType       string        `gorm:"column:mime_type;type:varchar(100)"`
+	Duration       float64       `gorm:"column:duration;default:0"`
+	Pages          int           `gorm:"column:pages;default:0"`
+	FocalX         *float64      `gorm:"column:focal_x"`
+	FocalY         *float64      `gorm:"column:focal_y"`
+	Crop           *FileCrop     `gorm:"column:crop;type:jsonb;serializer:json"`
+	ScanStatus     string        `gorm:"column:scan_status;type:varchar(20);default:'clean';index"`
+}
+
+// FileVariant is a resized or re-encoded copy written next to the original by
+// the image processor.
+type FileVariant struct {
+	Name   string `json:"name"`
+	Width  int    `json:"width"`
+	Format string `json:"format"`
+	File   string `json:"file"`
+}
+
+// FileCrop is the part of the original, in pixels, an editor chose to keep.
+type FileCrop struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
 }
 
 func (File) TableName() string {
@@ -0,0 +1,13 @@
+package entity
+
+type FrontpageSlot struct {
+	ID       int32  `gorm:"column:id;primaryKey;type:integer;autoIncrement;not null"`
+	Name     string `gorm:"column:name;type:varchar(50);not null;uniqueIndex:idx_frontpage_slot_name_post"`
+	PostID   int32  `gorm:"column:post_id;type:integer;not null;uniqueIndex:idx_frontpage_slot_name_post"`
+	Post     Post   `gorm:"foreignKey:PostID;constraint:OnDelete:CASCADE"`
+	Position int    `gorm:"column:position;type:integer;not null;default:0"`
+}
+
+func (FrontpageSlot) TableName() string {
+	return "frontpage_slot"
+}
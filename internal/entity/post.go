@@ -1,18 +1,22 @@
 package entity
 
 type Post struct {
-	ID         int32    `gorm:"column:id;primaryKey;type:integer;autoIncrement;not null"`
-	UserID     int32    `gorm:"column:user_id;type:integer;not null"`
-	User       User     `gorm:"foreignKey:UserID"`
-	CategoryID int32    `gorm:"column:category_id;type:integer;not null"`
-	Category   Category `gorm:"foreignKey:CategoryID"`
-	Files      []File   `gorm:"foreignKey:UsedByPostID;constraint:OnDelete:SET NULL"`
-	Title      string   `gorm:"column:title;type:varchar(255);not null"`
-	Summary    string   `gorm:"column:summary;type:varchar(1000);not null"`
-	Content    string   `gorm:"column:content;type:text"`
-	CreatedAt  int64    `gorm:"column:created_at;autoCreateTime:unixtime"`
-	UpdatedAt  int64    `gorm:"column:updated_at;autoCreateTime:unixtime;autoUpdateTime:unixtime"`
-	ViewCount  int64    `gorm:"column:view_count;type:integer;default:0;not null"`
+	ID            int32        `gorm:"column:id;primaryKey;type:integer;autoIncrement;not null"`
+	UserID        int32        `gorm:"column:user_id;type:integer;not null"`
+	User          User         `gorm:"foreignKey:UserID"`
+	CategoryID    int32        `gorm:"column:category_id;type:integer;not null"`
+	Category      Category     `gorm:"foreignKey:CategoryID"`
+	Files         []File       `gorm:"foreignKey:UsedByPostID;constraint:OnDelete:SET NULL"`
+	Authors       []PostAuthor `gorm:"foreignKey:PostID;constraint:OnDelete:CASCADE"`
+	Title         string       `gorm:"column:title;type:varchar(255);not null"`
+	Summary       string       `gorm:"column:summary;type:varchar(1000);not null"`
+	Content       string       `gorm:"column:content;type:text"`
+	CreatedAt     int64        `gorm:"column:created_at;autoCreateTime:unixtime"`
+	UpdatedAt     int64        `gorm:"column:updated_at;autoCreateTime:unixtime;autoUpdateTime:unixtime"`
+	ViewCount     int64        `gorm:"column:view_count;type:integer;default:0;not null"`
+	Status        string       `gorm:"column:status;type:post_status;default:'published';not null;index"`
+	PinnedAt      *int64       `gorm:"column:pinned_at;type:bigint;index"`
+	BreakingUntil *int64       `gorm:"column:breaking_until;type:bigint;index"`
 }
 
 func (Post) TableName() string {
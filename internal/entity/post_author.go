@@ -0,0 +1,14 @@
+package entity
+
+type PostAuthor struct {
+	ID       int32   `gorm:"column:id;primaryKey;type:integer;autoIncrement;not null"`
+	PostID   int32   `gorm:"column:post_id;type:integer;not null;uniqueIndex:idx_post_author_post_user"`
+	UserID   int32   `gorm:"column:user_id;type:integer;not null;uniqueIndex:idx_post_author_post_user;index"`
+	User     User    `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Position int     `gorm:"column:position;type:integer;not null;default:0"`
+	Role     *string `gorm:"column:role;type:varchar(50)"`
+}
+
+func (PostAuthor) TableName() string {
+	return "post_author"
+}
@@ -0,0 +1,16 @@
+package entity
+
+type PostViewBucket struct {
+	ID          int32  `gorm:"column:id;primaryKey;type:integer;autoIncrement;not null"`
+	PostID      int32  `gorm:"column:post_id;type:integer;not null;uniqueIndex:idx_post_view_bucket_key"`
+	Post        Post   `gorm:"foreignKey:PostID;constraint:OnDelete:CASCADE"`
+	CategoryID  int32  `gorm:"column:category_id;type:integer;not null;index:idx_post_view_bucket_category"`
+	Granularity string `gorm:"column:granularity;type:varchar(10);not null;uniqueIndex:idx_post_view_bucket_key"`
+	BucketStart int64  `gorm:"column:bucket_start;type:bigint;not null;uniqueIndex:idx_post_view_bucket_key;index:idx_post_view_bucket_category;index"`
+	Referrer    string `gorm:"column:referrer;type:varchar(255);not null;default:'';uniqueIndex:idx_post_view_bucket_key"`
+	Views       int64  `gorm:"column:views;type:bigint;not null;default:0"`
+}
+
+func (PostViewBucket) TableName() string {
+	return "post_view_bucket"
+}
@@ -0,0 +1,48 @@
+package entity
+
+type Review struct {
+	ID          int32           `gorm:"column:id;primaryKey;type:integer;autoIncrement;not null"`
+	PostID      int32           `gorm:"column:post_id;type:integer;not null;uniqueIndex"`
+	Post        Post            `gorm:"foreignKey:PostID;constraint:OnDelete:CASCADE"`
+	SubmitterID *int32          `gorm:"column:submitter_id;type:integer;index"`
+	Submitter   *User           `gorm:"foreignKey:SubmitterID;constraint:OnDelete:SET NULL"`
+	EditorID    *int32          `gorm:"column:editor_id;type:integer;index"`
+	Editor      *User           `gorm:"foreignKey:EditorID;constraint:OnDelete:SET NULL"`
+	Comments    []ReviewComment `gorm:"foreignKey:ReviewID;constraint:OnDelete:CASCADE"`
+	Events      []ReviewEvent   `gorm:"foreignKey:ReviewID;constraint:OnDelete:CASCADE"`
+	CreatedAt   int64           `gorm:"column:created_at;autoCreateTime:unixtime"`
+	UpdatedAt   int64           `gorm:"column:updated_at;autoCreateTime:unixtime;autoUpdateTime:unixtime"`
+}
+
+func (Review) TableName() string {
+	return "review"
+}
+
+type ReviewComment struct {
+	ID        int32  `gorm:"column:id;primaryKey;type:integer;autoIncrement;not null"`
+	ReviewID  int32  `gorm:"column:review_id;type:integer;not null;index"`
+	UserID    *int32 `gorm:"column:user_id;type:integer;index"`
+	User      *User  `gorm:"foreignKey:UserID;constraint:OnDelete:SET NULL"`
+	Anchor    string `gorm:"column:anchor;type:varchar(1000)"`
+	Body      string `gorm:"column:body;type:text;not null"`
+	CreatedAt int64  `gorm:"column:created_at;autoCreateTime:unixtime"`
+}
+
+func (ReviewComment) TableName() string {
+	return "review_comment"
+}
+
+type ReviewEvent struct {
+	ID         int32  `gorm:"column:id;primaryKey;type:integer;autoIncrement;not null"`
+	ReviewID   int32  `gorm:"column:review_id;type:integer;not null;index"`
+	ActorID    *int32 `gorm:"column:actor_id;type:integer;index"`
+	Actor      *User  `gorm:"foreignKey:ActorID;constraint:OnDelete:SET NULL"`
+	FromStatus string `gorm:"column:from_status;type:post_status;not null"`
+	ToStatus   string `gorm:"column:to_status;type:post_status;not null"`
+	Note       string `gorm:"column:note;type:text"`
+	CreatedAt  int64  `gorm:"column:created_at;autoCreateTime:unixtime"`
+}
+
+func (ReviewEvent) TableName() string {
+	return "review_event"
+}
@@ -0,0 +1,12 @@
+package entity
+
+type UploadUsage struct {
+	UserID int32 `gorm:"column:user_id;type:integer;primaryKey;not null"`
+	User   User  `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Day    int64 `gorm:"column:day;type:bigint;primaryKey;not null"`
+	Bytes  int64 `gorm:"column:bytes;type:bigint;not null;default:0"`
+}
+
+func (UploadUsage) TableName() string {
+	return "upload_usage"
+}
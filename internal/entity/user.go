@@ -1,13 +1,17 @@
 package entity
 
 type User struct {
-	ID          int32  `gorm:"column:id;primaryKey;type:integer;autoIncrement;not null"`
-	Name        string `gorm:"type:varchar(255);not null;column:name"`
-	PhoneNumber string `gorm:"type:varchar(20);not null;column:phone_number"`
-	Email       string `gorm:"type:varchar(255);unique;not null;column:email"`
-	Password    string `gorm:"type:varchar(255);column:password"`
-	Role        string `gorm:"type:user_type;not null;column:role"`
-	Files       []File `gorm:"foreignKey:UsedByUserID;constraint:OnDelete:SET NULL"`
+	ID          int32             `gorm:"column:id;primaryKey;type:integer;autoIncrement;not null"`
+	Name        string            `gorm:"type:varchar(255);not null;column:name"`
+	PhoneNumber string            `gorm:"type:varchar(20);not null;column:phone_number"`
+	Email       string            `gorm:"type:varchar(255);unique;not null;column:email"`
+	Password    string            `gorm:"type:varchar(255);column:password"`
+	Role        string            `gorm:"type:user_type;not null;column:role"`
+	Slug        *string           `gorm:"type:varchar(255);uniqueIndex;column:slug"`
+	JobTitle    string            `gorm:"type:varchar(100);column:job_title"`
+	Bio         string            `gorm:"type:text;column:bio"`
+	SocialLinks map[string]string `gorm:"type:jsonb;serializer:json;column:social_links"`
+	Files       []File            `gorm:"foreignKey:UsedByUserID;constraint:OnDelete:SET NULL"`
 }
 
 func (User) TableName() string {
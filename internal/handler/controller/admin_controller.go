@@ -0,0 +1,238 @@
+package controller
+
+import (
+	"chrononewsapi/internal/constant"
+	"chrononewsapi/internal/model"
+	"chrononewsapi/internal/service"
+	"chrononewsapi/internal/utility"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type AdminController struct {
+	AdminService *service.AdminService
+}
+
+func NewAdminController(adminService *service.AdminService) *AdminController {
+	return &AdminController{AdminService: adminService}
+}
+
+// Stats handles retrieving dashboard statistics
+// @Summary Get admin statistics
+// @Description Aggregated post, view and storage statistics for the admin dashboard. Results are cached for a minute
+// @Tags Admin
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param startDate query int false "Start of the posts-per-author range (timestamp), defaults to 30 days before endDate"
+// @Param endDate query int false "End of the posts-per-author range (timestamp), defaults to now"
+// @Success 200 {object} utility.ResponseSuccess{data=model.AdminStatsResponse}
+// @Failure 400 {object} utility.ResponseError
+// @Failure 403 {object} utility.ResponseError
+// @Failure 500 {object} utility.ResponseError
+// @Router /api/admin/stats [get]
+func (c *AdminController) Stats(w http.ResponseWriter, r *http.Request) {
+	auth := r.Context().Value("auth").(*model.Auth)
+
+	startDate, err := utility.ToInt64(r.URL.Query().Get("startDate"))
+	if err != nil {
+		startDate = 0
+	}
+	endDate, err := utility.ToInt64(r.URL.Query().Get("endDate"))
+	if err != nil {
+		endDate = 0
+	}
+
+	request := &model.AdminStatsGet{
+		StartDate: startDate,
+		EndDate:   endDate,
+	}
+
+	response, err := c.AdminService.Stats(r.Context(), request, auth)
+	if err != nil {
+		utility.HandleError(w, err)
+		return
+	}
+
+	utility.CreateSuccessResponse(w, http.StatusOK, response)
+}
+
+// ListFiles handles listing files for the image-processing admin view
+// @Summary List files
+// @Description List files with their processing status, last error and the post or user using them
+// @Tags Admin
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param status query string false "Filter by status (uploading, private, pending, processing, compressed, failed)" default(failed)
+// @Param type query string false "Filter by type (thumbnail, attachment, profile)"
+// @Param page query int false "Page number" default(1)
+// @Param size query int false "Page size" default(20)
+// @Success 200 {object} utility.PaginationResponse{data=[]model.AdminFileResponse,pagination=model.Pagination}
+// @Failure 400 {object} utility.ResponseError
+// @Failure 403 {object} utility.ResponseError
+// @Failure 500 {object} utility.ResponseError
+// @Router /api/admin/file [get]
+func (c *AdminController) ListFiles(w http.ResponseWriter, r *http.Request) {
+	auth := r.Context().Value("auth").(*model.Auth)
+
+	page, err := utility.ToInt64(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	size, err := utility.ToInt64(r.URL.Query().Get("size"))
+	if err != nil || size < 1 {
+		size = 20
+	}
+
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = constant.FileStatusFailed
+	}
+
+	request := &model.AdminFileSearch{
+		Status: status,
+		Type:   r.URL.Query().Get("type"),
+		Page:   page,
+		Size:   size,
+	}
+
+	response, pagination, err := c.AdminService.ListFiles(r.Context(), request, auth)
+	if err != nil {
+		utility.HandleError(w, err)
+		return
+	}
+
+	utility.CreateSuccessResponseWithPagination(w, http.StatusOK, response, pagination)
+}
+
+// ListDeadLetters handles listing the image-processing dead letter queue
+// @Summary List dead letter queue
+// @Description List files that exhausted their processing attempts, newest first
+// @Tags Admin
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param page query int false "Page number" default(1)
+// @Param size query int false "Page size" default(20)
+// @Success 200 {object} utility.PaginationResponse{data=[]model.DeadLetterResponse,pagination=model.Pagination}
+// @Failure 403 {object} utility.ResponseError
+// @Failure 500 {object} utility.ResponseError
+// @Router /api/admin/dlq [get]
+func (c *AdminController) ListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	auth := r.Context().Value("auth").(*model.Auth)
+
+	page, err := utility.ToInt64(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	size, err := utility.ToInt64(r.URL.Query().Get("size"))
+	if err != nil || size < 1 {
+		size = 20
+	}
+
+	request := &model.AdminDeadLetterSearch{
+		Page: page,
+		Size: size,
+	}
+
+	response, pagination, err := c.AdminService.ListDeadLetters(r.Context(), request, auth)
+	if err != nil {
+		utility.HandleError(w, err)
+		return
+	}
+
+	utility.CreateSuccessResponseWithPagination(w, http.StatusOK, response, pagination)
+}
+
+// Requeue handles sending files back to the image processor
+// @Summary Requeue files
+// @Description Reset the given files to pending with zero failed attempts and remove their dead letter entries
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param request body model.AdminFileRequeue true "File IDs"
+// @Success 200 {object} utility.ResponseSuccess{data=model.AdminFileRequeueResponse}
+// @Failure 400 {object} utility.ResponseError
+// @Failure 403 {object} utility.ResponseError
+// @Failure 500 {object} utility.ResponseError
+// @Router /api/admin/file/requeue [post]
+func (c *AdminController) Requeue(w http.ResponseWriter, r *http.Request) {
+	auth := r.Context().Value("auth").(*model.Auth)
+
+	request := new(model.AdminFileRequeue)
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		slog.Error("Failed to decode file requeue request", "error", err)
+		utility.CreateErrorResponse(w, utility.ErrBadRequest.Code, utility.ErrBadRequest.Message)
+		return
+	}
+
+	response, err := c.AdminService.Requeue(r.Context(), request, auth)
+	if err != nil {
+		utility.HandleError(w, err)
+		return
+	}
+
+	utility.CreateSuccessResponse(w, http.StatusOK, response)
+}
+
+// DiscardFile handles permanently removing a file
+// @Summary Discard a file
+// @Description Permanently delete a file record, its dead letter entries and its stored objects
+// @Tags Admin
+// @Produce json
+// @Param id path int true "File ID"
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {object} utility.ResponseSuccess
+// @Failure 400 {object} utility.ResponseError
+// @Failure 403 {object} utility.ResponseError
+// @Failure 404 {object} utility.ResponseError
+// @Failure 500 {object} utility.ResponseError
+// @Router /api/admin/file/{id} [delete]
+func (c *AdminController) DiscardFile(w http.ResponseWriter, r *http.Request) {
+	auth := r.Context().Value("auth").(*model.Auth)
+	id, err := utility.ToInt32(chi.URLParam(r, "id"))
+	if err != nil {
+		slog.Error("Failed to parse file ID from URL", "error", err)
+		utility.CreateErrorResponse(w, utility.ErrBadRequest.Code, utility.ErrBadRequest.Message)
+		return
+	}
+
+	request := new(model.AdminFileDelete)
+	request.ID = id
+
+	if err := c.AdminService.DiscardFile(r.Context(), request, auth); err != nil {
+		utility.HandleError(w, err)
+		return
+	}
+
+	utility.CreateSuccessResponse(w, http.StatusOK, "File discarded successfully")
+}
+
+// ReconcileStorage handles comparing file records with stored objects
+// @Summary Reconcile storage
+// @Description Report files whose object is missing, and remove stored objects without a file record and files no post or user uses once they are older than the grace period
+// @Tags Admin
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param dryRun query bool false "Only report, do not delete anything"
+// @Success 200 {object} utility.ResponseSuccess{data=model.StorageReconcileReport}
+// @Failure 403 {object} utility.ResponseError
+// @Failure 500 {object} utility.ResponseError
+// @Router /api/admin/storage/reconcile [post]
+func (c *AdminController) ReconcileStorage(w http.ResponseWriter, r *http.Request) {
+	auth := r.Context().Value("auth").(*model.Auth)
+
+	request := &model.AdminStorageReconcile{
+		DryRun: r.URL.Query().Get("dryRun") == "true",
+	}
+
+	response, err := c.AdminService.ReconcileStorage(r.Context(), request, auth)
+	if err != nil {
+		utility.HandleError(w, err)
+		return
+	}
+
+	utility.CreateSuccessResponse(w, http.StatusOK, response)
+}
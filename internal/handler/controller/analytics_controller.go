@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"chrononewsapi/internal/model"
+	"chrononewsapi/internal/service"
+	"chrononewsapi/internal/utility"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type AnalyticsController struct {
+	AnalyticsService *service.AnalyticsService
+}
+
+func NewAnalyticsController(analyticsService *service.AnalyticsService) *AnalyticsController {
+	return &AnalyticsController{AnalyticsService: analyticsService}
+}
+
+// Trending handles listing the most viewed posts in a recent window
+// @Summary Trending posts
+// @Description List published posts ordered by views within the given window
+// @Tags Post
+// @Produce json
+// @Param window query string false "Time window, e.g. 1h, 6h, 24h, 7d (max 30d)" default(24h)
+// @Param size query int false "Number of posts (max 50)" default(10)
+// @Success 200 {object} utility.ResponseSuccess{data=[]model.TrendingPostResponse}
+// @Failure 400 {object} utility.ResponseError
+// @Failure 500 {object} utility.ResponseError
+// @Router /api/post/trending [get]
+func (c *AnalyticsController) Trending(w http.ResponseWriter, r *http.Request) {
+	window := r.URL.Query().Get("window")
+	if window == "" {
+		window = "24h"
+	}
+	size, err := utility.ToInt64(r.URL.Query().Get("size"))
+	if err != nil {
+		size = 10
+	}
+
+	request := &model.PostTrending{
+		Window: window,
+		Size:   size,
+	}
+
+	response, err := c.AnalyticsService.Trending(r.Context(), request)
+	if err != nil {
+		utility.HandleError(w, err)
+		return
+	}
+
+	utility.CreateSuccessResponse(w, http.StatusOK, response)
+}
+
+// PostAnalytics handles retrieving view analytics of a post
+// @Summary Get post analytics
+// @Description Views over time, top referrers and the category average for a post. Available to the post's authors and admins
+// @Tags Post
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "Post ID"
+// @Param granularity query string false "Series granularity: hour, day" default(day)
+// @Param from query int false "Start of the range (unix timestamp), defaults to 30 days before to"
+// @Param to query int false "End of the range (unix timestamp), defaults to now"
+// @Success 200 {object} utility.ResponseSuccess{data=model.PostAnalyticsResponse}
+// @Failure 400 {object} utility.ResponseError
+// @Failure 404 {object} utility.ResponseError
+// @Failure 500 {object} utility.ResponseError
+// @Router /api/post/{id}/analytics [get]
+func (c *AnalyticsController) PostAnalytics(w http.ResponseWriter, r *http.Request) {
+	auth := r.Context().Value("auth").(*model.Auth)
+
+	id, err := utility.ToInt32(chi.URLParam(r, "id"))
+	if err != nil {
+		slog.Error("Failed to parse post ID from URL", "error", err)
+		utility.CreateErrorResponse(w, utility.ErrBadRequest.Code, utility.ErrBadRequest.Message)
+		return
+	}
+	from, err := utility.ToInt64(r.URL.Query().Get("from"))
+	if err != nil {
+		from = 0
+	}
+	to, err := utility.ToInt64(r.URL.Query().Get("to"))
+	if err != nil {
+		to = 0
+	}
+
+	request := &model.PostAnalyticsGet{
+		ID:          id,
+		Granularity: r.URL.Query().Get("granularity"),
+		From:        from,
+		To:          to,
+	}
+
+	response, err := c.AnalyticsService.PostAnalytics(r.Context(), request, auth)
+	if err != nil {
+		utility.HandleError(w, err)
+		return
+	}
+
+	utility.CreateSuccessResponse(w, http.StatusOK, response)
+}
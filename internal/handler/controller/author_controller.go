@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"chrononewsapi/internal/model"
+	"chrononewsapi/internal/service"
+	"chrononewsapi/internal/utility"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type AuthorController struct {
+	AuthorService *service.AuthorService
+}
+
+func NewAuthorController(authorService *service.AuthorService) *AuthorController {
+	return &AuthorController{AuthorService: authorService}
+}
+
+// Get handles retrieving a public author profile
+// @Summary Get author profile
+// @Description Retrieve an author's public profile together with their published posts
+// @Tags Author
+// @Produce json
+// @Param slug path string true "Author slug"
+// @Param page query int false "Page number" default(1)
+// @Param size query int false "Page size" default(10)
+// @Success 200 {object} utility.PaginationResponse{data=model.AuthorResponse,pagination=model.Pagination}
+// @Failure 400 {object} utility.ResponseError
+// @Failure 404 {object} utility.ResponseError
+// @Failure 500 {object} utility.ResponseError
+// @Router /api/author/{slug} [get]
+func (c *AuthorController) Get(w http.ResponseWriter, r *http.Request) {
+	page, err := utility.ToInt64(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	size, err := utility.ToInt64(r.URL.Query().Get("size"))
+	if err != nil || size < 1 {
+		size = 10
+	}
+
+	request := &model.AuthorGet{
+		Slug: chi.URLParam(r, "slug"),
+		Page: page,
+		Size: size,
+	}
+
+	response, pagination, err := c.AuthorService.Get(r.Context(), request)
+	if err != nil {
+		utility.HandleError(w, err)
+		return
+	}
+
+	utility.CreateSuccessResponseWithPagination(w, http.StatusOK, response, pagination)
+}
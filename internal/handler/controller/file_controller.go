@@ -1,10 +1,16 @@
 package controller
 
 import (
+	"chrononewsapi/internal/model"
 	"chrononewsapi/internal/service"
 	"chrononewsapi/internal/utility"
+	"encoding/json"
+	"errors"
+	"io"
 	"log/slog"
 	"net/http"
+
+	"github.com/go-chi/chi/v5"
 )
 
 type FileController struct {
@@ -26,9 +32,14 @@ func NewFileController(fileService *service.FileService) *FileController {
 // @Success 201 {object} utility.ResponseSuccess{data=model.ImageUploadResponse}
 // @Failure 400 {object} utility.ResponseError
 // @Failure 413 {object} utility.ResponseError
+// @Failure 422 {object} utility.ResponseError
+// @Failure 429 {object} utility.ResponseError
 // @Failure 500 {object} utility.ResponseError
+// @Failure 503 {object} utility.ResponseError
 // @Router /api/image [post]
 func (c *FileController) UploadImage(w http.ResponseWriter, r *http.Request) {
+	auth := r.Context().Value("auth").(*model.Auth)
+
 	_, fileHeader, err := r.FormFile("image")
 	if err != nil {
 		slog.Error("Failed to get file from form", "error", err)
@@ -36,7 +47,54 @@ func (c *FileController) UploadImage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response, err := c.FileService.UploadImage(r.Context(), fileHeader)
+	response, err := c.FileService.UploadImage(r.Context(), fileHeader, auth)
+	if err != nil {
+		utility.HandleError(w, err)
+		return
+	}
+
+	utility.CreateSuccessResponse(w, http.StatusCreated, response)
+}
+
+// UploadMedia handles document, audio and video uploads from the editor
+// @Summary Upload a document, audio clip or video
+// @Description Upload a PDF, audio or video file to embed in post content with an <a>, <audio> or <video> element carrying its data-id. Documents may be up to 20 MB, audio up to 50 MB and video up to 200 MB
+// @Tags File
+// @Accept multipart/form-data
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param file formData file true "Media file to upload"
+// @Success 201 {object} utility.ResponseSuccess{data=model.MediaUploadResponse}
+// @Failure 400 {object} utility.ResponseError
+// @Failure 413 {object} utility.ResponseError
+// @Failure 422 {object} utility.ResponseError
+// @Failure 429 {object} utility.ResponseError
+// @Failure 500 {object} utility.ResponseError
+// @Failure 503 {object} utility.ResponseError
+// @Router /api/media [post]
+func (c *FileController) UploadMedia(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 201*1024*1024)
+	if err := r.ParseMultipartForm(32 * 1024 * 1024); err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			utility.CreateErrorResponse(w, utility.ErrRequestEntityTooLarge.Code, utility.ErrRequestEntityTooLarge.Message)
+			return
+		}
+		slog.Error("Failed to parse media upload form", "error", err)
+		utility.CreateErrorResponse(w, utility.ErrBadRequest.Code, utility.ErrBadRequest.Message)
+		return
+	}
+
+	auth := r.Context().Value("auth").(*model.Auth)
+
+	_, fileHeader, err := r.FormFile("file")
+	if err != nil {
+		slog.Error("Failed to get file from form", "error", err)
+		utility.CreateErrorResponse(w, utility.ErrBadRequest.Code, "Media file is required")
+		return
+	}
+
+	response, err := c.FileService.UploadMedia(r.Context(), fileHeader, auth)
 	if err != nil {
 		utility.HandleError(w, err)
 		return
@@ -44,3 +102,344 @@ func (c *FileController) UploadImage(w http.ResponseWriter, r *http.Request) {
 
 	utility.CreateSuccessResponse(w, http.StatusCreated, response)
 }
+
+// PresignUpload handles the first step of a direct upload
+// @Summary Request a presigned upload URL
+// @Description Reserve a file record and return a URL the client can PUT the image to directly. Call the confirm endpoint once the upload finishes
+// @Tags File
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param request body model.FilePresign true "Declared file name, content type and size in bytes"
+// @Success 201 {object} utility.ResponseSuccess{data=model.FilePresignResponse}
+// @Failure 400 {object} utility.ResponseError
+// @Failure 413 {object} utility.ResponseError
+// @Failure 429 {object} utility.ResponseError
+// @Failure 500 {object} utility.ResponseError
+// @Router /api/file/presign [post]
+func (c *FileController) PresignUpload(w http.ResponseWriter, r *http.Request) {
+	auth := r.Context().Value("auth").(*model.Auth)
+
+	request := new(model.FilePresign)
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		slog.Error("Failed to decode presign request", "error", err)
+		utility.CreateErrorResponse(w, utility.ErrBadRequest.Code, utility.ErrBadRequest.Message)
+		return
+	}
+
+	response, err := c.FileService.PresignUpload(r.Context(), request, auth)
+	if err != nil {
+		utility.HandleError(w, err)
+		return
+	}
+
+	utility.CreateSuccessResponse(w, http.StatusCreated, response)
+}
+
+// ConfirmUpload handles the second step of a direct upload
+// @Summary Confirm a presigned upload
+// @Description Check the uploaded object's size, type and dimensions and queue it for processing. Rejected uploads are deleted
+// @Tags File
+// @Produce json
+// @Param id path int true "File ID"
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {object} utility.ResponseSuccess{data=model.ImageUploadResponse}
+// @Failure 400 {object} utility.ResponseError
+// @Failure 404 {object} utility.ResponseError
+// @Failure 409 {object} utility.ResponseError
+// @Failure 422 {object} utility.ResponseError
+// @Failure 500 {object} utility.ResponseError
+// @Failure 503 {object} utility.ResponseError
+// @Router /api/file/{id}/confirm [post]
+func (c *FileController) ConfirmUpload(w http.ResponseWriter, r *http.Request) {
+	auth := r.Context().Value("auth").(*model.Auth)
+	id, err := utility.ToInt32(chi.URLParam(r, "id"))
+	if err != nil {
+		slog.Error("Failed to parse file ID from URL", "error", err)
+		utility.CreateErrorResponse(w, utility.ErrBadRequest.Code, utility.ErrBadRequest.Message)
+		return
+	}
+
+	request := new(model.FileConfirm)
+	request.ID = id
+
+	response, err := c.FileService.ConfirmUpload(r.Context(), request, auth)
+	if err != nil {
+		utility.HandleError(w, err)
+		return
+	}
+
+	utility.CreateSuccessResponse(w, http.StatusOK, response)
+}
+
+// DirectUpload handles presigned PUTs when storage is local
+// @Summary Upload to a presigned URL (local storage)
+// @Description Receives the body for a presigned upload URL issued while storage mode is local. The token in the path authorises the request
+// @Tags File
+// @Accept octet-stream
+// @Produce json
+// @Param token path string true "Upload token"
+// @Success 200 {object} utility.ResponseSuccess
+// @Failure 400 {object} utility.ResponseError
+// @Failure 403 {object} utility.ResponseError
+// @Failure 500 {object} utility.ResponseError
+// @Router /api/file/upload/{token} [put]
+func (c *FileController) DirectUpload(w http.ResponseWriter, r *http.Request) {
+	request := &model.FileDirectUpload{
+		Token:         chi.URLParam(r, "token"),
+		ContentType:   r.Header.Get("Content-Type"),
+		ContentLength: r.ContentLength,
+	}
+
+	if err := c.FileService.DirectUpload(r.Context(), request, r.Body); err != nil {
+		utility.HandleError(w, err)
+		return
+	}
+
+	utility.CreateSuccessResponse(w, http.StatusOK, "File uploaded successfully")
+}
+
+// ServePrivate streams an unpublished file for a signed URL
+// @Summary Download a private file
+// @Description Returns an unpublished file for a signed URL issued while storage cannot presign reads. The token in the path authorises the request and expires
+// @Tags File
+// @Produce octet-stream
+// @Param token path string true "Media token"
+// @Success 200 {file} binary
+// @Failure 400 {object} utility.ResponseError
+// @Failure 403 {object} utility.ResponseError
+// @Failure 404 {object} utility.ResponseError
+// @Router /api/file/private/{token} [get]
+func (c *FileController) ServePrivate(w http.ResponseWriter, r *http.Request) {
+	request := &model.FilePrivate{
+		Token: chi.URLParam(r, "token"),
+	}
+
+	body, contentType, err := c.FileService.OpenPrivate(r.Context(), request)
+	if err != nil {
+		utility.HandleError(w, err)
+		return
+	}
+	defer func() {
+		_ = body.Close()
+	}()
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, body); err != nil {
+		slog.Warn("Failed to write private file", "error", err)
+	}
+}
+
+// Search handles browsing the media library
+// @Summary Search files
+// @Description Browse uploaded files. Journalists only see their own uploads, admins see everything
+// @Tags File
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param page query int false "Page number" default(0)
+// @Param size query int false "Page size" default(20)
+// @Param type query string false "Filter by type (thumbnail, attachment, profile)"
+// @Param kind query string false "Filter by kind (image, document, audio, video)"
+// @Param status query string false "Filter by status (uploading, private, pending, processing, compressed, failed)"
+// @Param uploadedBy query int false "Filter by uploader ID (admin only)"
+// @Param linked query bool false "Only files used (true) or not used (false) by a post or user"
+// @Param startDate query int false "Filter files uploaded after this date (timestamp)"
+// @Param endDate query int false "Filter files uploaded before this date (timestamp)"
+// @Success 200 {object} utility.PaginationResponse{data=[]model.FileResponse,pagination=model.Pagination}
+// @Failure 400 {object} utility.ResponseError
+// @Failure 500 {object} utility.ResponseError
+// @Router /api/file [get]
+func (c *FileController) Search(w http.ResponseWriter, r *http.Request) {
+	auth := r.Context().Value("auth").(*model.Auth)
+
+	page, err := utility.ToInt64(r.URL.Query().Get("page"))
+	if err != nil {
+		page = 0
+	}
+	size, err := utility.ToInt64(r.URL.Query().Get("size"))
+	if err != nil {
+		size = 20
+	}
+	uploadedBy, err := utility.ToInt32(r.URL.Query().Get("uploadedBy"))
+	if err != nil {
+		uploadedBy = 0
+	}
+	startDate, err := utility.ToInt64(r.URL.Query().Get("startDate"))
+	if err != nil {
+		startDate = 0
+	}
+	endDate, err := utility.ToInt64(r.URL.Query().Get("endDate"))
+	if err != nil {
+		endDate = 0
+	}
+
+	request := &model.FileSearch{
+		Type:       r.URL.Query().Get("type"),
+		Kind:       r.URL.Query().Get("kind"),
+		Status:     r.URL.Query().Get("status"),
+		UploadedBy: uploadedBy,
+		Linked:     r.URL.Query().Get("linked"),
+		StartDate:  startDate,
+		EndDate:    endDate,
+		Page:       page,
+		Size:       size,
+	}
+
+	files, pagination, err := c.FileService.Search(r.Context(), request, auth)
+	if err != nil {
+		utility.HandleError(w, err)
+		return
+	}
+
+	utility.CreateSuccessResponseWithPagination(w, http.StatusOK, files, pagination)
+}
+
+// Get handles getting a single file from the media library
+// @Summary Get a file by ID
+// @Description Retrieve a file with its uploader and the post or user using it
+// @Tags File
+// @Produce json
+// @Param id path int true "File ID"
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {object} utility.ResponseSuccess{data=model.FileResponse}
+// @Failure 400 {object} utility.ResponseError
+// @Failure 404 {object} utility.ResponseError
+// @Failure 500 {object} utility.ResponseError
+// @Router /api/file/{id} [get]
+func (c *FileController) Get(w http.ResponseWriter, r *http.Request) {
+	auth := r.Context().Value("auth").(*model.Auth)
+	id, err := utility.ToInt32(chi.URLParam(r, "id"))
+	if err != nil {
+		slog.Error("Failed to parse file ID from URL", "error", err)
+		utility.CreateErrorResponse(w, utility.ErrBadRequest.Code, utility.ErrBadRequest.Message)
+		return
+	}
+
+	request := new(model.FileGet)
+	request.ID = id
+
+	response, err := c.FileService.Get(r.Context(), request, auth)
+	if err != nil {
+		utility.HandleError(w, err)
+		return
+	}
+
+	utility.CreateSuccessResponse(w, http.StatusOK, response)
+}
+
+// UpdateMetadata handles editing the descriptive fields of a file
+// @Summary Update file metadata
+// @Description Set alt text, caption, credit and license. They are applied to images in post content when it is served
+// @Tags File
+// @Accept json
+// @Produce json
+// @Param id path int true "File ID"
+// @Param Authorization header string true "Bearer token"
+// @Param request body model.FileMetadataUpdate true "File metadata"
+// @Success 200 {object} utility.ResponseSuccess{data=model.FileResponse}
+// @Failure 400 {object} utility.ResponseError
+// @Failure 404 {object} utility.ResponseError
+// @Failure 500 {object} utility.ResponseError
+// @Router /api/file/{id}/metadata [patch]
+func (c *FileController) UpdateMetadata(w http.ResponseWriter, r *http.Request) {
+	auth := r.Context().Value("auth").(*model.Auth)
+	id, err := utility.ToInt32(chi.URLParam(r, "id"))
+	if err != nil {
+		slog.Error("Failed to parse file ID from URL", "error", err)
+		utility.CreateErrorResponse(w, utility.ErrBadRequest.Code, utility.ErrBadRequest.Message)
+		return
+	}
+
+	request := new(model.FileMetadataUpdate)
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		slog.Error("Failed to decode file metadata request", "error", err)
+		utility.CreateErrorResponse(w, utility.ErrBadRequest.Code, utility.ErrBadRequest.Message)
+		return
+	}
+	request.ID = id
+
+	response, err := c.FileService.UpdateMetadata(r.Context(), request, auth)
+	if err != nil {
+		utility.HandleError(w, err)
+		return
+	}
+
+	utility.CreateSuccessResponse(w, http.StatusOK, response)
+}
+
+// UpdateCrop handles setting the crop hints of an image
+// @Summary Update image crop hints
+// @Description Set the focal point (fractions of the width and height) and the crop rectangle (pixels of the original) used to compute the 16:9, 1:1 and 4:5 crops returned with the file and its post. Fields left out are cleared
+// @Tags File
+// @Accept json
+// @Produce json
+// @Param id path int true "File ID"
+// @Param Authorization header string true "Bearer token"
+// @Param request body model.FileCropUpdate true "Focal point and crop rectangle"
+// @Success 200 {object} utility.ResponseSuccess{data=model.FileResponse}
+// @Failure 400 {object} utility.ResponseError
+// @Failure 404 {object} utility.ResponseError
+// @Failure 409 {object} utility.ResponseError
+// @Failure 500 {object} utility.ResponseError
+// @Router /api/file/{id}/crop [patch]
+func (c *FileController) UpdateCrop(w http.ResponseWriter, r *http.Request) {
+	auth := r.Context().Value("auth").(*model.Auth)
+	id, err := utility.ToInt32(chi.URLParam(r, "id"))
+	if err != nil {
+		slog.Error("Failed to parse file ID from URL", "error", err)
+		utility.CreateErrorResponse(w, utility.ErrBadRequest.Code, utility.ErrBadRequest.Message)
+		return
+	}
+
+	request := new(model.FileCropUpdate)
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		slog.Error("Failed to decode file crop request", "error", err)
+		utility.CreateErrorResponse(w, utility.ErrBadRequest.Code, utility.ErrBadRequest.Message)
+		return
+	}
+	request.ID = id
+
+	response, err := c.FileService.UpdateCrop(r.Context(), request, auth)
+	if err != nil {
+		utility.HandleError(w, err)
+		return
+	}
+
+	utility.CreateSuccessResponse(w, http.StatusOK, response)
+}
+
+// Delete handles deleting an unused file
+// @Summary Delete a file
+// @Description Delete a file that is not used by any post or user
+// @Tags File
+// @Produce json
+// @Param id path int true "File ID"
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {object} utility.ResponseSuccess
+// @Failure 400 {object} utility.ResponseError
+// @Failure 404 {object} utility.ResponseError
+// @Failure 409 {object} utility.ResponseError
+// @Failure 500 {object} utility.ResponseError
+// @Router /api/file/{id} [delete]
+func (c *FileController) Delete(w http.ResponseWriter, r *http.Request) {
+	auth := r.Context().Value("auth").(*model.Auth)
+	id, err := utility.ToInt32(chi.URLParam(r, "id"))
+	if err != nil {
+		slog.Error("Failed to parse file ID from URL", "error", err)
+		utility.CreateErrorResponse(w, utility.ErrBadRequest.Code, utility.ErrBadRequest.Message)
+		return
+	}
+
+	request := new(model.FileDelete)
+	request.ID = id
+
+	if err := c.FileService.Delete(r.Context(), request, auth); err != nil {
+		utility.HandleError(w, err)
+		return
+	}
+
+	utility.CreateSuccessResponse(w, http.StatusOK, "File deleted successfully")
+}
@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"chrononewsapi/internal/model"
+	"chrononewsapi/internal/service"
+	"chrononewsapi/internal/utility"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type FrontpageController struct {
+	FrontpageService *service.FrontpageService
+}
+
+func NewFrontpageController(frontpageService *service.FrontpageService) *FrontpageController {
+	return &FrontpageController{FrontpageService: frontpageService}
+}
+
+// Get handles retrieving the homepage layout
+// @Summary Get frontpage
+// @Description Retrieve active breaking news, the editorial homepage slots and pinned posts per category in one request
+// @Tags Frontpage
+// @Produce json
+// @Success 200 {object} utility.ResponseSuccess{data=model.FrontpageResponse}
+// @Failure 500 {object} utility.ResponseError
+// @Router /api/frontpage [get]
+func (c *FrontpageController) Get(w http.ResponseWriter, r *http.Request) {
+	response, err := c.FrontpageService.Get(r.Context())
+	if err != nil {
+		utility.HandleError(w, err)
+		return
+	}
+
+	utility.CreateSuccessResponse(w, http.StatusOK, response)
+}
+
+// UpdateSlot handles filling a homepage slot
+// @Summary Update frontpage slot
+// @Description Replace the posts of a homepage slot, in the given order. An empty list clears the slot
+// @Tags Frontpage
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param name path string true "Slot name: hero, secondary, highlight"
+// @Param slot body model.FrontpageSlotUpdate true "Ordered post IDs"
+// @Success 200 {object} utility.ResponseSuccess{data=model.FrontpageSlotResponse}
+// @Failure 400 {object} utility.ResponseError
+// @Failure 403 {object} utility.ResponseError
+// @Failure 404 {object} utility.ResponseError
+// @Failure 500 {object} utility.ResponseError
+// @Router /api/frontpage/{name} [put]
+func (c *FrontpageController) UpdateSlot(w http.ResponseWriter, r *http.Request) {
+	auth := r.Context().Value("auth").(*model.Auth)
+
+	request := new(model.FrontpageSlotUpdate)
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		slog.Error("Failed to decode frontpage slot request", "error", err)
+		utility.CreateErrorResponse(w, utility.ErrBadRequest.Code, utility.ErrBadRequest.Message)
+		return
+	}
+	request.Name = chi.URLParam(r, "name")
+
+	response, err := c.FrontpageService.UpdateSlot(r.Context(), request, auth)
+	if err != nil {
+		utility.HandleError(w, err)
+		return
+	}
+
+	utility.CreateSuccessResponse(w, http.StatusOK, response)
+}
+
+// UpdatePlacement handles pinning a post and flagging it as breaking news
+// @Summary Update post placement
+// @Description Pin a post to the top of its category and/or show it in the breaking-news banner until the given time
+// @Tags Frontpage
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "Post ID"
+// @Param placement body model.PostPlacementUpdate true "Placement data, breakingUntil is a unix timestamp and 0 clears the flag"
+// @Success 200 {object} utility.ResponseSuccess{data=model.PostResponseWithPreload}
+// @Failure 400 {object} utility.ResponseError
+// @Failure 403 {object} utility.ResponseError
+// @Failure 404 {object} utility.ResponseError
+// @Failure 500 {object} utility.ResponseError
+// @Router /api/post/{id}/placement [patch]
+func (c *FrontpageController) UpdatePlacement(w http.ResponseWriter, r *http.Request) {
+	auth := r.Context().Value("auth").(*model.Auth)
+
+	id, err := utility.ToInt32(chi.URLParam(r, "id"))
+	if err != nil {
+		slog.Error("Failed to parse post ID from URL", "error", err)
+		utility.CreateErrorResponse(w, utility.ErrBadRequest.Code, utility.ErrBadRequest.Message)
+		return
+	}
+
+	request := new(model.PostPlacementUpdate)
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		slog.Error("Failed to decode post placement request", "error", err)
+		utility.CreateErrorResponse(w, utility.ErrBadRequest.Code, utility.ErrBadRequest.Message)
+		return
+	}
+	request.ID = id
+
+	response, err := c.FrontpageService.UpdatePlacement(r.Context(), request, auth)
+	if err != nil {
+		utility.HandleError(w, err)
+		return
+	}
+
+	utility.CreateSuccessResponse(w, http.StatusOK, response)
+}
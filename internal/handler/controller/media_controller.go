@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"chrononewsapi/internal/service"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const mediaCacheControl = "public, max-age=31536000, immutable"
+
+// MediaController serves the storage folders when storage mode is local.
+// Stored names are random and never reused, so responses are cached for good.
+type MediaController struct {
+	FileService *service.FileService
+}
+
+func NewMediaController(fileService *service.FileService) *MediaController {
+	return &MediaController{FileService: fileService}
+}
+
+// Serve returns a handler for the files directly inside root. Directories,
+// nested paths and hidden files are answered with 404. A JPEG or PNG is
+// replaced by a WebP file next to it when the client accepts WebP: the
+// full-size WebP variant recorded on the file if there is one, and otherwise
+// the file with the same base name, which is what the image processor writes
+// when it converts an original in place.
+func (c *MediaController) Serve(root string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		name := strings.TrimPrefix(r.URL.Path, "/")
+		if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+			http.NotFound(w, r)
+			return
+		}
+
+		served := name
+		ext := strings.ToLower(filepath.Ext(name))
+		negotiable := ext == ".jpg" || ext == ".jpeg" || ext == ".png"
+		if negotiable {
+			w.Header().Add("Vary", "Accept")
+			if acceptsWebP(r.Header.Get("Accept")) {
+				candidates := []string{strings.TrimSuffix(name, filepath.Ext(name)) + ".webp"}
+				if variant := c.FileService.WebPVariant(r.Context(), name); variant != "" {
+					candidates = append([]string{variant}, candidates...)
+				}
+				for _, webp := range candidates {
+					if strings.ContainsAny(webp, `/\`) || strings.HasPrefix(webp, ".") {
+						continue
+					}
+					if info, err := os.Stat(filepath.Join(root, webp)); err == nil && info.Mode().IsRegular() {
+						served = webp
+						break
+					}
+				}
+			}
+		}
+
+		file, err := os.Open(filepath.Join(root, served))
+		if err != nil {
+			if !os.IsNotExist(err) {
+				slog.Error("Failed to open media file", "name", served, "error", err)
+			}
+			http.NotFound(w, r)
+			return
+		}
+		defer func() {
+			_ = file.Close()
+		}()
+
+		info, err := file.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			http.NotFound(w, r)
+			return
+		}
+
+		contentType := mime.TypeByExtension(filepath.Ext(served))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("Cache-Control", mediaCacheControl)
+		w.Header().Set("ETag", mediaETag(served, info))
+
+		http.ServeContent(w, r, served, info.ModTime(), file)
+	})
+}
+
+// mediaETag includes the extension as well as the size and modification time,
+// so an original and its WebP replacement never share a tag.
+func mediaETag(name string, info fs.FileInfo) string {
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(name)), ".")
+	return fmt.Sprintf(`"%s-%x-%x"`, ext, info.ModTime().UnixNano(), info.Size())
+}
+
+// acceptsWebP reports whether an Accept header lists image/webp with a
+// non-zero quality. Wildcards are ignored, since browsers that support WebP
+// name it explicitly.
+func acceptsWebP(accept string) bool {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(part, ";")
+		if !strings.EqualFold(strings.TrimSpace(mediaType), "image/webp") {
+			continue
+		}
+		for _, param := range strings.Split(params, ";") {
+			key, value, _ := strings.Cut(param, "=")
+			if strings.TrimSpace(key) == "q" && strings.Trim(strings.TrimSpace(value), "0.") == "" {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -4,6 +4,7 @@ import (
 	"chrononewsapi/internal/model"
 	"chrononewsapi/internal/service"
 	"chrononewsapi/internal/utility"
+	"encoding/json"
 	"log/slog"
 	"net/http"
 
@@ -25,7 +26,7 @@ func NewPostController(postService *service.PostService) *PostController {
 // @Produce json
 // @Param page query int false "Page number" default(0)
 // @Param size query int false "Page size" default(5)
-// @Param userID query int false "User ID" default(0)
+// @Param userID query int false "Author ID, matches the owner or any co-author" default(0)
 // @Param title query string false "Title search query"
 // @Param userName query string false "User name search query"
 // @Param summary query string false "Summary search query"
@@ -113,12 +114,46 @@ func (c *PostController) Get(w http.ResponseWriter, r *http.Request) {
 	utility.CreateSuccessResponse(w, http.StatusOK, post)
 }
 
+// Preview handles getting a post in any status for its authors and admins
+// @Summary Preview a post
+// @Description Retrieve a draft, in-review or published post by its ID. Only the post's authors and admins, including the assigned editor, may read it
+// @Tags Post
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "Post ID"
+// @Success 200 {object} utility.ResponseSuccess{data=model.PostResponseWithPreload}
+// @Failure 400 {object} utility.ResponseError
+// @Failure 404 {object} utility.ResponseError
+// @Failure 500 {object} utility.ResponseError
+// @Router /api/post/{id}/preview [get]
+func (c *PostController) Preview(w http.ResponseWriter, r *http.Request) {
+	auth := r.Context().Value("auth").(*model.Auth)
+	id, err := utility.ToInt32(chi.URLParam(r, "id"))
+	if err != nil {
+		slog.Error("Failed to parse post ID from URL", "error", err)
+		utility.CreateErrorResponse(w, utility.ErrBadRequest.Code, utility.ErrBadRequest.Message)
+		return
+	}
+
+	request := &model.PostGet{ID: id}
+
+	post, err := c.PostService.Preview(r.Context(), request, auth)
+	if err != nil {
+		utility.HandleError(w, err)
+		return
+	}
+
+	utility.CreateSuccessResponse(w, http.StatusOK, post)
+}
+
 // IncrementViewCount handles incrementing the view count of a post
 // @Summary Increment post view count
-// @Description Increment the view count for a specific post by its ID
+// @Description Increment the view count for a specific post by its ID. Repeat views from the same visitor are only counted once per dedup window
 // @Tags Post
 // @Produce json
 // @Param id path int true "Post ID"
+// @Param X-Visitor-ID header string false "Stable anonymous visitor ID, used together with the IP address and user agent"
+// @Param referrer query string false "URL of the page the visitor came from (document.referrer)"
 // @Success 200 {object} utility.ResponseSuccess
 // @Failure 400 {object} utility.ResponseError
 // @Failure 404 {object} utility.ResponseError
@@ -132,7 +167,11 @@ func (c *PostController) IncrementViewCount(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	request := &model.PostIncrementView{ID: id}
+	request := &model.PostIncrementView{
+		ID:       id,
+		Visitors: utility.VisitorKeys(r),
+		Referrer: utility.ReferrerHost(r.URL.Query().Get("referrer")),
+	}
 
 	err = c.PostService.IncrementViewCount(r.Context(), request)
 	if err != nil {
@@ -156,8 +195,11 @@ func (c *PostController) IncrementViewCount(w http.ResponseWriter, r *http.Reque
 // @Param userID formData int32 false "User ID"
 // @Param categoryID formData int32 true "Category ID"
 // @Param thumbnail formData file false "Post Thumbnail"
+// @Param authors formData string false "JSON array of bylines in order, e.g. [{\"userID\":2,\"role\":\"photographer\"}]"
+// @Param draft formData bool false "Save as draft for editorial review. Posts by non-admins are always saved as drafts"
 // @Success 201 {object} utility.ResponseSuccess{data=model.PostResponse}
 // @Failure 400 {object} utility.ResponseError
+// @Failure 409 {object} utility.ResponseError
 // @Failure 500 {object} utility.ResponseError
 // @Router /api/post [post]
 func (c *PostController) Create(w http.ResponseWriter, r *http.Request) {
@@ -195,6 +237,15 @@ func (c *PostController) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	_, request.Thumbnail, _ = r.FormFile("thumbnail")
+	request.Draft = r.FormValue("draft") == "true"
+
+	if authors := r.FormValue("authors"); authors != "" {
+		if err := json.Unmarshal([]byte(authors), &request.Authors); err != nil {
+			slog.Error("Failed to parse authors from form", "error", err)
+			utility.CreateErrorResponse(w, utility.ErrBadRequest.Code, utility.ErrBadRequest.Message)
+			return
+		}
+	}
 
 	post, err := c.PostService.Create(r.Context(), request, auth)
 	if err != nil {
@@ -207,7 +258,7 @@ func (c *PostController) Create(w http.ResponseWriter, r *http.Request) {
 
 // Update handles updating a specific post
 // @Summary Update an existing post
-// @Description Update an existing post's details
+// @Description Update an existing post's details. Authors cannot edit a post under review, and their edits to a published post take it back to draft
 // @Tags Post
 // @Accept multipart/form-data
 // @Produce json
@@ -220,9 +271,11 @@ func (c *PostController) Create(w http.ResponseWriter, r *http.Request) {
 // @Param categoryID formData int32 true "Category ID"
 // @Param thumbnail formData file false "Post Thumbnail"
 // @Param deleteThumbnail formData bool false "Delete Thumbnail"
+// @Param authors formData string false "JSON array of bylines in order, replaces the current byline list"
 // @Success 200 {object} utility.ResponseSuccess{data=model.PostResponse}
 // @Failure 400 {object} utility.ResponseError
 // @Failure 404 {object} utility.ResponseError
+// @Failure 409 {object} utility.ResponseError
 // @Failure 500 {object} utility.ResponseError
 // @Router /api/post/{id} [put]
 func (c *PostController) Update(w http.ResponseWriter, r *http.Request) {
@@ -270,6 +323,15 @@ func (c *PostController) Update(w http.ResponseWriter, r *http.Request) {
 	}
 	_, request.Thumbnail, _ = r.FormFile("thumbnail")
 	request.DeleteThumbnail = r.FormValue("deleteThumbnail") == "true"
+
+	if authors := r.FormValue("authors"); authors != "" {
+		if err := json.Unmarshal([]byte(authors), &request.Authors); err != nil {
+			slog.Error("Failed to parse authors from form", "error", err)
+			utility.CreateErrorResponse(w, utility.ErrBadRequest.Code, utility.ErrBadRequest.Message)
+			return
+		}
+	}
+
 	post, err := c.PostService.Update(r.Context(), request, auth)
 	if err != nil {
 		utility.HandleError(w, err)
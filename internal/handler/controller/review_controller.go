@@ -0,0 +1,265 @@
+package controller
+
+import (
+	"chrononewsapi/internal/model"
+	"chrononewsapi/internal/service"
+	"chrononewsapi/internal/utility"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ReviewController struct {
+	ReviewService *service.ReviewService
+}
+
+func NewReviewController(reviewService *service.ReviewService) *ReviewController {
+	return &ReviewController{ReviewService: reviewService}
+}
+
+// Search handles listing the editorial review queue
+// @Summary List reviews
+// @Description List posts in the editorial workflow. Admins see every review, journalists only their own posts
+// @Tags Review
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param status query string false "Filter by post status: draft, in_review, changes_requested, rejected, published"
+// @Param assigned query bool false "Only reviews assigned to the current editor"
+// @Param page query int false "Page number" default(0)
+// @Param size query int false "Page size" default(10)
+// @Success 200 {object} utility.PaginationResponse{data=[]model.ReviewResponse,pagination=model.Pagination}
+// @Failure 400 {object} utility.ResponseError
+// @Failure 401 {object} utility.ResponseError
+// @Failure 500 {object} utility.ResponseError
+// @Router /api/review [get]
+func (c *ReviewController) Search(w http.ResponseWriter, r *http.Request) {
+	auth := r.Context().Value("auth").(*model.Auth)
+
+	page, err := utility.ToInt64(r.URL.Query().Get("page"))
+	if err != nil {
+		page = 0
+	}
+	size, err := utility.ToInt64(r.URL.Query().Get("size"))
+	if err != nil {
+		size = 10
+	}
+
+	request := &model.ReviewSearch{
+		Status:   r.URL.Query().Get("status"),
+		Assigned: r.URL.Query().Get("assigned") == "true",
+		Page:     page,
+		Size:     size,
+	}
+
+	response, pagination, err := c.ReviewService.Search(r.Context(), request, auth)
+	if err != nil {
+		utility.HandleError(w, err)
+		return
+	}
+
+	utility.CreateSuccessResponseWithPagination(w, http.StatusOK, response, pagination)
+}
+
+// Get handles retrieving the review of a post
+// @Summary Get post review
+// @Description Retrieve the review state, notes and history of a post
+// @Tags Review
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "Post ID"
+// @Success 200 {object} utility.ResponseSuccess{data=model.ReviewResponse}
+// @Failure 400 {object} utility.ResponseError
+// @Failure 404 {object} utility.ResponseError
+// @Failure 500 {object} utility.ResponseError
+// @Router /api/post/{id}/review [get]
+func (c *ReviewController) Get(w http.ResponseWriter, r *http.Request) {
+	auth := r.Context().Value("auth").(*model.Auth)
+
+	id, err := utility.ToInt32(chi.URLParam(r, "id"))
+	if err != nil {
+		slog.Error("Failed to parse post ID from URL", "error", err)
+		utility.CreateErrorResponse(w, utility.ErrBadRequest.Code, utility.ErrBadRequest.Message)
+		return
+	}
+
+	response, err := c.ReviewService.Get(r.Context(), &model.ReviewGet{PostID: id}, auth)
+	if err != nil {
+		utility.HandleError(w, err)
+		return
+	}
+
+	utility.CreateSuccessResponse(w, http.StatusOK, response)
+}
+
+// Submit handles submitting a draft for review
+// @Summary Submit post for review
+// @Description Submit a draft, rejected or changes-requested post for editorial review, optionally assigning an editor
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "Post ID"
+// @Param review body model.ReviewSubmit false "Submission data"
+// @Success 200 {object} utility.ResponseSuccess{data=model.ReviewResponse}
+// @Failure 400 {object} utility.ResponseError
+// @Failure 404 {object} utility.ResponseError
+// @Failure 409 {object} utility.ResponseError
+// @Failure 500 {object} utility.ResponseError
+// @Router /api/post/{id}/review [post]
+func (c *ReviewController) Submit(w http.ResponseWriter, r *http.Request) {
+	auth := r.Context().Value("auth").(*model.Auth)
+
+	id, err := utility.ToInt32(chi.URLParam(r, "id"))
+	if err != nil {
+		slog.Error("Failed to parse post ID from URL", "error", err)
+		utility.CreateErrorResponse(w, utility.ErrBadRequest.Code, utility.ErrBadRequest.Message)
+		return
+	}
+
+	request := new(model.ReviewSubmit)
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+			slog.Error("Failed to decode review submit request", "error", err)
+			utility.CreateErrorResponse(w, utility.ErrBadRequest.Code, utility.ErrBadRequest.Message)
+			return
+		}
+	}
+	request.PostID = id
+
+	response, err := c.ReviewService.Submit(r.Context(), request, auth)
+	if err != nil {
+		utility.HandleError(w, err)
+		return
+	}
+
+	utility.CreateSuccessResponse(w, http.StatusOK, response)
+}
+
+// Assign handles assigning an editor to a review
+// @Summary Assign review editor
+// @Description Assign an admin as the editor of a post under review
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "Post ID"
+// @Param review body model.ReviewAssign true "Editor data"
+// @Success 200 {object} utility.ResponseSuccess{data=model.ReviewResponse}
+// @Failure 400 {object} utility.ResponseError
+// @Failure 403 {object} utility.ResponseError
+// @Failure 404 {object} utility.ResponseError
+// @Failure 409 {object} utility.ResponseError
+// @Failure 500 {object} utility.ResponseError
+// @Router /api/post/{id}/review/editor [patch]
+func (c *ReviewController) Assign(w http.ResponseWriter, r *http.Request) {
+	auth := r.Context().Value("auth").(*model.Auth)
+
+	id, err := utility.ToInt32(chi.URLParam(r, "id"))
+	if err != nil {
+		slog.Error("Failed to parse post ID from URL", "error", err)
+		utility.CreateErrorResponse(w, utility.ErrBadRequest.Code, utility.ErrBadRequest.Message)
+		return
+	}
+
+	request := new(model.ReviewAssign)
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		slog.Error("Failed to decode review assign request", "error", err)
+		utility.CreateErrorResponse(w, utility.ErrBadRequest.Code, utility.ErrBadRequest.Message)
+		return
+	}
+	request.PostID = id
+
+	response, err := c.ReviewService.Assign(r.Context(), request, auth)
+	if err != nil {
+		utility.HandleError(w, err)
+		return
+	}
+
+	utility.CreateSuccessResponse(w, http.StatusOK, response)
+}
+
+// Comment handles adding a review note
+// @Summary Add review note
+// @Description Add a general note, or an inline note anchored to a passage of the content
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "Post ID"
+// @Param comment body model.ReviewCommentCreate true "Note data"
+// @Success 201 {object} utility.ResponseSuccess{data=model.ReviewCommentResponse}
+// @Failure 400 {object} utility.ResponseError
+// @Failure 404 {object} utility.ResponseError
+// @Failure 500 {object} utility.ResponseError
+// @Router /api/post/{id}/review/comment [post]
+func (c *ReviewController) Comment(w http.ResponseWriter, r *http.Request) {
+	auth := r.Context().Value("auth").(*model.Auth)
+
+	id, err := utility.ToInt32(chi.URLParam(r, "id"))
+	if err != nil {
+		slog.Error("Failed to parse post ID from URL", "error", err)
+		utility.CreateErrorResponse(w, utility.ErrBadRequest.Code, utility.ErrBadRequest.Message)
+		return
+	}
+
+	request := new(model.ReviewCommentCreate)
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		slog.Error("Failed to decode review comment request", "error", err)
+		utility.CreateErrorResponse(w, utility.ErrBadRequest.Code, utility.ErrBadRequest.Message)
+		return
+	}
+	request.PostID = id
+
+	response, err := c.ReviewService.Comment(r.Context(), request, auth)
+	if err != nil {
+		utility.HandleError(w, err)
+		return
+	}
+
+	utility.CreateSuccessResponse(w, http.StatusCreated, response)
+}
+
+// Decide handles the editor's review decision
+// @Summary Decide on a review
+// @Description Approve (publish), reject or request changes on a post under review
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "Post ID"
+// @Param decision body model.ReviewDecision true "Decision data"
+// @Success 200 {object} utility.ResponseSuccess{data=model.ReviewResponse}
+// @Failure 400 {object} utility.ResponseError
+// @Failure 403 {object} utility.ResponseError
+// @Failure 404 {object} utility.ResponseError
+// @Failure 409 {object} utility.ResponseError
+// @Failure 500 {object} utility.ResponseError
+// @Router /api/post/{id}/review/decision [patch]
+func (c *ReviewController) Decide(w http.ResponseWriter, r *http.Request) {
+	auth := r.Context().Value("auth").(*model.Auth)
+
+	id, err := utility.ToInt32(chi.URLParam(r, "id"))
+	if err != nil {
+		slog.Error("Failed to parse post ID from URL", "error", err)
+		utility.CreateErrorResponse(w, utility.ErrBadRequest.Code, utility.ErrBadRequest.Message)
+		return
+	}
+
+	request := new(model.ReviewDecision)
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		slog.Error("Failed to decode review decision request", "error", err)
+		utility.CreateErrorResponse(w, utility.ErrBadRequest.Code, utility.ErrBadRequest.Message)
+		return
+	}
+	request.PostID = id
+
+	response, err := c.ReviewService.Decide(r.Context(), request, auth)
+	if err != nil {
+		utility.HandleError(w, err)
+		return
+	}
+
+	utility.CreateSuccessResponse(w, http.StatusOK, response)
+}
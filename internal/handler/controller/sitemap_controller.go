@@ -70,3 +70,17 @@ func (c *SitemapController) GetCategoriesSitemap(w http.ResponseWriter, _ *http.
 		http.Error(w, "Failed to write sitemap", http.StatusInternalServerError)
 	}
 }
+
+func (c *SitemapController) GetAuthorsSitemap(w http.ResponseWriter, _ *http.Request) {
+	sitemap, err := c.sitemapService.GenerateAuthorsSitemap(c.DB)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	if _, err := w.Write(sitemap); err != nil {
+		slog.Error("Failed to write authors sitemap", "error", err)
+		http.Error(w, "Failed to write sitemap", http.StatusInternalServerError)
+	}
+}
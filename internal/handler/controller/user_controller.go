@@ -80,9 +80,14 @@ func (c *UserController) Current(w http.ResponseWriter, r *http.Request) {
 // @Param email formData string true "Email"
 // @Param profilePicture formData file false "Profile picture"
 // @Param deleteProfilePicture formData bool false "Delete profile picture"
+// @Param slug formData string false "Public author slug, generated from the name when empty"
+// @Param jobTitle formData string false "Job title"
+// @Param bio formData string false "Short biography"
+// @Param socialLinks formData string false "JSON object of social links, e.g. {\"twitter\":\"https://x.com/name\"}"
 // @Success 200 {object} utility.ResponseSuccess{data=model.UserResponse}
 // @Failure 400 {object} utility.ResponseError
 // @Failure 401 {object} utility.ResponseError
+// @Failure 409 {object} utility.ResponseError
 // @Failure 500 {object} utility.ResponseError
 // @Router /api/user/current/profile [patch]
 func (c *UserController) UpdateProfile(w http.ResponseWriter, r *http.Request) {
@@ -92,6 +97,16 @@ func (c *UserController) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 	request.PhoneNumber = r.FormValue("phoneNumber")
 	request.Email = r.FormValue("email")
 	request.DeleteProfilePicture = r.FormValue("deleteProfilePicture") == "true"
+	request.Slug = r.FormValue("slug")
+	request.JobTitle = r.FormValue("jobTitle")
+	request.Bio = r.FormValue("bio")
+	if socialLinks := r.FormValue("socialLinks"); socialLinks != "" {
+		if err := json.Unmarshal([]byte(socialLinks), &request.SocialLinks); err != nil {
+			slog.Error("Failed to parse social links", "error", err)
+			utility.CreateErrorResponse(w, utility.ErrBadRequest.Code, utility.ErrBadRequest.Message)
+			return
+		}
+	}
 	_, request.ProfilePicture, _ = r.FormFile("profilePicture")
 	response, err := c.UserService.UpdateProfile(r.Context(), request, auth)
 	if err != nil {
@@ -0,0 +1,107 @@
+package model
+
+type CategoryPostCount struct {
+	CategoryID int32  `json:"categoryID"`
+	Name       string `json:"name"`
+	Count      int64  `json:"count"`
+}
+
+type AuthorPostCount struct {
+	UserID int32  `json:"userID"`
+	Name   string `json:"name"`
+	Count  int64  `json:"count"`
+}
+
+type TopPostResponse struct {
+	ID        int32  `json:"id"`
+	Title     string `json:"title"`
+	ViewCount int64  `json:"viewCount"`
+}
+
+type StatusCount struct {
+	Status string
+	Count  int64
+}
+
+type AdminStatsResponse struct {
+	StartDate                int64               `json:"startDate"`
+	EndDate                  int64               `json:"endDate"`
+	GeneratedAt              int64               `json:"generatedAt"`
+	PostsByStatus            map[string]int64    `json:"postsByStatus"`
+	PostsByCategory          []CategoryPostCount `json:"postsByCategory"`
+	PostsByAuthor            []AuthorPostCount   `json:"postsByAuthor"`
+	TotalViews               int64               `json:"totalViews"`
+	TopPosts                 []TopPostResponse   `json:"topPosts"`
+	FilesByStatus            map[string]int64    `json:"filesByStatus"`
+	DeadLetterQueueSize      int64               `json:"deadLetterQueueSize"`
+	PendingSourceFileDeletes int64               `json:"pendingSourceFileDeletes"`
+}
+
+type AdminStatsGet struct {
+	StartDate int64 `validate:"min=0"`
+	EndDate   int64 `validate:"min=0"`
+}
+
+type AdminFileResponse struct {
+	ID             int32               `json:"id"`
+	Name           string              `json:"name"`
+	URL            string              `json:"url"`
+	Type           string              `json:"type"`
+	Status         string              `json:"status"`
+	FailedAttempts int                 `json:"failedAttempts"`
+	LastError      string              `json:"lastError,omitempty"`
+	Post           *FilePostResponse   `json:"post,omitempty"`
+	User           *UserPublicResponse `json:"user,omitempty"`
+	CreatedAt      int64               `json:"createdAt"`
+	UpdatedAt      int64               `json:"updatedAt"`
+}
+
+type DeadLetterResponse struct {
+	ID           int32             `json:"id"`
+	ErrorMessage string            `json:"errorMessage"`
+	CreatedAt    int64             `json:"createdAt"`
+	File         AdminFileResponse `json:"file"`
+}
+
+type AdminFileSearch struct {
+	Status string `validate:"omitempty,oneof=uploading private pending processing compressed failed"`
+	Type   string `validate:"omitempty,oneof=thumbnail attachment profile"`
+	Page   int64
+	Size   int64
+}
+
+type AdminDeadLetterSearch struct {
+	Page int64
+	Size int64
+}
+
+type AdminFileRequeue struct {
+	IDs []int32 `validate:"required,min=1,max=100,dive,required" json:"ids"`
+}
+
+type AdminFileRequeueResponse struct {
+	Requeued int64 `json:"requeued"`
+}
+
+type AdminFileDelete struct {
+	ID int32 `validate:"required"`
+}
+
+type AdminStorageReconcile struct {
+	DryRun bool
+}
+
+type StorageReconcileItem struct {
+	FileID int32  `json:"fileID,omitempty"`
+	Path   string `json:"path"`
+	Size   int64  `json:"size,omitempty"`
+}
+
+type StorageReconcileReport struct {
+	MissingObjects []StorageReconcileItem `json:"missingObjects"`
+	OrphanObjects  []StorageReconcileItem `json:"orphanObjects"`
+	UnusedFiles    []StorageReconcileItem `json:"unusedFiles"`
+	DeletedFiles   int64                  `json:"deletedFiles"`
+	DeletedObjects int64                  `json:"deletedObjects"`
+	DryRun         bool                   `json:"dryRun"`
+}
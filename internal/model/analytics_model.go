@@ -0,0 +1,45 @@
+package model
+
+type AnalyticsPoint struct {
+	Time  int64 `json:"time"`
+	Views int64 `json:"views"`
+}
+
+type ReferrerCount struct {
+	Referrer string `json:"referrer"`
+	Views    int64  `json:"views"`
+}
+
+type PostViewCount struct {
+	PostID int32
+	Views  int64
+}
+
+type PostAnalyticsResponse struct {
+	PostID          int32            `json:"postID"`
+	Granularity     string           `json:"granularity"`
+	From            int64            `json:"from"`
+	To              int64            `json:"to"`
+	TotalViews      int64            `json:"totalViews"`
+	WindowViews     int64            `json:"windowViews"`
+	CategoryAverage float64          `json:"categoryAverage"`
+	Series          []AnalyticsPoint `json:"series"`
+	TopReferrers    []ReferrerCount  `json:"topReferrers"`
+}
+
+type TrendingPostResponse struct {
+	PostResponseWithPreload
+	WindowViews int64 `json:"windowViews"`
+}
+
+type PostTrending struct {
+	Window string `validate:"required"`
+	Size   int64  `validate:"min=1,max=50"`
+}
+
+type PostAnalyticsGet struct {
+	ID          int32  `validate:"required"`
+	Granularity string `validate:"omitempty,oneof=hour day"`
+	From        int64  `validate:"min=0"`
+	To          int64  `validate:"min=0"`
+}
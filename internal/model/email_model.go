@@ -20,4 +20,8 @@ type EmailBodyData struct {
 	ResetRequestURL template.URL
 	Year            int
 	Expired         int
+	PostTitle       string
+	ActorName       string
+	Status          string
+	Note            string
 }
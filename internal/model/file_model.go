@@ -3,10 +3,131 @@ package model
 import "mime/multipart"
 
 type FileUpload struct {
-	File *multipart.FileHeader `validate:"required,image=16383_16383_10"`
+	File *multipart.FileHeader `validate:"required,image=16383_16383_10_png+jpeg+gif+webp+avif_300"`
 }
 
 type ImageUploadResponse struct {
 	ID   int32  `json:"id"`
 	Name string `json:"name"`
 }
+
+type MediaUpload struct {
+	File *multipart.FileHeader `validate:"required"`
+}
+
+type MediaUploadResponse struct {
+	ID       int32   `json:"id"`
+	Name     string  `json:"name"`
+	Kind     string  `json:"kind"`
+	MimeType string  `json:"mimeType"`
+	Size     int64   `json:"size"`
+	Duration float64 `json:"duration,omitempty"`
+	Pages    int     `json:"pages,omitempty"`
+}
+
+type FilePostResponse struct {
+	ID    int32  `json:"id"`
+	Title string `json:"title"`
+}
+
+type FileResponse struct {
+	ID        int32                `json:"id"`
+	Name      string               `json:"name"`
+	URL       string               `json:"url"`
+	Variants  map[string]string    `json:"variants,omitempty"`
+	Type      string               `json:"type"`
+	Kind      string               `json:"kind"`
+	MimeType  string               `json:"mimeType,omitempty"`
+	Size      int64                `json:"size"`
+	Duration  float64              `json:"duration,omitempty"`
+	Pages     int                  `json:"pages,omitempty"`
+	Width     int                  `json:"width,omitempty"`
+	Height    int                  `json:"height,omitempty"`
+	FocalX    *float64             `json:"focalX,omitempty"`
+	FocalY    *float64             `json:"focalY,omitempty"`
+	Crop      *ImageCrop           `json:"crop,omitempty"`
+	Crops     map[string]ImageCrop `json:"crops,omitempty"`
+	Status    string               `json:"status"`
+	AltText   string               `json:"altText"`
+	Caption   string               `json:"caption"`
+	Credit    string               `json:"credit"`
+	License   string               `json:"license"`
+	Uploader  *UserPublicResponse  `json:"uploader,omitempty"`
+	Post      *FilePostResponse    `json:"post,omitempty"`
+	User      *UserPublicResponse  `json:"user,omitempty"`
+	CreatedAt int64                `json:"createdAt"`
+	UpdatedAt int64                `json:"updatedAt"`
+}
+
+type FileSearch struct {
+	Type       string `validate:"omitempty,oneof=thumbnail attachment profile"`
+	Kind       string `validate:"omitempty,oneof=image document audio video"`
+	Status     string `validate:"omitempty,oneof=uploading private pending processing compressed failed"`
+	UploadedBy int32
+	Linked     string `validate:"omitempty,oneof=true false"`
+	StartDate  int64
+	EndDate    int64
+	Page       int64
+	Size       int64
+}
+
+type FileGet struct {
+	ID int32 `validate:"required"`
+}
+
+type FileDelete struct {
+	ID int32 `validate:"required"`
+}
+
+type FileMetadataUpdate struct {
+	ID      int32  `validate:"required" json:"-"`
+	AltText string `validate:"max=500" json:"altText"`
+	Caption string `validate:"max=1000" json:"caption"`
+	Credit  string `validate:"max=255" json:"credit"`
+	License string `validate:"max=100" json:"license"`
+}
+
+// ImageCrop is a rectangle in pixels of the original image.
+type ImageCrop struct {
+	X      int `validate:"min=0" json:"x"`
+	Y      int `validate:"min=0" json:"y"`
+	Width  int `validate:"min=1" json:"width"`
+	Height int `validate:"min=1" json:"height"`
+}
+
+// FileCropUpdate replaces the focal point and crop rectangle of an image.
+// Leaving a field out clears it.
+type FileCropUpdate struct {
+	ID     int32      `validate:"required" json:"-"`
+	FocalX *float64   `validate:"required_with=FocalY,omitempty,min=0,max=1" json:"focalX"`
+	FocalY *float64   `validate:"required_with=FocalX,omitempty,min=0,max=1" json:"focalY"`
+	Crop   *ImageCrop `validate:"omitempty" json:"crop"`
+}
+
+type FilePresign struct {
+	FileName    string `validate:"required,max=255" json:"fileName"`
+	ContentType string `validate:"required,oneof=image/png image/jpeg image/gif image/webp image/avif" json:"contentType"`
+	Size        int64  `validate:"required,min=1,max=10485760" json:"size"`
+}
+
+type FilePresignResponse struct {
+	ID        int32             `json:"id"`
+	UploadURL string            `json:"uploadURL"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt int64             `json:"expiresAt"`
+}
+
+type FileConfirm struct {
+	ID int32 `validate:"required"`
+}
+
+type FileDirectUpload struct {
+	Token         string `validate:"required"`
+	ContentType   string
+	ContentLength int64
+}
+
+type FilePrivate struct {
+	Token string `validate:"required"`
+}
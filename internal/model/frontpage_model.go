@@ -0,0 +1,22 @@
+package model
+
+type FrontpageSlotResponse struct {
+	Name  string                    `json:"name"`
+	Posts []PostResponseWithPreload `json:"posts"`
+}
+
+type FrontpagePinnedResponse struct {
+	Category *CategoryResponse         `json:"category"`
+	Posts    []PostResponseWithPreload `json:"posts"`
+}
+
+type FrontpageResponse struct {
+	Breaking []PostResponseWithPreload `json:"breaking"`
+	Slots    []FrontpageSlotResponse   `json:"slots"`
+	Pinned   []FrontpagePinnedResponse `json:"pinned"`
+}
+
+type FrontpageSlotUpdate struct {
+	Name    string  `validate:"required,oneof=hero secondary highlight" json:"-"`
+	PostIDs []int32 `validate:"max=20,dive,required" json:"postIDs"`
+}
@@ -3,34 +3,60 @@ package model
 import "mime/multipart"
 
 type UserPublicResponse struct {
-	ID             int32  `json:"id,omitempty"`
-	Name           string `json:"name,omitempty"`
-	ProfilePicture string `json:"profilePicture,omitempty"`
+	ID                     int32             `json:"id,omitempty"`
+	Name                   string            `json:"name,omitempty"`
+	ProfilePicture         string            `json:"profilePicture,omitempty"`
+	ProfilePictureVariants map[string]string `json:"profilePictureVariants,omitempty"`
+	Slug                   string            `json:"slug,omitempty"`
+	Role                   string            `json:"role,omitempty"`
+}
+
+type PostAuthorRequest struct {
+	UserID int32  `validate:"required" json:"userID"`
+	Role   string `validate:"omitempty,oneof=reporter photographer editor" json:"role"`
+}
+
+// ContentRemoval describes what the sanitizer dropped from post content.
+// Attribute is empty when a whole tag was removed.
+type ContentRemoval struct {
+	Tag       string `json:"tag"`
+	Attribute string `json:"attribute,omitempty"`
+	Reason    string `json:"reason"`
+	Count     int    `json:"count"`
 }
 
 type PostResponse struct {
-	ID         int32  `json:"id"`
-	CategoryID int32  `json:"categoryID,omitempty"`
-	UserID     int32  `json:"userID,omitempty"`
-	Title      string `json:"title"`
-	Summary    string `json:"summary,omitempty"`
-	Content    string `json:"content,omitempty"`
-	CreatedAt  int64  `json:"createdAt"`
-	UpdatedAt  int64  `json:"updatedAt"`
-	Thumbnail  string `json:"thumbnail"`
+	ID         int32            `json:"id"`
+	CategoryID int32            `json:"categoryID,omitempty"`
+	UserID     int32            `json:"userID,omitempty"`
+	Title      string           `json:"title"`
+	Summary    string           `json:"summary,omitempty"`
+	Content    string           `json:"content,omitempty"`
+	CreatedAt  int64            `json:"createdAt"`
+	UpdatedAt  int64            `json:"updatedAt"`
+	Thumbnail  string           `json:"thumbnail"`
+	Status     string           `json:"status,omitempty"`
+	Sanitized  []ContentRemoval `json:"sanitized,omitempty"`
 }
 
 type PostResponseWithPreload struct {
-	ID        int32               `json:"id"`
-	Category  *CategoryResponse   `json:"category,omitempty"`
-	User      *UserPublicResponse `json:"user,omitempty"`
-	Title     string              `json:"title"`
-	Summary   string              `json:"summary,omitempty"`
-	Content   string              `json:"content,omitempty"`
-	CreatedAt int64               `json:"createdAt"`
-	UpdatedAt int64               `json:"updatedAt"`
-	Thumbnail string              `json:"thumbnail"`
-	ViewCount int64               `json:"viewCount"`
+	ID                int32                `json:"id"`
+	Category          *CategoryResponse    `json:"category,omitempty"`
+	User              *UserPublicResponse  `json:"user,omitempty"`
+	Authors           []UserPublicResponse `json:"authors,omitempty"`
+	Title             string               `json:"title"`
+	Summary           string               `json:"summary,omitempty"`
+	Content           string               `json:"content,omitempty"`
+	CreatedAt         int64                `json:"createdAt"`
+	UpdatedAt         int64                `json:"updatedAt"`
+	Thumbnail         string               `json:"thumbnail"`
+	ThumbnailAlt      string               `json:"thumbnailAlt,omitempty"`
+	ThumbnailVariants map[string]string    `json:"thumbnailVariants,omitempty"`
+	ThumbnailCrops    map[string]ImageCrop `json:"thumbnailCrops,omitempty"`
+	ViewCount         int64                `json:"viewCount"`
+	Pinned            bool                 `json:"pinned,omitempty"`
+	BreakingUntil     int64                `json:"breakingUntil,omitempty"`
+	Status            string               `json:"status,omitempty"`
 }
 
 type PostGet struct {
@@ -38,7 +64,9 @@ type PostGet struct {
 }
 
 type PostIncrementView struct {
-	ID int32 `validate:"required"`
+	ID       int32 `validate:"required"`
+	Visitors []string
+	Referrer string
 }
 
 type PostSearch struct {
@@ -61,7 +89,9 @@ type PostCreate struct {
 	Content    string                `validate:"max=65535"`
 	UserID     int32                 `validate:"omitempty,required"`
 	CategoryID int32                 `validate:"required"`
-	Thumbnail  *multipart.FileHeader `validate:"omitempty,image=1200_675_2"`
+	Thumbnail  *multipart.FileHeader `validate:"omitempty,image=4096_4096_5_png+jpeg+webp"`
+	Authors    []PostAuthorRequest   `validate:"omitempty,max=20,dive"`
+	Draft      bool
 }
 
 type PostUpdate struct {
@@ -71,10 +101,17 @@ type PostUpdate struct {
 	Content         string                `validate:"max=65535"`
 	UserID          int32                 `validate:"omitempty,required"`
 	CategoryID      int32                 `validate:"required"`
-	Thumbnail       *multipart.FileHeader `validate:"omitempty,image=1200_675_2"`
+	Thumbnail       *multipart.FileHeader `validate:"omitempty,image=4096_4096_5_png+jpeg+webp"`
+	Authors         []PostAuthorRequest   `validate:"omitempty,max=20,dive"`
 	DeleteThumbnail bool
 }
 
+type PostPlacementUpdate struct {
+	ID            int32 `validate:"required" json:"-"`
+	Pinned        bool  `json:"pinned"`
+	BreakingUntil int64 `validate:"min=0" json:"breakingUntil"`
+}
+
 type PostDelete struct {
 	ID int32 `validate:"required"`
 }
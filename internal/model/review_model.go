@@ -0,0 +1,63 @@
+package model
+
+type ReviewCommentResponse struct {
+	ID        int32               `json:"id"`
+	User      *UserPublicResponse `json:"user,omitempty"`
+	Anchor    string              `json:"anchor,omitempty"`
+	Body      string              `json:"body"`
+	CreatedAt int64               `json:"createdAt"`
+}
+
+type ReviewEventResponse struct {
+	ID         int32               `json:"id"`
+	Actor      *UserPublicResponse `json:"actor,omitempty"`
+	FromStatus string              `json:"fromStatus"`
+	ToStatus   string              `json:"toStatus"`
+	Note       string              `json:"note,omitempty"`
+	CreatedAt  int64               `json:"createdAt"`
+}
+
+type ReviewResponse struct {
+	PostID    int32                   `json:"postID"`
+	Title     string                  `json:"title"`
+	Status    string                  `json:"status"`
+	Submitter *UserPublicResponse     `json:"submitter,omitempty"`
+	Editor    *UserPublicResponse     `json:"editor,omitempty"`
+	Comments  []ReviewCommentResponse `json:"comments,omitempty"`
+	Events    []ReviewEventResponse   `json:"events,omitempty"`
+	CreatedAt int64                   `json:"createdAt"`
+	UpdatedAt int64                   `json:"updatedAt"`
+}
+
+type ReviewGet struct {
+	PostID int32 `validate:"required"`
+}
+
+type ReviewSearch struct {
+	Status   string `validate:"omitempty,oneof=draft in_review changes_requested rejected published"`
+	Assigned bool
+	Page     int64
+	Size     int64
+}
+
+type ReviewSubmit struct {
+	PostID   int32 `validate:"required" json:"-"`
+	EditorID int32 `validate:"omitempty,numeric" json:"editorID"`
+}
+
+type ReviewAssign struct {
+	PostID   int32 `validate:"required" json:"-"`
+	EditorID int32 `validate:"required,numeric" json:"editorID"`
+}
+
+type ReviewCommentCreate struct {
+	PostID int32  `validate:"required" json:"-"`
+	Anchor string `validate:"max=1000" json:"anchor"`
+	Body   string `validate:"required,max=5000" json:"body"`
+}
+
+type ReviewDecision struct {
+	PostID   int32  `validate:"required" json:"-"`
+	Decision string `validate:"required,oneof=approve reject request_changes" json:"decision"`
+	Note     string `validate:"max=5000" json:"note"`
+}
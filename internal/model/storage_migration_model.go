@@ -0,0 +1,17 @@
+package model
+
+type StorageMigrationObject struct {
+	FileID int32  `json:"fileID"`
+	Path   string `json:"path"`
+	Error  string `json:"error,omitempty"`
+}
+
+type StorageMigrationReport struct {
+	Files   int64                    `json:"files"`
+	Copied  int64                    `json:"copied"`
+	Skipped int64                    `json:"skipped"`
+	Missing []StorageMigrationObject `json:"missing"`
+	Failed  []StorageMigrationObject `json:"failed"`
+	LastID  int32                    `json:"lastID"`
+	DryRun  bool                     `json:"dryRun"`
+}
@@ -3,12 +3,40 @@ package model
 import "mime/multipart"
 
 type UserResponse struct {
-	ID             int32  `json:"id,omitempty"`
-	Name           string `json:"name,omitempty"`
-	ProfilePicture string `json:"profilePicture,omitempty"`
-	PhoneNumber    string `json:"phoneNumber,omitempty"`
-	Email          string `json:"email,omitempty"`
-	Role           string `json:"role,omitempty"`
+	ID                     int32             `json:"id,omitempty"`
+	Name                   string            `json:"name,omitempty"`
+	ProfilePicture         string            `json:"profilePicture,omitempty"`
+	ProfilePictureVariants map[string]string `json:"profilePictureVariants,omitempty"`
+	PhoneNumber            string            `json:"phoneNumber,omitempty"`
+	Email                  string            `json:"email,omitempty"`
+	Role                   string            `json:"role,omitempty"`
+	Slug                   string            `json:"slug,omitempty"`
+	JobTitle               string            `json:"jobTitle,omitempty"`
+	Bio                    string            `json:"bio,omitempty"`
+	SocialLinks            map[string]string `json:"socialLinks,omitempty"`
+	Usage                  *UploadUsage      `json:"usage,omitempty"`
+}
+
+// UploadUsage reports what an account has uploaded against its quotas. A
+// limit of 0 means there is none.
+type UploadUsage struct {
+	StoredBytes  int64 `json:"storedBytes"`
+	StorageLimit int64 `json:"storageLimit"`
+	TodayBytes   int64 `json:"todayBytes"`
+	DailyLimit   int64 `json:"dailyLimit"`
+	ResetsAt     int64 `json:"resetsAt"`
+}
+
+type AuthorResponse struct {
+	ID                     int32                     `json:"id"`
+	Name                   string                    `json:"name"`
+	Slug                   string                    `json:"slug"`
+	ProfilePicture         string                    `json:"profilePicture,omitempty"`
+	ProfilePictureVariants map[string]string         `json:"profilePictureVariants,omitempty"`
+	JobTitle               string                    `json:"jobTitle,omitempty"`
+	Bio                    string                    `json:"bio,omitempty"`
+	SocialLinks            map[string]string         `json:"socialLinks,omitempty"`
+	Posts                  []PostResponseWithPreload `json:"posts"`
 }
 
 type UserRegister struct {
@@ -28,8 +56,12 @@ type UserUpdateProfile struct {
 	Name                 string                `validate:"required,min=3,max=255"`
 	PhoneNumber          string                `validate:"required,e164,max=20"`
 	Email                string                `validate:"required,email,max=255"`
-	ProfilePicture       *multipart.FileHeader `validate:"omitempty,image=800_800_2"`
+	ProfilePicture       *multipart.FileHeader `validate:"omitempty,image=800_800_2_png+jpeg+webp"`
 	DeleteProfilePicture bool
+	Slug                 string            `validate:"omitempty,max=255"`
+	JobTitle             string            `validate:"max=100"`
+	Bio                  string            `validate:"max=2000"`
+	SocialLinks          map[string]string `validate:"omitempty,max=10,dive,keys,oneof=website twitter facebook instagram linkedin youtube tiktok,endkeys,url,max=255"`
 }
 
 type UserUpdatePassword struct {
@@ -52,7 +84,7 @@ type UserCreate struct {
 
 	PhoneNumber    string                `validate:"required,e164,max=20"`
 	Email          string                `validate:"required,email,max=255"`
-	ProfilePicture *multipart.FileHeader `validate:"omitempty,image=800_800_2"`
+	ProfilePicture *multipart.FileHeader `validate:"omitempty,image=800_800_2_png+jpeg+webp"`
 	Role           string                `validate:"required,oneof=admin journalist"`
 }
 
@@ -62,7 +94,7 @@ type UserUpdate struct {
 
 	PhoneNumber          string                `validate:"required,e164,max=20"`
 	Email                string                `validate:"required,email,max=255"`
-	ProfilePicture       *multipart.FileHeader `validate:"omitempty,image=800_800_2"`
+	ProfilePicture       *multipart.FileHeader `validate:"omitempty,image=800_800_2_png+jpeg+webp"`
 	Password             string                `validate:"omitempty,passwordformat,min=8,max=255"`
 	Role                 string                `validate:"required,oneof=admin journalist"`
 	DeleteProfilePicture bool
@@ -75,3 +107,9 @@ type UserDelete struct {
 type UserGet struct {
 	ID int32 `validate:"required"`
 }
+
+type AuthorGet struct {
+	Slug string `validate:"required,max=255"`
+	Page int64
+	Size int64
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"chrononewsapi/internal/constant"
+	"chrononewsapi/internal/entity"
+	"chrononewsapi/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type AnalyticsRepository struct {
+	CommonRepository[entity.PostViewBucket]
+}
+
+func NewAnalyticsRepository() *AnalyticsRepository {
+	return &AnalyticsRepository{}
+}
+
+func (r *AnalyticsRepository) IncrementBucket(db *gorm.DB, postID int32, bucketStart int64, referrer string, views int64) error {
+	return db.Exec(`
+	INSERT INTO post_view_bucket (post_id, category_id, granularity, bucket_start, referrer, views)
+	SELECT id, category_id, ?, ?, ?, ? FROM post WHERE id = ?
+	ON CONFLICT (post_id, granularity, bucket_start, referrer)
+	DO UPDATE SET views = post_view_bucket.views + EXCLUDED.views
+	`, constant.BucketHour, bucketStart, referrer, views, postID).Error
+}
+
+func (r *AnalyticsRepository) RollupHourly(db *gorm.DB, before int64) error {
+	if err := db.Exec(`
+	INSERT INTO post_view_bucket (post_id, category_id, granularity, bucket_start, referrer, views)
+	SELECT post_id, MAX(category_id), ?, bucket_start - (bucket_start % 86400), referrer, SUM(views)
+	FROM post_view_bucket
+	WHERE granularity = ? AND bucket_start < ?
+	GROUP BY post_id, bucket_start - (bucket_start % 86400), referrer
+	ON CONFLICT (post_id, granularity, bucket_start, referrer)
+	DO UPDATE SET views = post_view_bucket.views + EXCLUDED.views
+	`, constant.BucketDay, constant.BucketHour, before).Error; err != nil {
+		return err
+	}
+
+	return db.Where("granularity = ?", constant.BucketHour).
+		Where("bucket_start < ?", before).
+		Delete(&entity.PostViewBucket{}).Error
+}
+
+func (r *AnalyticsRepository) FindTrending(db *gorm.DB, since int64, limit int) ([]model.PostViewCount, error) {
+	var counts []model.PostViewCount
+	err := db.Model(&entity.PostViewBucket{}).
+		Select("post_view_bucket.post_id, SUM(post_view_bucket.views) AS views").
+		Joins("JOIN post ON post.id = post_view_bucket.post_id").
+		Where("post.status = ?", constant.PostStatusPublished).
+		Where("post_view_bucket.bucket_start >= ?", since).
+		Group("post_view_bucket.post_id").
+		Order("views DESC, post_view_bucket.post_id DESC").
+		Limit(limit).
+		Scan(&counts).Error
+	return counts, err
+}
+
+func (r *AnalyticsRepository) FindSeries(db *gorm.DB, postID int32, granularity string, from, to int64) ([]model.AnalyticsPoint, error) {
+	bucket := "bucket_start"
+	query := db.Model(&entity.PostViewBucket{})
+	if granularity == constant.BucketHour {
+		query = query.Where("granularity = ?", constant.BucketHour)
+	} else {
+		bucket = "bucket_start - (bucket_start % 86400)"
+	}
+
+	var points []model.AnalyticsPoint
+	err := query.
+		Select(bucket+` AS "time", SUM(views) AS views`).
+		Where("post_id = ?", postID).
+		Where("bucket_start >= ? AND bucket_start < ?", from, to).
+		Group(bucket).
+		Order(`"time" ASC`).
+		Scan(&points).Error
+	return points, err
+}
+
+func (r *AnalyticsRepository) FindTopReferrers(db *gorm.DB, postID int32, from, to int64, limit int) ([]model.ReferrerCount, error) {
+	var referrers []model.ReferrerCount
+	err := db.Model(&entity.PostViewBucket{}).
+		Select("referrer, SUM(views) AS views").
+		Where("post_id = ?", postID).
+		Where("referrer <> ''").
+		Where("bucket_start >= ? AND bucket_start < ?", from, to).
+		Group("referrer").
+		Order("views DESC, referrer ASC").
+		Limit(limit).
+		Scan(&referrers).Error
+	return referrers, err
+}
+
+func (r *AnalyticsRepository) SumCategoryViews(db *gorm.DB, categoryID int32, from, to int64) (int64, error) {
+	var views int64
+	err := db.Model(&entity.PostViewBucket{}).
+		Select("COALESCE(SUM(views), 0)").
+		Where("category_id = ?", categoryID).
+		Where("bucket_start >= ? AND bucket_start < ?", from, to).
+		Scan(&views).Error
+	return views, err
+}
@@ -1,9 +1,13 @@
 package repository
 
 import (
+	"chrononewsapi/internal/constant"
 	"chrononewsapi/internal/entity"
+	"chrononewsapi/internal/model"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type FileRepository struct {
@@ -23,6 +27,21 @@ func (r *FileRepository) FindByID(db *gorm.DB, id int32) (*entity.File, error) {
 	return &file, nil
 }
 
+func (r *FileRepository) FindByName(db *gorm.DB, name string) (*entity.File, error) {
+	var file entity.File
+	err := db.Where("name = ?", name).First(&file).Error
+	if err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// FindAfterID returns up to limit files with an ID above afterID, in ID order,
+// so long-running jobs can walk the table in resumable batches.
+func (r *FileRepository) FindAfterID(db *gorm.DB, afterID int32, limit int, files *[]entity.File) error {
+	return db.Where("id > ?", afterID).Order("id ASC").Limit(limit).Find(files).Error
+}
+
 func (r *FileRepository) FindAsMap(db *gorm.DB, ids []int32) map[int32]*entity.File {
 	fileMap := make(map[int32]*entity.File)
 	if len(ids) == 0 {
@@ -30,7 +49,7 @@ func (r *FileRepository) FindAsMap(db *gorm.DB, ids []int32) map[int32]*entity.F
 	}
 
 	var files []entity.File
-	db.Where("id IN ?", ids).Find(&files)
+	db.Where("id IN ?", ids).Where("scan_status = ?", constant.ScanStatusClean).Find(&files)
 
 	for i := range files {
 		fileMap[files[i].ID] = &files[i]
@@ -39,20 +58,279 @@ func (r *FileRepository) FindAsMap(db *gorm.DB, ids []int32) map[int32]*entity.F
 	return fileMap
 }
 
+// CountUnscanned returns how many of the given files have not been marked
+// clean by the malware scanner.
+func (r *FileRepository) CountUnscanned(db *gorm.DB, ids []int32) (int64, error) {
+	var count int64
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	err := db.Model(&entity.File{}).
+		Where("id IN ? AND scan_status <> ?", ids, constant.ScanStatusClean).
+		Count(&count).Error
+	return count, err
+}
+
 func (r *FileRepository) LinkFilesToPost(db *gorm.DB, fileIDs []int32, postID int32) error {
 	if len(fileIDs) == 0 {
 		return nil
 	}
-	return db.Model(&entity.File{}).Where("id IN ?", fileIDs).Update("used_by_post_id", postID).Error
+	return db.Model(&entity.File{}).Where("id IN ?", fileIDs).
+		Updates(map[string]interface{}{"used_by_post_id": postID, "unlinked_at": nil}).Error
 }
 
+// UnlinkUnusedFiles detaches the post's files that are not in usedFileIDs and
+// records when, so the reconciler can tell them from files never linked.
 func (r *FileRepository) UnlinkUnusedFiles(db *gorm.DB, postID int32, usedFileIDs []int32) error {
-	if len(usedFileIDs) == 0 {
-		return db.Model(&entity.File{}).Where("used_by_post_id = ?", postID).Update("used_by_post_id", nil).Error
+	query := db.Model(&entity.File{}).Where("used_by_post_id = ?", postID)
+	if len(usedFileIDs) > 0 {
+		query = query.Where("id NOT IN ?", usedFileIDs)
 	}
-	return db.Model(&entity.File{}).Where("used_by_post_id = ? AND id NOT IN ?", postID, usedFileIDs).Update("used_by_post_id", nil).Error
+	return query.Updates(map[string]interface{}{"used_by_post_id": nil, "unlinked_at": time.Now().Unix()}).Error
 }
 
 func (r *FileRepository) UnlinkFilesFromUser(db *gorm.DB, userID int32) error {
-	return db.Model(&entity.File{}).Where("used_by_user_id = ?", userID).Update("used_by_user_id", nil).Error
+	return db.Model(&entity.File{}).Where("used_by_user_id = ?", userID).
+		Updates(map[string]interface{}{"used_by_user_id": nil, "unlinked_at": time.Now().Unix()}).Error
+}
+
+func (r *FileRepository) Search(db *gorm.DB, request *model.AdminFileSearch, files *[]entity.File) (int64, error) {
+	query := db.Model(&entity.File{})
+
+	if request.Status != "" {
+		query = query.Where("status = ?", request.Status)
+	}
+
+	if request.Type != "" {
+		query = query.Where("type = ?", request.Type)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return 0, err
+	}
+
+	if request.Page > 0 && request.Size > 0 {
+		query = query.Limit(int(request.Size)).Offset(int((request.Page - 1) * request.Size))
+	}
+
+	err := query.Preload("Post").
+		Preload("User").
+		Order("updated_at DESC, id DESC").
+		Find(files).Error
+	return total, err
+}
+
+func (r *FileRepository) SearchLibrary(db *gorm.DB, request *model.FileSearch, files *[]entity.File) (int64, error) {
+	query := db.Model(&entity.File{})
+
+	if request.Type != "" {
+		query = query.Where("type = ?", request.Type)
+	}
+
+	if request.Kind != "" {
+		query = query.Where("kind = ?", request.Kind)
+	}
+
+	if request.Status != "" {
+		query = query.Where("status = ?", request.Status)
+	}
+
+	if request.UploadedBy != 0 {
+		query = query.Where("uploaded_by_id = ?", request.UploadedBy)
+	}
+
+	switch request.Linked {
+	case "true":
+		query = query.Where("(used_by_post_id IS NOT NULL OR used_by_user_id IS NOT NULL)")
+	case "false":
+		query = query.Where("used_by_post_id IS NULL AND used_by_user_id IS NULL")
+	}
+
+	if request.StartDate != 0 && request.EndDate != 0 {
+		query = query.Where("created_at BETWEEN ? AND ?", request.StartDate, request.EndDate)
+	} else if request.StartDate != 0 {
+		query = query.Where("created_at >= ?", request.StartDate)
+	} else if request.EndDate != 0 {
+		query = query.Where("created_at <= ?", request.EndDate)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return 0, err
+	}
+
+	if request.Page > 0 && request.Size > 0 {
+		query = query.Limit(int(request.Size)).Offset(int((request.Page - 1) * request.Size))
+	}
+
+	err := query.Preload("Uploader").
+		Preload("Post").
+		Preload("User").
+		Order("created_at DESC, id DESC").
+		Find(files).Error
+	return total, err
+}
+
+func (r *FileRepository) FindByIDWithRelations(db *gorm.DB, file *entity.File, id int32) error {
+	return db.Preload("Uploader").
+		Preload("Post").
+		Preload("User").
+		First(file, id).Error
+}
+
+// UpdateMetadata writes only the descriptive columns so it cannot race with
+// the image processor updating status.
+func (r *FileRepository) UpdateMetadata(db *gorm.DB, file *entity.File) error {
+	return db.Model(file).
+		Select("alt_text", "caption", "credit", "license").
+		Updates(file).Error
+}
+
+// UpdateCrop writes only the crop hint columns, for the same reason.
+func (r *FileRepository) UpdateCrop(db *gorm.DB, file *entity.File) error {
+	return db.Model(file).
+		Select("focal_x", "focal_y", "crop").
+		Updates(file).Error
+}
+
+// UpdateDimensions records the size of an image stored after its row was
+// created.
+func (r *FileRepository) UpdateDimensions(db *gorm.DB, file *entity.File) error {
+	return db.Model(file).
+		Select("width", "height", "size").
+		Updates(file).Error
+}
+
+func (r *FileRepository) MarkUploaded(db *gorm.DB, file *entity.File) error {
+	return db.Model(file).
+		Where("status = ?", constant.FileStatusUploading).
+		Select("status", "width", "height", "size", "hash", "scan_status").
+		Updates(file).Error
+}
+
+func (r *FileRepository) FindDeadLetters(db *gorm.DB, request *model.AdminDeadLetterSearch, entries *[]entity.DeadLetterQueue) (int64, error) {
+	query := db.Model(&entity.DeadLetterQueue{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return 0, err
+	}
+
+	if request.Page > 0 && request.Size > 0 {
+		query = query.Limit(int(request.Size)).Offset(int((request.Page - 1) * request.Size))
+	}
+
+	err := query.Preload("File.Post").
+		Preload("File.User").
+		Order("created_at DESC, id DESC").
+		Find(entries).Error
+	return total, err
+}
+
+func (r *FileRepository) Requeue(db *gorm.DB, ids []int32) (int64, error) {
+	if err := db.Where("file_id IN ?", ids).Delete(&entity.DeadLetterQueue{}).Error; err != nil {
+		return 0, err
+	}
+
+	// Unpublished files are still in the private folder the processor does
+	// not read from.
+	result := db.Model(&entity.File{}).
+		Where("id IN ?", ids).
+		Where("status NOT IN ?", []string{constant.FileStatusUploading, constant.FileStatusPrivate}).
+		Updates(map[string]interface{}{
+			"status":          constant.FileStatusPending,
+			"failed_attempts": 0,
+			"last_error":      nil,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// FindByHash returns the newest file of the given type whose content has the
+// given SHA-256 hash and whose object is safe to share: either processed or
+// private and not yet handed to the processor. Files that are pending or being
+// processed are skipped, since the processor renames their object.
+func (r *FileRepository) FindByHash(db *gorm.DB, hash string, fileType string) (*entity.File, error) {
+	var file entity.File
+	err := db.Where("hash = ? AND type = ?", hash, fileType).
+		Where("status IN ?", []string{constant.FileStatusCompressed, constant.FileStatusPrivate}).
+		Where("scan_status = ?", constant.ScanStatusClean).
+		Order("id DESC").
+		First(&file).Error
+	if err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// SumSizeByUploader returns the bytes held by the files an account uploaded.
+// Rows sharing a stored object through deduplication count once. Failed
+// uploads and presigned uploads created before uploadCutoff, which the
+// reconciler will remove, are not counted.
+func (r *FileRepository) SumSizeByUploader(db *gorm.DB, userID int32, uploadCutoff int64) (int64, error) {
+	var size int64
+	err := db.Raw(`
+	SELECT COALESCE(SUM(size), 0)
+	FROM (
+		SELECT DISTINCT ON (name, type) size
+		FROM file
+		WHERE uploaded_by_id = ? AND status <> ?
+		AND NOT (status = ? AND created_at < ?)
+		ORDER BY name, type, id
+	) stored
+	`, userID, constant.FileStatusFailed, constant.FileStatusUploading, uploadCutoff).Scan(&size).Error
+	return size, err
+}
+
+func (r *FileRepository) FindPrivateByPost(db *gorm.DB, postID int32, files *[]entity.File) error {
+	return db.Where("used_by_post_id = ? AND status = ?", postID, constant.FileStatusPrivate).Find(files).Error
+}
+
+// Publish moves a private file to the given status and the object name it
+// was published under, unless it has been published in the meantime.
+func (r *FileRepository) Publish(db *gorm.DB, file *entity.File, name string, status string) error {
+	return db.Model(file).
+		Where("status = ?", constant.FileStatusPrivate).
+		Updates(map[string]interface{}{"name": name, "status": status}).Error
+}
+
+// CountPrivateSharing returns how many other private files reference the same
+// private object as file.
+func (r *FileRepository) CountPrivateSharing(db *gorm.DB, file *entity.File) (int64, error) {
+	var count int64
+	err := db.Model(&entity.File{}).
+		Where("name = ? AND type = ? AND id <> ?", file.Name, file.Type, file.ID).
+		Where("status IN ?", []string{constant.FileStatusUploading, constant.FileStatusPrivate}).
+		Count(&count).Error
+	return count, err
+}
+
+// CountSharing returns how many other files reference the same stored object
+// as file. The rows are locked so concurrent deletes of the last two
+// references cannot both decide the object is still in use.
+func (r *FileRepository) CountSharing(db *gorm.DB, file *entity.File) (int64, error) {
+	var ids []int32
+	err := db.Model(&entity.File{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("name = ? AND type = ? AND id <> ?", file.Name, file.Type, file.ID).
+		Pluck("id", &ids).Error
+	return int64(len(ids)), err
+}
+
+func (r *FileRepository) FindSourceFiles(db *gorm.DB, fileID int32, sources *[]entity.SourceFileToDelete) error {
+	return db.Where("file_id = ?", fileID).Find(sources).Error
+}
+
+func (r *FileRepository) FindSourcePaths(db *gorm.DB, paths *[]string) error {
+	return db.Model(&entity.SourceFileToDelete{}).Pluck("source_path", paths).Error
+}
+
+func (r *FileRepository) Discard(db *gorm.DB, file *entity.File) error {
+	if err := db.Where("file_id = ?", file.ID).Delete(&entity.DeadLetterQueue{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("file_id = ?", file.ID).Delete(&entity.SourceFileToDelete{}).Error; err != nil {
+		return err
+	}
+	return db.Delete(file).Error
 }
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"chrononewsapi/internal/constant"
+	"chrononewsapi/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+type FrontpageRepository struct {
+	CommonRepository[entity.FrontpageSlot]
+}
+
+func NewFrontpageRepository() *FrontpageRepository {
+	return &FrontpageRepository{}
+}
+
+func (r *FrontpageRepository) FindAll(db *gorm.DB, slots *[]entity.FrontpageSlot) error {
+	return db.Joins("JOIN post ON post.id = frontpage_slot.post_id").
+		Where("post.status = ?", constant.PostStatusPublished).
+		Preload("Post.User.Files").
+		Preload("Post.Category").
+		Preload("Post.Files", "type = ?", constant.FileTypeThumbnail).
+		Preload("Post.Authors", orderAuthors).
+		Preload("Post.Authors.User.Files").
+		Order("frontpage_slot.name ASC, frontpage_slot.position ASC").
+		Find(slots).Error
+}
+
+func (r *FrontpageRepository) ReplaceSlot(db *gorm.DB, name string, slots []entity.FrontpageSlot) error {
+	if err := db.Where("name = ?", name).Delete(&entity.FrontpageSlot{}).Error; err != nil {
+		return err
+	}
+	if len(slots) == 0 {
+		return nil
+	}
+	for i := range slots {
+		slots[i].Name = name
+	}
+	return db.Omit("Post").Create(&slots).Error
+}
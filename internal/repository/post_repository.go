@@ -20,7 +20,9 @@ func NewPostRepository() *PostRepository {
 func (r *PostRepository) Search(db *gorm.DB, request *model.PostSearch, posts *[]entity.Post, excludeIDs []uint) (int64, error) {
 	query := db.Preload("User.Files").
 		Preload("Category").
-		Preload("Files", "type = ?", constant.FileTypeThumbnail)
+		Preload("Files", "type = ?", constant.FileTypeThumbnail).
+		Preload("Authors", orderAuthors).
+		Preload("Authors.User.Files")
 	var conditions []string
 	var args []interface{}
 
@@ -42,16 +44,18 @@ func (r *PostRepository) Search(db *gorm.DB, request *model.PostSearch, posts *[
 
 	if request.UserName != "" {
 		query = query.Joins("JOIN \"user\" u ON u.id = post.user_id")
-		conditions = append(conditions, "LOWER(u.name) LIKE ?")
-		args = append(args, "%"+strings.ToLower(request.UserName)+"%")
+		conditions = append(conditions, "(LOWER(u.name) LIKE ? OR EXISTS (SELECT 1 FROM post_author pa JOIN \"user\" au ON au.id = pa.user_id WHERE pa.post_id = post.id AND LOWER(au.name) LIKE ?))")
+		args = append(args, "%"+strings.ToLower(request.UserName)+"%", "%"+strings.ToLower(request.UserName)+"%")
 	}
 
 	if len(conditions) > 0 {
 		query = query.Where(strings.Join(conditions, " OR "), args...)
 	}
 
+	query = query.Where("post.status = ?", constant.PostStatusPublished)
+
 	if request.UserID != 0 {
-		query = query.Where("post.user_id = ?", request.UserID)
+		query = query.Where("(post.user_id = ? OR EXISTS (SELECT 1 FROM post_author pa WHERE pa.post_id = post.id AND pa.user_id = ?))", request.UserID, request.UserID)
 	}
 
 	if len(excludeIDs) > 0 {
@@ -69,6 +73,8 @@ func (r *PostRepository) Search(db *gorm.DB, request *model.PostSearch, posts *[
 
 	if v, ok := orderMap[request.Sort]; ok {
 		orderBy = v
+	} else if request.CategoryName != "" {
+		orderBy = "post.pinned_at DESC NULLS LAST, " + orderBy
 	}
 
 	if request.StartDate != 0 && request.EndDate != 0 {
@@ -97,22 +103,112 @@ func (r *PostRepository) FindByID(db *gorm.DB, post *entity.Post, id int32) erro
 	return db.Where("id = ?", id).
 		Preload("User.Files").
 		Preload("Category").
-		Preload("Files").First(post).Error
+		Preload("Files").
+		Preload("Authors", orderAuthors).
+		Preload("Authors.User.Files").First(post).Error
 }
 
 func (r *PostRepository) FindByIDAndUserID(db *gorm.DB, post *entity.Post, postID int32, userID int32) error {
 	return db.Where("id = ?", postID).Where("user_id = ?", userID).
 		Preload("User.Files").
 		Preload("Category").
-		Preload("Files").First(post).Error
+		Preload("Files").
+		Preload("Authors", orderAuthors).
+		Preload("Authors.User.Files").First(post).Error
+}
+
+func (r *PostRepository) FindByIDAndAuthorID(db *gorm.DB, post *entity.Post, postID int32, userID int32) error {
+	return db.Where("id = ?", postID).
+		Where("(user_id = ? OR EXISTS (SELECT 1 FROM post_author pa WHERE pa.post_id = post.id AND pa.user_id = ?))", userID, userID).
+		Preload("User.Files").
+		Preload("Category").
+		Preload("Files").
+		Preload("Authors", orderAuthors).
+		Preload("Authors.User.Files").First(post).Error
 }
 
 func (r *PostRepository) Update(db *gorm.DB, post *entity.Post) error {
 	return db.Model(post).
-		Omit("Category", "User").
+		Omit("Category", "User", "Authors").
 		Save(post).Error
 }
 
+func (r *PostRepository) ReplaceAuthors(db *gorm.DB, postID int32, authors []entity.PostAuthor) error {
+	if err := db.Where("post_id = ?", postID).Delete(&entity.PostAuthor{}).Error; err != nil {
+		return err
+	}
+	if len(authors) == 0 {
+		return nil
+	}
+	for i := range authors {
+		authors[i].PostID = postID
+	}
+	return db.Omit("User").Create(&authors).Error
+}
+
+func orderAuthors(db *gorm.DB) *gorm.DB {
+	return db.Order("post_author.position ASC")
+}
+
+func (r *PostRepository) FindBreaking(db *gorm.DB, posts *[]entity.Post, now int64) error {
+	return db.Preload("User.Files").
+		Preload("Category").
+		Preload("Files", "type = ?", constant.FileTypeThumbnail).
+		Preload("Authors", orderAuthors).
+		Preload("Authors.User.Files").
+		Where("status = ?", constant.PostStatusPublished).
+		Where("breaking_until > ?", now).
+		Order("created_at DESC").
+		Find(posts).Error
+}
+
+func (r *PostRepository) FindPinned(db *gorm.DB, posts *[]entity.Post) error {
+	return db.Preload("User.Files").
+		Preload("Category").
+		Preload("Files", "type = ?", constant.FileTypeThumbnail).
+		Preload("Authors", orderAuthors).
+		Preload("Authors.User.Files").
+		Where("status = ?", constant.PostStatusPublished).
+		Where("pinned_at IS NOT NULL").
+		Order("category_id ASC, pinned_at DESC").
+		Find(posts).Error
+}
+
+func (r *PostRepository) FindPublishedByIDs(db *gorm.DB, posts *[]entity.Post, ids []int32) error {
+	return db.Preload("User.Files").
+		Preload("Category").
+		Preload("Files", "type = ?", constant.FileTypeThumbnail).
+		Preload("Authors", orderAuthors).
+		Preload("Authors.User.Files").
+		Where("status = ?", constant.PostStatusPublished).
+		Where("id IN ?", ids).
+		Find(posts).Error
+}
+
+func (r *PostRepository) UpdatePlacement(db *gorm.DB, postID int32, pinnedAt *int64, breakingUntil *int64) error {
+	return db.Model(&entity.Post{}).Where("id = ?", postID).Updates(map[string]interface{}{
+		"pinned_at":      pinnedAt,
+		"breaking_until": breakingUntil,
+	}).Error
+}
+
+func (r *PostRepository) IncrementViewCount(db *gorm.DB, postID int32, count int64) error {
+	return db.Model(&entity.Post{}).
+		Where("id = ?", postID).
+		UpdateColumn("view_count", gorm.Expr("view_count + ?", count)).Error
+}
+
+func (r *PostRepository) IsPublished(db *gorm.DB, postID int32) error {
+	return db.Select("id").
+		Where("id = ?", postID).
+		Where("status = ?", constant.PostStatusPublished).
+		First(&entity.Post{}).Error
+}
+
+func (r *PostRepository) UpdateStatus(db *gorm.DB, postID int32, status string) error {
+	return db.Model(&entity.Post{}).Where("id = ?", postID).Update("status", status).Error
+}
+
 func (r *PostRepository) ExistsByUserID(db *gorm.DB, userID int32) (bool, error) {
 	var exists bool
 	err := db.Model(&entity.Post{}).
@@ -131,15 +227,25 @@ func (r *PostRepository) ExistsByCategoryID(db *gorm.DB, categoryID int32) (bool
 	return exists, err
 }
 
+func (r *PostRepository) CountPublishedByCategoryID(db *gorm.DB, categoryID int32) (int64, error) {
+	var count int64
+	err := db.Model(&entity.Post{}).
+		Where("category_id = ?", categoryID).
+		Where("status = ?", constant.PostStatusPublished).
+		Count(&count).Error
+	return count, err
+}
+
 func (r *PostRepository) Count(db *gorm.DB) (int64, error) {
 	var count int64
-	err := db.Model(&entity.Post{}).Count(&count).Error
+	err := db.Model(&entity.Post{}).Where("status = ?", constant.PostStatusPublished).Count(&count).Error
 	return count, err
 }
 
 func (r *PostRepository) FindAllPaged(db *gorm.DB, limit, offset int) ([]entity.Post, error) {
 	var posts []entity.Post
 	err := db.Select("id", "title", "updated_at").
+		Where("status = ?", constant.PostStatusPublished).
 		Order("created_at DESC").
 		Limit(limit).
 		Offset(offset).
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"chrononewsapi/internal/constant"
+	"chrononewsapi/internal/entity"
+	"chrononewsapi/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type ReviewRepository struct {
+	CommonRepository[entity.Review]
+}
+
+func NewReviewRepository() *ReviewRepository {
+	return &ReviewRepository{}
+}
+
+func (r *ReviewRepository) FindByPostID(db *gorm.DB, review *entity.Review, postID int32) error {
+	return db.Where("post_id = ?", postID).
+		Preload("Post").
+		Preload("Submitter").
+		Preload("Editor").
+		Preload("Comments", func(db *gorm.DB) *gorm.DB {
+			return db.Order("review_comment.created_at ASC, review_comment.id ASC")
+		}).
+		Preload("Comments.User").
+		Preload("Events", func(db *gorm.DB) *gorm.DB {
+			return db.Order("review_event.created_at ASC, review_event.id ASC")
+		}).
+		Preload("Events.Actor").
+		First(review).Error
+}
+
+func (r *ReviewRepository) Search(db *gorm.DB, request *model.ReviewSearch, reviews *[]entity.Review, userID int32, isAdmin bool) (int64, error) {
+	query := db.Model(&entity.Review{}).
+		Joins("JOIN post ON post.id = review.post_id").
+		Preload("Post").
+		Preload("Submitter").
+		Preload("Editor")
+
+	if isAdmin {
+		if request.Assigned {
+			query = query.Where("review.editor_id = ?", userID)
+		}
+	} else {
+		query = query.Where("(post.user_id = ? OR EXISTS (SELECT 1 FROM post_author pa WHERE pa.post_id = post.id AND pa.user_id = ?))", userID, userID)
+	}
+
+	if request.Status != "" {
+		query = query.Where("post.status = ?", request.Status)
+	} else {
+		query = query.Where("post.status <> ?", constant.PostStatusPublished)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return 0, err
+	}
+
+	if request.Page > 0 && request.Size > 0 {
+		query = query.Limit(int(request.Size)).Offset(int((request.Page - 1) * request.Size))
+	}
+
+	err := query.Order("review.updated_at DESC").Find(reviews).Error
+	return total, err
+}
+
+func (r *ReviewRepository) CreateComment(db *gorm.DB, comment *entity.ReviewComment) error {
+	return db.Create(comment).Error
+}
+
+func (r *ReviewRepository) CreateEvent(db *gorm.DB, event *entity.ReviewEvent) error {
+	return db.Create(event).Error
+}
+
+func (r *ReviewRepository) Update(db *gorm.DB, review *entity.Review) error {
+	return db.Model(review).
+		Omit("Post", "Submitter", "Editor", "Comments", "Events").
+		Save(review).Error
+}
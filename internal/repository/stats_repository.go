@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"chrononewsapi/internal/constant"
+	"chrononewsapi/internal/entity"
+	"chrononewsapi/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type StatsRepository struct {
+}
+
+func NewStatsRepository() *StatsRepository {
+	return &StatsRepository{}
+}
+
+func (r *StatsRepository) CountPostsByStatus(db *gorm.DB) ([]model.StatusCount, error) {
+	var counts []model.StatusCount
+	err := db.Model(&entity.Post{}).
+		Select("status, COUNT(*) AS count").
+		Group("status").
+		Scan(&counts).Error
+	return counts, err
+}
+
+func (r *StatsRepository) CountPostsByCategory(db *gorm.DB) ([]model.CategoryPostCount, error) {
+	var counts []model.CategoryPostCount
+	err := db.Model(&entity.Category{}).
+		Select("category.id AS category_id, category.name, COUNT(post.id) AS count").
+		Joins("LEFT JOIN post ON post.category_id = category.id AND post.status = ?", constant.PostStatusPublished).
+		Group("category.id, category.name").
+		Order("count DESC, category.name ASC").
+		Scan(&counts).Error
+	return counts, err
+}
+
+func (r *StatsRepository) CountPostsByAuthor(db *gorm.DB, startDate, endDate int64) ([]model.AuthorPostCount, error) {
+	var counts []model.AuthorPostCount
+	err := db.Raw(`
+	SELECT u.id AS user_id, u.name, COUNT(DISTINCT p.id) AS count
+	FROM post p
+	JOIN (SELECT id AS post_id, user_id FROM post UNION SELECT post_id, user_id FROM post_author) a ON a.post_id = p.id
+	JOIN "user" u ON u.id = a.user_id
+	WHERE p.created_at BETWEEN ? AND ?
+	GROUP BY u.id, u.name
+	ORDER BY count DESC, u.name ASC
+	`, startDate, endDate).Scan(&counts).Error
+	return counts, err
+}
+
+func (r *StatsRepository) SumViews(db *gorm.DB) (int64, error) {
+	var total int64
+	err := db.Model(&entity.Post{}).
+		Select("COALESCE(SUM(view_count), 0)").
+		Where("status = ?", constant.PostStatusPublished).
+		Scan(&total).Error
+	return total, err
+}
+
+func (r *StatsRepository) FindTopPosts(db *gorm.DB, limit int) ([]model.TopPostResponse, error) {
+	var posts []model.TopPostResponse
+	err := db.Model(&entity.Post{}).
+		Select("id, title, view_count").
+		Where("status = ?", constant.PostStatusPublished).
+		Order("view_count DESC, id DESC").
+		Limit(limit).
+		Scan(&posts).Error
+	return posts, err
+}
+
+func (r *StatsRepository) CountFilesByStatus(db *gorm.DB) ([]model.StatusCount, error) {
+	var counts []model.StatusCount
+	err := db.Model(&entity.File{}).
+		Select("status, COUNT(*) AS count").
+		Group("status").
+		Scan(&counts).Error
+	return counts, err
+}
+
+func (r *StatsRepository) CountDeadLetterQueue(db *gorm.DB) (int64, error) {
+	var count int64
+	err := db.Model(&entity.DeadLetterQueue{}).Count(&count).Error
+	return count, err
+}
+
+func (r *StatsRepository) CountSourceFilesToDelete(db *gorm.DB) (int64, error) {
+	var count int64
+	err := db.Model(&entity.SourceFileToDelete{}).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"chrononewsapi/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+type UploadUsageRepository struct {
+	CommonRepository[entity.UploadUsage]
+}
+
+func NewUploadUsageRepository() *UploadUsageRepository {
+	return &UploadUsageRepository{}
+}
+
+func (r *UploadUsageRepository) Increment(db *gorm.DB, userID int32, day int64, bytes int64) error {
+	return db.Exec(`
+	INSERT INTO upload_usage (user_id, day, bytes)
+	VALUES (?, ?, ?)
+	ON CONFLICT (user_id, day)
+	DO UPDATE SET bytes = upload_usage.bytes + EXCLUDED.bytes
+	`, userID, day, bytes).Error
+}
+
+func (r *UploadUsageRepository) FindBytes(db *gorm.DB, userID int32, day int64) (int64, error) {
+	var bytes int64
+	err := db.Model(&entity.UploadUsage{}).
+		Select("COALESCE(SUM(bytes), 0)").
+		Where("user_id = ? AND day = ?", userID, day).
+		Scan(&bytes).Error
+	return bytes, err
+}
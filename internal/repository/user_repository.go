@@ -33,6 +33,25 @@ func (r *UserRepository) FindIDByPhoneNumber(db *gorm.DB, phoneNumber string) in
 	return user.ID
 }
 
+func (r *UserRepository) FindIDBySlug(db *gorm.DB, slug string) int32 {
+	var user entity.User
+	if err := db.Select("id").Where("slug = ?", slug).First(&user).Error; err != nil {
+		return 0
+	}
+	return user.ID
+}
+
+func (r *UserRepository) FindBySlug(db *gorm.DB, entity *entity.User, slug string) error {
+	return db.Preload("Files", "type = ?", constant.FileTypeProfile).Where("slug = ?", slug).First(entity).Error
+}
+
+func (r *UserRepository) FindAllAuthors(db *gorm.DB, entities *[]entity.User) error {
+	return db.Where("slug IS NOT NULL").
+		Where(`EXISTS (SELECT 1 FROM post WHERE post.status = ? AND (post.user_id = "user".id OR EXISTS (SELECT 1 FROM post_author pa WHERE pa.post_id = post.id AND pa.user_id = "user".id)))`, constant.PostStatusPublished).
+		Order("id ASC").
+		Find(entities).Error
+}
+
 func (r *UserRepository) FindPasswordByEmail(db *gorm.DB, entity *entity.User, email string) error {
 	return db.Where("email = ?", email).First(entity).Error
 }
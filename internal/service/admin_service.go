@@ -0,0 +1,386 @@
+package service
+
+import (
+	"chrononewsapi/internal/adapter"
+	"chrononewsapi/internal/config"
+	"chrononewsapi/internal/constant"
+	"chrononewsapi/internal/entity"
+	"chrononewsapi/internal/model"
+	"chrononewsapi/internal/repository"
+	"chrononewsapi/internal/utility"
+	"context"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+const (
+	statsCacheTTL     = time.Minute
+	statsDefaultRange = 30 * 24 * 60 * 60
+	statsTopPosts     = 10
+)
+
+type AdminService struct {
+	DB                *gorm.DB
+	StatsRepository   *repository.StatsRepository
+	UserRepository    *repository.UserRepository
+	FileRepository    *repository.FileRepository
+	StorageAdapter    adapter.StorageAdapter
+	StorageReconciler *StorageReconciler
+	Validator         *validator.Validate
+	Config            *config.Config
+
+	statsMu    sync.Mutex
+	statsCache map[[2]int64]cachedStats
+}
+
+type cachedStats struct {
+	response  *model.AdminStatsResponse
+	expiresAt time.Time
+}
+
+func NewAdminService(db *gorm.DB, statsRepository *repository.StatsRepository, userRepository *repository.UserRepository, fileRepository *repository.FileRepository, storageAdapter adapter.StorageAdapter, storageReconciler *StorageReconciler, validator *validator.Validate, config *config.Config) *AdminService {
+	return &AdminService{
+		DB:                db,
+		StatsRepository:   statsRepository,
+		UserRepository:    userRepository,
+		FileRepository:    fileRepository,
+		StorageAdapter:    storageAdapter,
+		StorageReconciler: storageReconciler,
+		Validator:         validator,
+		Config:            config,
+		statsCache:        make(map[[2]int64]cachedStats),
+	}
+}
+
+func (s *AdminService) Stats(ctx context.Context, request *model.AdminStatsGet, auth *model.Auth) (*model.AdminStatsResponse, error) {
+	db := s.DB.WithContext(ctx)
+
+	if err := s.UserRepository.IsAdmin(db, auth.ID); err != nil {
+		slog.Error("Failed to check admin status for stats", "error", err)
+		return nil, utility.ErrForbidden
+	}
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error("Validation failed for admin stats", "error", err)
+		return nil, utility.ErrBadRequest
+	}
+
+	now := time.Now()
+	if request.EndDate == 0 {
+		// Round to the minute so repeated dashboard loads share a cache entry.
+		request.EndDate = now.Unix() - now.Unix()%60
+	}
+	if request.StartDate == 0 {
+		request.StartDate = request.EndDate - statsDefaultRange
+	}
+	if request.StartDate > request.EndDate {
+		return nil, utility.ErrBadRequest
+	}
+
+	key := [2]int64{request.StartDate, request.EndDate}
+
+	s.statsMu.Lock()
+	if cached, ok := s.statsCache[key]; ok && now.Before(cached.expiresAt) {
+		s.statsMu.Unlock()
+		return cached.response, nil
+	}
+	s.statsMu.Unlock()
+
+	response, err := s.collectStats(db, request)
+	if err != nil {
+		slog.Error("Failed to collect admin stats", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
+	s.statsMu.Lock()
+	for k, cached := range s.statsCache {
+		if !now.Before(cached.expiresAt) {
+			delete(s.statsCache, k)
+		}
+	}
+	s.statsCache[key] = cachedStats{response: response, expiresAt: now.Add(statsCacheTTL)}
+	s.statsMu.Unlock()
+
+	return response, nil
+}
+
+func (s *AdminService) collectStats(db *gorm.DB, request *model.AdminStatsGet) (*model.AdminStatsResponse, error) {
+	response := &model.AdminStatsResponse{
+		StartDate:   request.StartDate,
+		EndDate:     request.EndDate,
+		GeneratedAt: time.Now().Unix(),
+		PostsByStatus: map[string]int64{
+			constant.PostStatusDraft:            0,
+			constant.PostStatusInReview:         0,
+			constant.PostStatusChangesRequested: 0,
+			constant.PostStatusRejected:         0,
+			constant.PostStatusPublished:        0,
+		},
+		FilesByStatus: map[string]int64{
+			constant.FileStatusUploading:  0,
+			constant.FileStatusPrivate:    0,
+			constant.FileStatusPending:    0,
+			constant.FileStatusProcessing: 0,
+			constant.FileStatusCompressed: 0,
+			constant.FileStatusFailed:     0,
+		},
+	}
+
+	postStatuses, err := s.StatsRepository.CountPostsByStatus(db)
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range postStatuses {
+		response.PostsByStatus[status.Status] = status.Count
+	}
+
+	if response.PostsByCategory, err = s.StatsRepository.CountPostsByCategory(db); err != nil {
+		return nil, err
+	}
+
+	if response.PostsByAuthor, err = s.StatsRepository.CountPostsByAuthor(db, request.StartDate, request.EndDate); err != nil {
+		return nil, err
+	}
+
+	if response.TotalViews, err = s.StatsRepository.SumViews(db); err != nil {
+		return nil, err
+	}
+
+	if response.TopPosts, err = s.StatsRepository.FindTopPosts(db, statsTopPosts); err != nil {
+		return nil, err
+	}
+
+	fileStatuses, err := s.StatsRepository.CountFilesByStatus(db)
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range fileStatuses {
+		response.FilesByStatus[status.Status] = status.Count
+	}
+
+	if response.DeadLetterQueueSize, err = s.StatsRepository.CountDeadLetterQueue(db); err != nil {
+		return nil, err
+	}
+
+	if response.PendingSourceFileDeletes, err = s.StatsRepository.CountSourceFilesToDelete(db); err != nil {
+		return nil, err
+	}
+
+	if response.PostsByCategory == nil {
+		response.PostsByCategory = []model.CategoryPostCount{}
+	}
+	if response.PostsByAuthor == nil {
+		response.PostsByAuthor = []model.AuthorPostCount{}
+	}
+	if response.TopPosts == nil {
+		response.TopPosts = []model.TopPostResponse{}
+	}
+
+	return response, nil
+}
+
+func (s *AdminService) ListFiles(ctx context.Context, request *model.AdminFileSearch, auth *model.Auth) (*[]model.AdminFileResponse, *model.Pagination, error) {
+	db := s.DB.WithContext(ctx)
+
+	if err := s.UserRepository.IsAdmin(db, auth.ID); err != nil {
+		slog.Error("Failed to check admin status for file list", "error", err)
+		return nil, nil, utility.ErrForbidden
+	}
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error("Validation failed for admin file list", "error", err)
+		return nil, nil, utility.ErrBadRequest
+	}
+
+	var files []entity.File
+	total, err := s.FileRepository.Search(db, request, &files)
+	if err != nil {
+		slog.Error("Failed to search files", "error", err)
+		return nil, nil, utility.ErrInternalServer
+	}
+
+	responses := make([]model.AdminFileResponse, len(files))
+	for i := range files {
+		responses[i] = s.toAdminFileResponse(&files[i])
+	}
+
+	return &responses, newAdminPagination(request.Page, request.Size, total), nil
+}
+
+func (s *AdminService) ListDeadLetters(ctx context.Context, request *model.AdminDeadLetterSearch, auth *model.Auth) (*[]model.DeadLetterResponse, *model.Pagination, error) {
+	db := s.DB.WithContext(ctx)
+
+	if err := s.UserRepository.IsAdmin(db, auth.ID); err != nil {
+		slog.Error("Failed to check admin status for dead letter list", "error", err)
+		return nil, nil, utility.ErrForbidden
+	}
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error("Validation failed for dead letter list", "error", err)
+		return nil, nil, utility.ErrBadRequest
+	}
+
+	var entries []entity.DeadLetterQueue
+	total, err := s.FileRepository.FindDeadLetters(db, request, &entries)
+	if err != nil {
+		slog.Error("Failed to find dead letter entries", "error", err)
+		return nil, nil, utility.ErrInternalServer
+	}
+
+	responses := make([]model.DeadLetterResponse, len(entries))
+	for i, entry := range entries {
+		responses[i] = model.DeadLetterResponse{
+			ID:           entry.ID,
+			ErrorMessage: entry.ErrorMessage,
+			CreatedAt:    entry.CreatedAt,
+			File:         s.toAdminFileResponse(&entry.File),
+		}
+	}
+
+	return &responses, newAdminPagination(request.Page, request.Size, total), nil
+}
+
+func (s *AdminService) Requeue(ctx context.Context, request *model.AdminFileRequeue, auth *model.Auth) (*model.AdminFileRequeueResponse, error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.UserRepository.IsAdmin(tx, auth.ID); err != nil {
+		slog.Error("Failed to check admin status for requeue", "error", err)
+		return nil, utility.ErrForbidden
+	}
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error("Validation failed for file requeue", "error", err)
+		return nil, utility.ErrBadRequest
+	}
+
+	requeued, err := s.FileRepository.Requeue(tx, request.IDs)
+	if err != nil {
+		slog.Error("Failed to requeue files", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error("Failed to commit file requeue", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
+	return &model.AdminFileRequeueResponse{Requeued: requeued}, nil
+}
+
+func (s *AdminService) DiscardFile(ctx context.Context, request *model.AdminFileDelete, auth *model.Auth) error {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.UserRepository.IsAdmin(tx, auth.ID); err != nil {
+		slog.Error("Failed to check admin status for file discard", "error", err)
+		return utility.ErrForbidden
+	}
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error("Validation failed for file discard", "error", err)
+		return utility.ErrBadRequest
+	}
+
+	file, err := s.FileRepository.FindByID(tx, request.ID)
+	if err != nil {
+		slog.Error("Failed to find file for discard", "error", err)
+		return utility.ErrNotFound
+	}
+
+	var sources []entity.SourceFileToDelete
+	if err := s.FileRepository.FindSourceFiles(tx, file.ID, &sources); err != nil {
+		slog.Error("Failed to find source files for discard", "error", err)
+		return utility.ErrInternalServer
+	}
+
+	shared, err := s.FileRepository.CountSharing(tx, file)
+	if err != nil {
+		slog.Error("Failed to count files sharing the stored object", "error", err)
+		return utility.ErrInternalServer
+	}
+
+	if err := s.FileRepository.Discard(tx, file); err != nil {
+		slog.Error("Failed to discard file", "error", err)
+		return utility.ErrInternalServer
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error("Failed to commit file discard", "error", err)
+		return utility.ErrInternalServer
+	}
+
+	deleteFileObjects(ctx, s.StorageAdapter, s.Config, file, shared > 0, sources)
+
+	return nil
+}
+
+func (s *AdminService) ReconcileStorage(ctx context.Context, request *model.AdminStorageReconcile, auth *model.Auth) (*model.StorageReconcileReport, error) {
+	if err := s.UserRepository.IsAdmin(s.DB.WithContext(ctx), auth.ID); err != nil {
+		slog.Error("Failed to check admin status for storage reconciliation", "error", err)
+		return nil, utility.ErrForbidden
+	}
+
+	report, err := s.StorageReconciler.Reconcile(ctx, request.DryRun)
+	if err != nil {
+		slog.Error("Failed to reconcile storage", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
+	return report, nil
+}
+
+func newAdminPagination(page, size, total int64) *model.Pagination {
+	pagination := &model.Pagination{
+		TotalItem: total,
+	}
+
+	if page != 0 && size != 0 {
+		pagination.Page = page
+		pagination.Size = size
+		pagination.TotalPage = int64(math.Ceil(float64(total) / float64(size)))
+	} else {
+		pagination.TotalPage = 1
+	}
+
+	return pagination
+}
+
+func (s *AdminService) toAdminFileResponse(file *entity.File) model.AdminFileResponse {
+	response := model.AdminFileResponse{
+		ID:             file.ID,
+		Name:           file.Name,
+		URL:            utility.BuildFileURL(s.StorageAdapter, s.Config, file),
+		Type:           file.Type,
+		Status:         file.Status,
+		FailedAttempts: file.FailedAttempts,
+		CreatedAt:      file.CreatedAt,
+		UpdatedAt:      file.UpdatedAt,
+	}
+
+	if file.LastError != nil {
+		response.LastError = *file.LastError
+	}
+
+	if file.Post != nil {
+		response.Post = &model.FilePostResponse{
+			ID:    file.Post.ID,
+			Title: file.Post.Title,
+		}
+	}
+
+	if file.User != nil {
+		response.User = &model.UserPublicResponse{
+			ID:   file.User.ID,
+			Name: file.User.Name,
+			Slug: userSlug(file.User),
+		}
+	}
+
+	return response
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"chrononewsapi/internal/config"
+	"chrononewsapi/internal/constant"
+	"chrononewsapi/internal/entity"
+	"chrononewsapi/internal/model"
+	"chrononewsapi/internal/repository"
+	"chrononewsapi/internal/utility"
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+const (
+	maxTrendingWindow   = 30 * 24 * time.Hour
+	maxAnalyticsRange   = 366 * 24 * 60 * 60
+	defaultAnalyticsLen = 30 * 24 * 60 * 60
+	topReferrersLimit   = 10
+)
+
+type AnalyticsService struct {
+	DB                  *gorm.DB
+	AnalyticsRepository *repository.AnalyticsRepository
+	PostRepository      *repository.PostRepository
+	UserRepository      *repository.UserRepository
+	PostService         *PostService
+	Validator           *validator.Validate
+	Config              *config.Config
+}
+
+func NewAnalyticsService(db *gorm.DB, analyticsRepository *repository.AnalyticsRepository, postRepository *repository.PostRepository, userRepository *repository.UserRepository, postService *PostService, validator *validator.Validate, config *config.Config) *AnalyticsService {
+	return &AnalyticsService{
+		DB:                  db,
+		AnalyticsRepository: analyticsRepository,
+		PostRepository:      postRepository,
+		UserRepository:      userRepository,
+		PostService:         postService,
+		Validator:           validator,
+		Config:              config,
+	}
+}
+
+// StartRollup periodically folds hourly buckets older than the configured
+// retention into daily buckets.
+func (s *AnalyticsService) StartRollup(ctx context.Context, wg *sync.WaitGroup) {
+	if s.Config.View.HourlyRetention <= 0 {
+		return
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			if err := s.Rollup(ctx); err != nil {
+				slog.Error("Failed to roll up view buckets", "error", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (s *AnalyticsService) Rollup(ctx context.Context) error {
+	now := time.Now().Unix()
+	before := now - now%86400 - int64(s.Config.View.HourlyRetention)*86400
+
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.AnalyticsRepository.RollupHourly(tx, before); err != nil {
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func (s *AnalyticsService) Trending(ctx context.Context, request *model.PostTrending) (*[]model.TrendingPostResponse, error) {
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error("Validation failed for trending posts", "error", err)
+		return nil, utility.ErrBadRequest
+	}
+
+	window, err := parseWindow(request.Window)
+	if err != nil || window < time.Hour || window > maxTrendingWindow {
+		return nil, utility.NewCustomError(400, "Window must be between 1h and 30d")
+	}
+
+	db := s.DB.WithContext(ctx)
+
+	since := time.Now().Add(-window).Unix()
+	// Hourly buckets are aligned to the hour, so include the partial first hour.
+	since -= since % 3600
+
+	counts, err := s.AnalyticsRepository.FindTrending(db, since, int(request.Size))
+	if err != nil {
+		slog.Error("Failed to find trending posts", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
+	response := make([]model.TrendingPostResponse, 0, len(counts))
+	if len(counts) == 0 {
+		return &response, nil
+	}
+
+	ids := make([]int32, 0, len(counts))
+	for _, count := range counts {
+		ids = append(ids, count.PostID)
+	}
+
+	var posts []entity.Post
+	if err := s.PostRepository.FindPublishedByIDs(db, &posts, ids); err != nil {
+		slog.Error("Failed to find trending post details", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
+	postMap := make(map[int32]*entity.Post, len(posts))
+	for i := range posts {
+		postMap[posts[i].ID] = &posts[i]
+	}
+
+	for _, count := range counts {
+		post, ok := postMap[count.PostID]
+		if !ok {
+			continue
+		}
+		response = append(response, model.TrendingPostResponse{
+			PostResponseWithPreload: s.PostService.toPostSummaryResponse(post),
+			WindowViews:             count.Views,
+		})
+	}
+
+	return &response, nil
+}
+
+func (s *AnalyticsService) PostAnalytics(ctx context.Context, request *model.PostAnalyticsGet, auth *model.Auth) (*model.PostAnalyticsResponse, error) {
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error("Validation failed for post analytics", "error", err)
+		return nil, utility.ErrBadRequest
+	}
+
+	if request.Granularity == "" {
+		request.Granularity = constant.BucketDay
+	}
+	if request.To == 0 {
+		request.To = time.Now().Unix()
+	}
+	if request.From == 0 {
+		request.From = request.To - defaultAnalyticsLen
+	}
+	if request.From >= request.To || request.To-request.From > maxAnalyticsRange {
+		return nil, utility.ErrBadRequest
+	}
+
+	db := s.DB.WithContext(ctx)
+
+	post := new(entity.Post)
+	if err := s.UserRepository.IsAdmin(db, auth.ID); err != nil {
+		if err := s.PostRepository.FindByIDAndAuthorID(db, post, request.ID, auth.ID); err != nil {
+			slog.Error("Failed to find post by ID and author for analytics", "error", err)
+			return nil, utility.ErrNotFound
+		}
+	} else {
+		if err := s.PostRepository.FindByID(db, post, request.ID); err != nil {
+			slog.Error("Failed to find post by ID for analytics", "error", err)
+			return nil, utility.ErrNotFound
+		}
+	}
+
+	series, err := s.AnalyticsRepository.FindSeries(db, post.ID, request.Granularity, request.From, request.To)
+	if err != nil {
+		slog.Error("Failed to find post view series", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
+	referrers, err := s.AnalyticsRepository.FindTopReferrers(db, post.ID, request.From, request.To, topReferrersLimit)
+	if err != nil {
+		slog.Error("Failed to find post top referrers", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
+	categoryViews, err := s.AnalyticsRepository.SumCategoryViews(db, post.CategoryID, request.From, request.To)
+	if err != nil {
+		slog.Error("Failed to sum category views", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
+	categoryPosts, err := s.PostRepository.CountPublishedByCategoryID(db, post.CategoryID)
+	if err != nil {
+		slog.Error("Failed to count category posts", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
+	response := &model.PostAnalyticsResponse{
+		PostID:       post.ID,
+		Granularity:  request.Granularity,
+		From:         request.From,
+		To:           request.To,
+		TotalViews:   post.ViewCount,
+		Series:       series,
+		TopReferrers: referrers,
+	}
+	if response.Series == nil {
+		response.Series = []model.AnalyticsPoint{}
+	}
+	if response.TopReferrers == nil {
+		response.TopReferrers = []model.ReferrerCount{}
+	}
+	for _, point := range series {
+		response.WindowViews += point.Views
+	}
+	if categoryPosts > 0 {
+		response.CategoryAverage = float64(categoryViews) / float64(categoryPosts)
+	}
+
+	return response, nil
+}
+
+func parseWindow(window string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(window, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(window)
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"chrononewsapi/internal/config"
+	"chrononewsapi/internal/constant"
+	"chrononewsapi/internal/entity"
+	"chrononewsapi/internal/model"
+	"chrononewsapi/internal/repository"
+	"chrononewsapi/internal/utility"
+	"context"
+	"log/slog"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+type AuthorService struct {
+	DB             *gorm.DB
+	UserRepository *repository.UserRepository
+	PostService    *PostService
+	Validator      *validator.Validate
+	Config         *config.Config
+}
+
+func NewAuthorService(db *gorm.DB, userRepository *repository.UserRepository, postService *PostService, validator *validator.Validate, config *config.Config) *AuthorService {
+	return &AuthorService{
+		DB:             db,
+		UserRepository: userRepository,
+		PostService:    postService,
+		Validator:      validator,
+		Config:         config,
+	}
+}
+
+func (s *AuthorService) Get(ctx context.Context, request *model.AuthorGet) (*model.AuthorResponse, *model.Pagination, error) {
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error("Validation failed for author get", "error", err)
+		return nil, nil, utility.ErrBadRequest
+	}
+
+	db := s.DB.WithContext(ctx)
+
+	user := new(entity.User)
+	if err := s.UserRepository.FindBySlug(db, user, request.Slug); err != nil {
+		slog.Error("Failed to find author by slug", "error", err)
+		return nil, nil, utility.ErrNotFound
+	}
+
+	posts, pagination, err := s.PostService.Search(ctx, &model.PostSearch{
+		UserID: user.ID,
+		Page:   request.Page,
+		Size:   request.Size,
+		Sort:   "-created_at",
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var profilePicture string
+	var profilePictureVariants map[string]string
+	for _, file := range user.Files {
+		if file.Type == constant.FileTypeProfile {
+			profilePicture = utility.BuildImageURL(s.PostService.StorageAdapter, s.Config.Storage.Profile, file.Name)
+			profilePictureVariants = utility.BuildImageVariants(s.PostService.StorageAdapter, s.Config.Storage.Profile, &file)
+			break
+		}
+	}
+
+	return &model.AuthorResponse{
+		ID:                     user.ID,
+		Name:                   user.Name,
+		Slug:                   userSlug(user),
+		ProfilePicture:         profilePicture,
+		ProfilePictureVariants: profilePictureVariants,
+		JobTitle:               user.JobTitle,
+		Bio:                    user.Bio,
+		SocialLinks:            user.SocialLinks,
+		Posts:                  *posts,
+	}, pagination, nil
+}
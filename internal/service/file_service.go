@@ -1,6 +1,7 @@
 package service
 
 import (
+	"bytes"
 	"chrononewsapi/internal/adapter"
 	"chrononewsapi/internal/config"
 	"chrononewsapi/internal/constant"
@@ -9,45 +10,130 @@ import (
 	"chrononewsapi/internal/repository"
 	"chrononewsapi/internal/utility"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
 	"log/slog"
+	"math"
+	"mime"
 	"mime/multipart"
+	"net/http"
+	"net/url"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"gorm.io/gorm"
 )
 
+const (
+	uploadMaxDimension = 16383
+	uploadMaxFrames    = 300
+)
+
+var uploadExtensions = map[string][]string{
+	"image/png":  {".png"},
+	"image/jpeg": {".jpg", ".jpeg", ".jpe", ".jfif", ".jif", ".jfi"},
+	"image/gif":  {".gif"},
+	"image/webp": {".webp"},
+	"image/avif": {".avif"},
+}
+
+// mediaTypes maps the sniffed content type of a media upload to its kind and
+// the extensions it may be uploaded with.
+var mediaTypes = map[string]struct {
+	kind       string
+	extensions []string
+}{
+	"application/pdf": {constant.MediaKindDocument, []string{".pdf"}},
+	"audio/mpeg":      {constant.MediaKindAudio, []string{".mp3"}},
+	"audio/mp4":       {constant.MediaKindAudio, []string{".m4a"}},
+	"audio/ogg":       {constant.MediaKindAudio, []string{".ogg", ".oga", ".opus"}},
+	"audio/wave":      {constant.MediaKindAudio, []string{".wav"}},
+	"video/mp4":       {constant.MediaKindVideo, []string{".mp4", ".m4v"}},
+	"video/webm":      {constant.MediaKindVideo, []string{".webm"}},
+}
+
+var (
+	errUploadInfected     = utility.NewCustomError(http.StatusUnprocessableEntity, "File was rejected by the malware scanner")
+	errScannerUnavailable = utility.NewCustomError(http.StatusServiceUnavailable, "Malware scanner is unavailable, try again later")
+)
+
+var mediaMaxSizes = map[string]int64{
+	constant.MediaKindDocument: 20 << 20,
+	constant.MediaKindAudio:    50 << 20,
+	constant.MediaKindVideo:    200 << 20,
+}
+
 type FileService struct {
 	DB             *gorm.DB
 	FileRepository *repository.FileRepository
-	StorageAdapter *adapter.StorageAdapter
+	UserRepository *repository.UserRepository
+	StorageAdapter adapter.StorageAdapter
+	ScannerAdapter adapter.ScannerAdapter
+	UploadQuota    *UploadQuota
 	Config         *config.Config
 	Validator      *validator.Validate
 }
 
-func NewFileService(db *gorm.DB, fileRepository *repository.FileRepository, storageAdapter *adapter.StorageAdapter, config *config.Config, validator *validator.Validate) *FileService {
+func NewFileService(db *gorm.DB, fileRepository *repository.FileRepository, userRepository *repository.UserRepository, storageAdapter adapter.StorageAdapter, scannerAdapter adapter.ScannerAdapter, uploadQuota *UploadQuota, config *config.Config, validator *validator.Validate) *FileService {
 	return &FileService{
 		DB:             db,
 		FileRepository: fileRepository,
+		UserRepository: userRepository,
 		StorageAdapter: storageAdapter,
+		ScannerAdapter: scannerAdapter,
+		UploadQuota:    uploadQuota,
 		Config:         config,
 		Validator:      validator,
 	}
 }
 
-func (s *FileService) UploadImage(ctx context.Context, fileHeader *multipart.FileHeader) (*model.ImageUploadResponse, error) {
+func (s *FileService) UploadImage(ctx context.Context, fileHeader *multipart.FileHeader, auth *model.Auth) (*model.ImageUploadResponse, error) {
 	uploadValidation := &model.FileUpload{File: fileHeader}
 	if err := s.Validator.Struct(uploadValidation); err != nil {
 		slog.Error("Validation failed for image upload", "error", err)
 		return nil, utility.ErrBadRequest
 	}
 
+	data, err := readUploadedImage(s.Config, fileHeader)
+	if err != nil {
+		slog.Error("Failed to sanitize uploaded image", "error", err)
+		return nil, utility.ErrBadRequest
+	}
+
+	if err := s.UploadQuota.Check(s.DB.WithContext(ctx), auth.ID, int64(len(data))); err != nil {
+		return nil, err
+	}
+	if err := scanUpload(ctx, s.ScannerAdapter, bytes.NewReader(data), fileHeader.Filename, auth.ID); err != nil {
+		return nil, err
+	}
+
+	hash := hashImage(data)
+	if response, ok := s.reuseUpload(ctx, hash, auth); ok {
+		s.UploadQuota.Record(s.DB.WithContext(ctx), auth.ID, int64(len(data)))
+		return response, nil
+	}
+
 	fileName := utility.CreateFileName(fileHeader)
 
 	fileEntity := entity.File{
-		Name:   fileName,
-		Status: constant.FileStatusPending,
-		Type:   constant.FileTypeAttachment,
+		Name:         fileName,
+		Status:       constant.FileStatusPrivate,
+		Type:         constant.FileTypeAttachment,
+		UploadedByID: &auth.ID,
+		Size:         int64(len(data)),
+		Hash:         hash,
+	}
+	if imageConfig, _, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+		fileEntity.Width = imageConfig.Width
+		fileEntity.Height = imageConfig.Height
 	}
 
 	if err := s.FileRepository.Create(s.DB.WithContext(ctx), &fileEntity); err != nil {
@@ -55,8 +141,8 @@ func (s *FileService) UploadImage(ctx context.Context, fileHeader *multipart.Fil
 		return nil, utility.ErrInternalServer
 	}
 
-	destinationPath := filepath.Join(s.Config.Storage.Attachment, fileName)
-	if err := s.StorageAdapter.Store(fileHeader, destinationPath); err != nil {
+	destinationPath := filepath.Join(s.Config.Storage.Private, fileName)
+	if err := s.StorageAdapter.Put(ctx, destinationPath, bytes.NewReader(data), config.DetectImageType(data)); err != nil {
 		slog.Error("Failed to store file to storage", "error", err)
 
 		if delErr := s.FileRepository.Delete(s.DB.WithContext(ctx), &fileEntity); delErr != nil {
@@ -64,9 +150,897 @@ func (s *FileService) UploadImage(ctx context.Context, fileHeader *multipart.Fil
 		}
 		return nil, utility.ErrInternalServer
 	}
+	s.UploadQuota.Record(s.DB.WithContext(ctx), auth.ID, fileEntity.Size)
+
+	return &model.ImageUploadResponse{
+		ID:   fileEntity.ID,
+		Name: utility.BuildFileURL(s.StorageAdapter, s.Config, &fileEntity),
+	}, nil
+}
+
+// UploadMedia stores a document, audio clip or video for embedding in post
+// content. Media is served as uploaded, so it becomes compressed rather than
+// pending when it is published, keeping the image processor from picking it
+// up.
+func (s *FileService) UploadMedia(ctx context.Context, fileHeader *multipart.FileHeader, auth *model.Auth) (*model.MediaUploadResponse, error) {
+	if err := s.Validator.Struct(&model.MediaUpload{File: fileHeader}); err != nil {
+		slog.Error("Validation failed for media upload", "error", err)
+		return nil, utility.ErrBadRequest
+	}
+
+	fileOpened, err := fileHeader.Open()
+	if err != nil {
+		slog.Error("Failed to open uploaded media", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
+	defer func() {
+		if cerr := fileOpened.Close(); cerr != nil {
+			slog.Warn("Error closing uploaded file", "error", cerr)
+		}
+	}()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(fileOpened, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		slog.Error("Failed to read uploaded media", "error", err)
+		return nil, utility.ErrBadRequest
+	}
+
+	mimeType := utility.DetectMediaType(head[:n])
+	mediaType, ok := mediaTypes[mimeType]
+	if !ok {
+		return nil, utility.NewCustomError(http.StatusBadRequest, "File is not a supported document, audio or video format")
+	}
+	if !slices.Contains(mediaType.extensions, strings.ToLower(filepath.Ext(fileHeader.Filename))) {
+		return nil, utility.NewCustomError(http.StatusBadRequest, "File content does not match its extension")
+	}
+	if fileHeader.Size > mediaMaxSizes[mediaType.kind] {
+		return nil, utility.ErrRequestEntityTooLarge
+	}
+	if err := s.UploadQuota.Check(s.DB.WithContext(ctx), auth.ID, fileHeader.Size); err != nil {
+		return nil, err
+	}
+	if err := scanUpload(ctx, s.ScannerAdapter, io.NewSectionReader(fileOpened, 0, fileHeader.Size), fileHeader.Filename, auth.ID); err != nil {
+		return nil, err
+	}
+
+	fileEntity := entity.File{
+		Name:         utility.CreateFileName(fileHeader),
+		Status:       constant.FileStatusPrivate,
+		Type:         constant.FileTypeAttachment,
+		Kind:         mediaType.kind,
+		MimeType:     mimeType,
+		UploadedByID: &auth.ID,
+		Size:         fileHeader.Size,
+	}
+
+	if mediaType.kind == constant.MediaKindDocument {
+		data, err := io.ReadAll(io.NewSectionReader(fileOpened, 0, fileHeader.Size))
+		if err != nil {
+			slog.Error("Failed to read uploaded document", "error", err)
+			return nil, utility.ErrInternalServer
+		}
+		fileEntity.Pages = utility.PDFPageCount(data)
+	} else {
+		fileEntity.Duration = utility.MediaDuration(fileOpened, fileHeader.Size, mimeType)
+	}
+
+	if err := s.FileRepository.Create(s.DB.WithContext(ctx), &fileEntity); err != nil {
+		slog.Error("Failed to create file record in database", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
+	destinationPath := filepath.Join(s.Config.Storage.Private, fileEntity.Name)
+	if err := s.StorageAdapter.Put(ctx, destinationPath, io.NewSectionReader(fileOpened, 0, fileHeader.Size), mimeType); err != nil {
+		slog.Error("Failed to store media to storage", "error", err)
+
+		if delErr := s.FileRepository.Delete(s.DB.WithContext(ctx), &fileEntity); delErr != nil {
+			slog.Error("Failed to delete file record after storage failure", "error", delErr)
+		}
+		return nil, utility.ErrInternalServer
+	}
+	s.UploadQuota.Record(s.DB.WithContext(ctx), auth.ID, fileEntity.Size)
+
+	return &model.MediaUploadResponse{
+		ID:       fileEntity.ID,
+		Name:     utility.BuildFileURL(s.StorageAdapter, s.Config, &fileEntity),
+		Kind:     fileEntity.Kind,
+		MimeType: fileEntity.MimeType,
+		Size:     fileEntity.Size,
+		Duration: fileEntity.Duration,
+		Pages:    fileEntity.Pages,
+	}, nil
+}
+
+// reuseUpload creates a new file pointing at the stored object of an earlier
+// upload with the same content. Only processed or still private files are
+// reused, so the new file never shares an object the processor is working on.
+func (s *FileService) reuseUpload(ctx context.Context, hash string, auth *model.Auth) (*model.ImageUploadResponse, bool) {
+	db := s.DB.WithContext(ctx)
+
+	existing, err := s.FileRepository.FindByHash(db, hash, constant.FileTypeAttachment)
+	if err != nil {
+		return nil, false
+	}
+
+	path := filepath.Join(utility.FileObjectFolder(s.Config, existing), existing.Name)
+	if _, err := s.StorageAdapter.Stat(ctx, path); err != nil {
+		slog.Warn("Stored object for duplicate upload is unavailable", "fileID", existing.ID, "error", err)
+		return nil, false
+	}
+
+	fileEntity := entity.File{
+		Name:         existing.Name,
+		Status:       existing.Status,
+		Type:         constant.FileTypeAttachment,
+		UploadedByID: &auth.ID,
+		Size:         existing.Size,
+		Hash:         hash,
+		Width:        existing.Width,
+		Height:       existing.Height,
+		Variants:     existing.Variants,
+	}
+
+	if err := s.FileRepository.Create(db, &fileEntity); err != nil {
+		slog.Error("Failed to create file record for duplicate upload", "error", err)
+		return nil, false
+	}
 
 	return &model.ImageUploadResponse{
 		ID:   fileEntity.ID,
-		Name: utility.BuildImageURL(s.Config, s.Config.Storage.Attachment, fileEntity.Name),
+		Name: utility.BuildFileURL(s.StorageAdapter, s.Config, &fileEntity),
+	}, true
+}
+
+func (s *FileService) PresignUpload(ctx context.Context, request *model.FilePresign, auth *model.Auth) (*model.FilePresignResponse, error) {
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error("Validation failed for presigned upload", "error", err)
+		return nil, utility.ErrBadRequest
+	}
+
+	extension := strings.ToLower(filepath.Ext(request.FileName))
+	if !slices.Contains(uploadExtensions[request.ContentType], extension) {
+		return nil, utility.NewCustomError(http.StatusBadRequest, "File extension does not match content type")
+	}
+	if err := s.UploadQuota.Check(s.DB.WithContext(ctx), auth.ID, request.Size); err != nil {
+		return nil, err
+	}
+
+	fileEntity := entity.File{
+		Name:         utility.CreateFileNameFromOriginal(request.FileName),
+		Status:       constant.FileStatusUploading,
+		Type:         constant.FileTypeAttachment,
+		UploadedByID: &auth.ID,
+		Size:         request.Size,
+		ScanStatus:   constant.ScanStatusPending,
+	}
+
+	if err := s.FileRepository.Create(s.DB.WithContext(ctx), &fileEntity); err != nil {
+		slog.Error("Failed to create file record for presigned upload", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
+	ttl := time.Duration(s.Config.Storage.UploadTTL) * time.Second
+	destinationPath := uploadObjectPath(s.Config, &fileEntity)
+
+	uploadURL, err := s.StorageAdapter.PresignPut(ctx, destinationPath, request.ContentType, request.Size, ttl)
+	if errors.Is(err, adapter.ErrPresignNotSupported) {
+		uploadURL, err = s.directUploadURL(fileEntity.ID, destinationPath, request.ContentType, request.Size, ttl)
+	}
+	if err != nil {
+		slog.Error("Failed to presign upload", "error", err)
+
+		if delErr := s.FileRepository.Delete(s.DB.WithContext(ctx), &fileEntity); delErr != nil {
+			slog.Error("Failed to delete file record after presign failure", "error", delErr)
+		}
+		return nil, utility.ErrInternalServer
+	}
+
+	return &model.FilePresignResponse{
+		ID:        fileEntity.ID,
+		UploadURL: uploadURL,
+		Method:    http.MethodPut,
+		Headers: map[string]string{
+			"Content-Type": request.ContentType,
+		},
+		ExpiresAt: time.Now().Add(ttl).Unix(),
+	}, nil
+}
+
+// ConfirmUpload checks an object written through a presigned URL before it is
+// handed to the image processor. Anything that fails the checks is removed.
+func (s *FileService) ConfirmUpload(ctx context.Context, request *model.FileConfirm, auth *model.Auth) (*model.ImageUploadResponse, error) {
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error("Validation failed for upload confirm", "error", err)
+		return nil, utility.ErrBadRequest
+	}
+
+	db := s.DB.WithContext(ctx)
+
+	file, err := s.FileRepository.FindByID(db, request.ID)
+	if err != nil {
+		slog.Error("Failed to find file by ID for upload confirm", "error", err)
+		return nil, utility.ErrNotFound
+	}
+
+	if file.UploadedByID == nil || *file.UploadedByID != auth.ID {
+		return nil, utility.ErrNotFound
+	}
+
+	if file.Status != constant.FileStatusUploading {
+		return nil, utility.NewCustomError(http.StatusConflict, "File upload is already confirmed")
+	}
+
+	uploadPath := uploadObjectPath(s.Config, file)
+
+	info, err := s.StorageAdapter.Stat(ctx, uploadPath)
+	if err != nil {
+		slog.Error("Failed to stat uploaded file", "error", err)
+		return nil, utility.NewCustomError(http.StatusBadRequest, "File has not been uploaded")
+	}
+
+	// The upload URL stays valid until it expires, so the object is read once
+	// and the checks, the scan and the stored copy all use those bytes.
+	data, reason := s.readUpload(ctx, uploadPath, info.Size, file)
+	if reason == "" {
+		data, reason = s.inspectUpload(data, file)
+	}
+	if reason != "" {
+		s.discardUpload(ctx, db, uploadPath, file)
+		return nil, utility.NewCustomError(http.StatusBadRequest, reason)
+	}
+
+	if err := scanUpload(ctx, s.ScannerAdapter, bytes.NewReader(data), file.Name, auth.ID); err != nil {
+		if err == errUploadInfected {
+			s.discardUpload(ctx, db, uploadPath, file)
+		}
+		return nil, err
+	}
+
+	path := filepath.Join(s.Config.Storage.Private, file.Name)
+	if err := s.StorageAdapter.Put(ctx, path, bytes.NewReader(data), config.DetectImageType(data)); err != nil {
+		slog.Error("Failed to store confirmed upload", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
+	file.Status = constant.FileStatusPrivate
+	file.ScanStatus = constant.ScanStatusClean
+	if err := s.FileRepository.MarkUploaded(db, file); err != nil {
+		slog.Error("Failed to mark file as uploaded", "error", err)
+		if err := s.StorageAdapter.Delete(ctx, path); err != nil {
+			slog.Error("Failed to delete stored copy of upload", "error", err)
+		}
+		return nil, utility.ErrInternalServer
+	}
+	s.UploadQuota.Record(db, auth.ID, file.Size)
+
+	if err := s.StorageAdapter.Delete(ctx, uploadPath); err != nil {
+		slog.Warn("Failed to delete confirmed upload object", "error", err)
+	}
+
+	return &model.ImageUploadResponse{
+		ID:   file.ID,
+		Name: utility.BuildFileURL(s.StorageAdapter, s.Config, file),
 	}, nil
 }
+
+// discardUpload removes a presigned upload that failed its checks.
+func (s *FileService) discardUpload(ctx context.Context, db *gorm.DB, uploadPath string, file *entity.File) {
+	if err := s.StorageAdapter.Delete(ctx, uploadPath); err != nil {
+		slog.Error("Failed to delete rejected upload from storage", "error", err)
+	}
+	if err := s.FileRepository.Delete(db, file); err != nil {
+		slog.Error("Failed to delete rejected upload record", "error", err)
+	}
+}
+
+// readUpload reads an object written through a presigned URL, returning the
+// reason for rejection when it is not the declared size.
+func (s *FileService) readUpload(ctx context.Context, path string, size int64, file *entity.File) ([]byte, string) {
+	if size != file.Size {
+		return nil, "Uploaded size does not match the declared size"
+	}
+
+	body, err := s.StorageAdapter.Get(ctx, path)
+	if err != nil {
+		slog.Error("Failed to read uploaded file", "error", err)
+		return nil, "File could not be read"
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, file.Size+1))
+	if cerr := body.Close(); cerr != nil {
+		slog.Warn("Error closing storage object", "error", cerr)
+	}
+	if err != nil {
+		slog.Error("Failed to read uploaded file", "error", err)
+		return nil, "File could not be read"
+	}
+	if int64(len(data)) != file.Size {
+		return nil, "Uploaded size does not match the declared size"
+	}
+
+	return data, ""
+}
+
+// inspectUpload checks the bytes of a presigned upload and strips their
+// metadata, the same way multipart uploads are stripped before storing. It
+// returns the sanitized image and fills in its dimensions, size and hash on
+// success, and returns the reason for rejection otherwise.
+func (s *FileService) inspectUpload(original []byte, file *entity.File) ([]byte, string) {
+	contentType := config.DetectImageType(original)
+	if _, ok := uploadExtensions[contentType]; !ok {
+		return nil, "File is not a supported image"
+	}
+	if !slices.Contains(uploadExtensions[contentType], strings.ToLower(filepath.Ext(file.Name))) {
+		return nil, "File content does not match its extension"
+	}
+
+	imageConfig, _, err := image.DecodeConfig(bytes.NewReader(original))
+	if err != nil {
+		return nil, "File is not a supported image"
+	}
+	if imageConfig.Width > uploadMaxDimension || imageConfig.Height > uploadMaxDimension {
+		return nil, "Image dimensions are too large"
+	}
+	if config.ImageFrames(original) > uploadMaxFrames {
+		return nil, "Image has too many frames"
+	}
+
+	data, err := utility.SanitizeImage(original, s.Config.Storage.KeepCopyright)
+	if err != nil {
+		return nil, "File is not a supported image"
+	}
+
+	imageConfig, _, err = image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "File is not a supported image"
+	}
+
+	file.Width = imageConfig.Width
+	file.Height = imageConfig.Height
+	file.Size = int64(len(data))
+	file.Hash = hashImage(data)
+	return data, ""
+}
+
+// directUploadURL signs an upload token for backends that cannot presign
+// requests themselves. The body is then received by DirectUpload.
+func (s *FileService) directUploadURL(fileID int32, path string, contentType string, size int64, ttl time.Duration) (string, error) {
+	token, err := utility.CreateUploadToken(s.Config.JWT.Secret, utility.UploadClaims{
+		FileID:      fileID,
+		Path:        path,
+		ContentType: contentType,
+		Size:        size,
+	}, time.Now().Add(ttl))
+	if err != nil {
+		return "", err
+	}
+
+	return s.Config.Web.BaseURL + "/api/file/upload/" + url.PathEscape(token), nil
+}
+
+// DirectUpload receives the object body for a presigned URL issued by a
+// backend without native presigning, standing in for the S3 endpoint. The
+// body is refused once the upload has been confirmed or discarded.
+func (s *FileService) DirectUpload(ctx context.Context, request *model.FileDirectUpload, body io.Reader) error {
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error("Validation failed for direct upload", "error", err)
+		return utility.ErrBadRequest
+	}
+
+	claims, err := utility.ValidateUploadToken(s.Config.JWT.Secret, request.Token)
+	if err != nil {
+		slog.Error("Invalid upload token", "error", err)
+		return utility.ErrForbidden
+	}
+
+	if request.ContentType != claims.ContentType {
+		return utility.NewCustomError(http.StatusBadRequest, "Content-Type does not match the presigned upload")
+	}
+	if request.ContentLength != claims.Size {
+		return utility.NewCustomError(http.StatusBadRequest, "Content-Length does not match the presigned upload")
+	}
+
+	file, err := s.FileRepository.FindByID(s.DB.WithContext(ctx), claims.FileID)
+	if err != nil || uploadObjectPath(s.Config, file) != claims.Path {
+		return utility.ErrForbidden
+	}
+	if file.Status != constant.FileStatusUploading {
+		return utility.NewCustomError(http.StatusConflict, "File upload is already confirmed")
+	}
+
+	if err := s.StorageAdapter.Put(ctx, claims.Path, io.LimitReader(body, claims.Size), claims.ContentType); err != nil {
+		slog.Error("Failed to store direct upload", "error", err)
+		return utility.ErrInternalServer
+	}
+
+	return nil
+}
+
+// WebPVariant returns the name of the full-size WebP variant the image
+// processor recorded for the file stored under name, or "" when it has none.
+func (s *FileService) WebPVariant(ctx context.Context, name string) string {
+	file, err := s.FileRepository.FindByName(s.DB.WithContext(ctx), name)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			slog.Error("Failed to find file by name for WebP variant", "name", name, "error", err)
+		}
+		return ""
+	}
+
+	for _, variant := range file.Variants {
+		if variant.Format == constant.ImageFormatWebP && variant.File != "" && variant.Width == file.Width {
+			return variant.File
+		}
+	}
+	return ""
+}
+
+// OpenPrivate returns the object a signed private file URL points to, along
+// with its content type. It is only used when storage cannot presign reads.
+func (s *FileService) OpenPrivate(ctx context.Context, request *model.FilePrivate) (io.ReadCloser, string, error) {
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error("Validation failed for private file", "error", err)
+		return nil, "", utility.ErrBadRequest
+	}
+
+	path, err := utility.ValidateMediaToken(s.Config.JWT.Secret, request.Token)
+	if err != nil {
+		slog.Error("Invalid media token", "error", err)
+		return nil, "", utility.ErrForbidden
+	}
+	if !strings.HasPrefix(adapter.ObjectKey(path), adapter.ObjectKey(s.Config.Storage.Private)+"/") {
+		return nil, "", utility.ErrForbidden
+	}
+
+	body, err := s.StorageAdapter.Get(ctx, path)
+	if err != nil {
+		slog.Error("Failed to open private file", "path", path, "error", err)
+		return nil, "", utility.ErrNotFound
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(path))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	return body, contentType, nil
+}
+
+func (s *FileService) Search(ctx context.Context, request *model.FileSearch, auth *model.Auth) (*[]model.FileResponse, *model.Pagination, error) {
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error("Validation failed for file search", "error", err)
+		return nil, nil, utility.ErrBadRequest
+	}
+
+	db := s.DB.WithContext(ctx)
+
+	if err := s.UserRepository.IsAdmin(db, auth.ID); err != nil {
+		request.UploadedBy = auth.ID
+	}
+
+	var files []entity.File
+	total, err := s.FileRepository.SearchLibrary(db, request, &files)
+	if err != nil {
+		slog.Error("Failed to search files", "error", err)
+		return nil, nil, utility.ErrInternalServer
+	}
+
+	if len(files) == 0 {
+		return &[]model.FileResponse{}, &model.Pagination{}, nil
+	}
+
+	response := make([]model.FileResponse, len(files))
+	for i := range files {
+		response[i] = *s.toFileResponse(&files[i])
+	}
+
+	pagination := &model.Pagination{
+		TotalItem: total,
+	}
+
+	if request.Page != 0 && request.Size != 0 {
+		pagination.Page = request.Page
+		pagination.Size = request.Size
+		pagination.TotalPage = int64(math.Ceil(float64(total) / float64(request.Size)))
+	} else {
+		pagination.TotalPage = 1
+	}
+
+	return &response, pagination, nil
+}
+
+func (s *FileService) Get(ctx context.Context, request *model.FileGet, auth *model.Auth) (*model.FileResponse, error) {
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error("Validation failed for file get", "error", err)
+		return nil, utility.ErrBadRequest
+	}
+
+	db := s.DB.WithContext(ctx)
+
+	file := new(entity.File)
+	if err := s.FileRepository.FindByIDWithRelations(db, file, request.ID); err != nil {
+		slog.Error("Failed to find file by ID", "error", err)
+		return nil, utility.ErrNotFound
+	}
+
+	if !s.canAccess(db, file, auth) {
+		return nil, utility.ErrNotFound
+	}
+
+	return s.toFileResponse(file), nil
+}
+
+func (s *FileService) UpdateMetadata(ctx context.Context, request *model.FileMetadataUpdate, auth *model.Auth) (*model.FileResponse, error) {
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error("Validation failed for file metadata update", "error", err)
+		return nil, utility.ErrBadRequest
+	}
+
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	file := new(entity.File)
+	if err := s.FileRepository.FindByIDWithRelations(tx, file, request.ID); err != nil {
+		slog.Error("Failed to find file by ID for metadata update", "error", err)
+		return nil, utility.ErrNotFound
+	}
+
+	if !s.canAccess(tx, file, auth) {
+		return nil, utility.ErrNotFound
+	}
+
+	file.AltText = strings.TrimSpace(request.AltText)
+	file.Caption = strings.TrimSpace(request.Caption)
+	file.Credit = strings.TrimSpace(request.Credit)
+	file.License = strings.TrimSpace(request.License)
+
+	if err := s.FileRepository.UpdateMetadata(tx, file); err != nil {
+		slog.Error("Failed to update file metadata", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error("Failed to commit transaction for file metadata update", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
+	return s.toFileResponse(file), nil
+}
+
+// UpdateCrop sets the focal point and crop rectangle used to compute the crop
+// hints of an image. The crop must lie inside the original.
+func (s *FileService) UpdateCrop(ctx context.Context, request *model.FileCropUpdate, auth *model.Auth) (*model.FileResponse, error) {
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error("Validation failed for file crop update", "error", err)
+		return nil, utility.ErrBadRequest
+	}
+
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	file := new(entity.File)
+	if err := s.FileRepository.FindByIDWithRelations(tx, file, request.ID); err != nil {
+		slog.Error("Failed to find file by ID for crop update", "error", err)
+		return nil, utility.ErrNotFound
+	}
+
+	if !s.canAccess(tx, file, auth) {
+		return nil, utility.ErrNotFound
+	}
+
+	if file.Kind != constant.MediaKindImage {
+		return nil, utility.NewCustomError(http.StatusBadRequest, "Only images can be cropped")
+	}
+
+	file.FocalX = request.FocalX
+	file.FocalY = request.FocalY
+	file.Crop = nil
+	if request.Crop != nil {
+		if file.Width == 0 || file.Height == 0 {
+			return nil, utility.NewCustomError(http.StatusConflict, "Image dimensions are not known yet")
+		}
+		if request.Crop.X+request.Crop.Width > file.Width || request.Crop.Y+request.Crop.Height > file.Height {
+			return nil, utility.NewCustomError(http.StatusBadRequest, "Crop rectangle is outside the image")
+		}
+		file.Crop = &entity.FileCrop{
+			X:      request.Crop.X,
+			Y:      request.Crop.Y,
+			Width:  request.Crop.Width,
+			Height: request.Crop.Height,
+		}
+	}
+
+	if err := s.FileRepository.UpdateCrop(tx, file); err != nil {
+		slog.Error("Failed to update file crop", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error("Failed to commit transaction for file crop update", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
+	return s.toFileResponse(file), nil
+}
+
+func (s *FileService) Delete(ctx context.Context, request *model.FileDelete, auth *model.Auth) error {
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error("Validation failed for file delete", "error", err)
+		return utility.ErrBadRequest
+	}
+
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	file, err := s.FileRepository.FindByID(tx, request.ID)
+	if err != nil {
+		slog.Error("Failed to find file by ID for delete", "error", err)
+		return utility.ErrNotFound
+	}
+
+	if !s.canAccess(tx, file, auth) {
+		return utility.ErrNotFound
+	}
+
+	if file.UsedByPostID != nil || file.UsedByUserID != nil {
+		return utility.NewCustomError(http.StatusConflict, "File is in use")
+	}
+
+	var sources []entity.SourceFileToDelete
+	if err := s.FileRepository.FindSourceFiles(tx, file.ID, &sources); err != nil {
+		slog.Error("Failed to find source files for delete", "error", err)
+		return utility.ErrInternalServer
+	}
+
+	shared, err := s.FileRepository.CountSharing(tx, file)
+	if err != nil {
+		slog.Error("Failed to count files sharing the stored object", "error", err)
+		return utility.ErrInternalServer
+	}
+
+	if err := s.FileRepository.Discard(tx, file); err != nil {
+		slog.Error("Failed to delete file", "error", err)
+		return utility.ErrInternalServer
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error("Failed to commit transaction for file delete", "error", err)
+		return utility.ErrInternalServer
+	}
+
+	deleteFileObjects(ctx, s.StorageAdapter, s.Config, file, shared > 0, sources)
+
+	return nil
+}
+
+func (s *FileService) canAccess(db *gorm.DB, file *entity.File, auth *model.Auth) bool {
+	if file.UploadedByID != nil && *file.UploadedByID == auth.ID {
+		return true
+	}
+	return s.UserRepository.IsAdmin(db, auth.ID) == nil
+}
+
+func (s *FileService) toFileResponse(file *entity.File) *model.FileResponse {
+	response := &model.FileResponse{
+		ID:        file.ID,
+		Name:      file.Name,
+		URL:       utility.BuildFileURL(s.StorageAdapter, s.Config, file),
+		Variants:  utility.BuildFileVariants(s.StorageAdapter, s.Config, file),
+		Type:      file.Type,
+		Kind:      file.Kind,
+		MimeType:  file.MimeType,
+		Size:      file.Size,
+		Duration:  file.Duration,
+		Pages:     file.Pages,
+		Width:     file.Width,
+		Height:    file.Height,
+		FocalX:    file.FocalX,
+		FocalY:    file.FocalY,
+		Crops:     utility.BuildImageCrops(file),
+		Status:    file.Status,
+		AltText:   file.AltText,
+		Caption:   file.Caption,
+		Credit:    file.Credit,
+		License:   file.License,
+		CreatedAt: file.CreatedAt,
+		UpdatedAt: file.UpdatedAt,
+	}
+
+	if file.Crop != nil {
+		response.Crop = &model.ImageCrop{
+			X:      file.Crop.X,
+			Y:      file.Crop.Y,
+			Width:  file.Crop.Width,
+			Height: file.Crop.Height,
+		}
+	}
+
+	if file.Uploader != nil {
+		response.Uploader = &model.UserPublicResponse{
+			ID:   file.Uploader.ID,
+			Name: file.Uploader.Name,
+			Slug: userSlug(file.Uploader),
+		}
+	}
+
+	if file.Post != nil {
+		response.Post = &model.FilePostResponse{
+			ID:    file.Post.ID,
+			Title: file.Post.Title,
+		}
+	}
+
+	if file.User != nil {
+		response.User = &model.UserPublicResponse{
+			ID:   file.User.ID,
+			Name: file.User.Name,
+			Slug: userSlug(file.User),
+		}
+	}
+
+	return response
+}
+
+// uploadObjectPath returns where a presigned upload is written. Confirmed
+// uploads are copied to their own key, which the upload URL cannot reach.
+func uploadObjectPath(cfg *config.Config, file *entity.File) string {
+	return filepath.Join(cfg.Storage.Private, "upload-"+file.Name)
+}
+
+// fileObjectPaths returns the storage paths of a file, starting with the
+// original and followed by its processed variants.
+func fileObjectPaths(cfg *config.Config, file *entity.File) []string {
+	if file.Status == constant.FileStatusUploading {
+		return []string{uploadObjectPath(cfg, file)}
+	}
+
+	folder := utility.FileObjectFolder(cfg, file)
+
+	paths := []string{filepath.Join(folder, file.Name)}
+	for _, variant := range file.Variants {
+		if variant.File != "" {
+			paths = append(paths, filepath.Join(folder, variant.File))
+		}
+	}
+	return paths
+}
+
+// publishPostFiles moves the private files of a post to the public folder of
+// their type and queues images for processing. A file that cannot be moved
+// stays private and is picked up again the next time the post is saved or
+// published. The private object is kept while other private files share it.
+func publishPostFiles(ctx context.Context, db *gorm.DB, fileRepository *repository.FileRepository, storage adapter.StorageAdapter, cfg *config.Config, postID int32) {
+	var files []entity.File
+	if err := fileRepository.FindPrivateByPost(db, postID, &files); err != nil {
+		slog.Error("Failed to find private files of post", "postID", postID, "error", err)
+		return
+	}
+
+	for i := range files {
+		file := &files[i]
+		source := filepath.Join(cfg.Storage.Private, file.Name)
+
+		shared, err := fileRepository.CountPrivateSharing(db, file)
+		if err != nil {
+			slog.Error("Failed to count files sharing a private object", "fileID", file.ID, "error", err)
+			continue
+		}
+
+		// The processor renames the object it works on, so a private object
+		// shared by several files is published under a new name for each but
+		// the last of them.
+		name := file.Name
+		if shared > 0 {
+			name = utility.CreateFileNameFromOriginal(file.Name)
+		}
+		destination := filepath.Join(utility.FileFolder(cfg, file.Type), name)
+
+		if err := storage.Copy(ctx, source, destination); err != nil {
+			slog.Error("Failed to move private file to public storage", "fileID", file.ID, "error", err)
+			continue
+		}
+
+		status := constant.FileStatusPending
+		if file.Kind != "" && file.Kind != constant.MediaKindImage {
+			status = constant.FileStatusCompressed
+		}
+		if err := fileRepository.Publish(db, file, name, status); err != nil {
+			slog.Error("Failed to mark file as published", "fileID", file.ID, "error", err)
+			continue
+		}
+
+		if shared > 0 {
+			continue
+		}
+		if err := storage.Delete(ctx, source); err != nil {
+			slog.Warn("Failed to delete private copy of published file", "fileID", file.ID, "error", err)
+		}
+	}
+}
+
+// readUploadedImage reads an uploaded image with its privacy-sensitive
+// metadata removed and its EXIF orientation applied to the pixels.
+func readUploadedImage(cfg *config.Config, fileHeader *multipart.FileHeader) ([]byte, error) {
+	fileOpened, err := fileHeader.Open()
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if cerr := fileOpened.Close(); cerr != nil {
+			slog.Warn("Error closing uploaded file", "error", cerr)
+		}
+	}()
+
+	data, err := io.ReadAll(fileOpened)
+	if err != nil {
+		return nil, err
+	}
+
+	return utility.SanitizeImage(data, cfg.Storage.KeepCopyright)
+}
+
+// storeUploadedImage stores an uploaded image after removing its metadata and
+// scanning it, and records the stored size and dimensions on file.
+func storeUploadedImage(ctx context.Context, storage adapter.StorageAdapter, scanner adapter.ScannerAdapter, cfg *config.Config, fileHeader *multipart.FileHeader, path string, file *entity.File) error {
+	data, err := readUploadedImage(cfg, fileHeader)
+	if err != nil {
+		return err
+	}
+
+	var uploadedBy int32
+	if file.UploadedByID != nil {
+		uploadedBy = *file.UploadedByID
+	}
+	if err := scanUpload(ctx, scanner, bytes.NewReader(data), fileHeader.Filename, uploadedBy); err != nil {
+		return err
+	}
+
+	if imageConfig, _, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+		file.Width = imageConfig.Width
+		file.Height = imageConfig.Height
+	}
+	file.Size = int64(len(data))
+
+	return storage.Put(ctx, path, bytes.NewReader(data), config.DetectImageType(data))
+}
+
+// scanUpload passes an upload through the malware scanner. Infected uploads
+// are logged and rejected with errUploadInfected.
+func scanUpload(ctx context.Context, scanner adapter.ScannerAdapter, r io.Reader, name string, uploadedBy int32) error {
+	result, err := scanner.Scan(ctx, r)
+	if err != nil {
+		slog.Error("Failed to scan upload for malware", "file", name, "error", err)
+		return errScannerUnavailable
+	}
+	if !result.Clean {
+		slog.Warn("Rejected infected upload", "file", name, "uploadedBy", uploadedBy, "signature", result.Signature)
+		return errUploadInfected
+	}
+	return nil
+}
+
+func hashImage(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// deleteFileObjects removes the stored objects of a discarded file. The
+// original and its variants are kept while other files still reference them;
+// leftovers are only logged since the rows are already gone.
+func deleteFileObjects(ctx context.Context, storage adapter.StorageAdapter, cfg *config.Config, file *entity.File, shared bool, sources []entity.SourceFileToDelete) {
+	var paths []string
+	if !shared {
+		paths = fileObjectPaths(cfg, file)
+	}
+	for _, source := range sources {
+		paths = append(paths, source.SourcePath)
+	}
+	for _, path := range paths {
+		if err := storage.Delete(ctx, path); err != nil {
+			slog.Error("Failed to delete file from storage", "path", path, "error", err)
+		}
+	}
+}
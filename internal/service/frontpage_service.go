@@ -0,0 +1,206 @@
+package service
+
+import (
+	"chrononewsapi/internal/constant"
+	"chrononewsapi/internal/entity"
+	"chrononewsapi/internal/model"
+	"chrononewsapi/internal/repository"
+	"chrononewsapi/internal/utility"
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+type FrontpageService struct {
+	DB                  *gorm.DB
+	FrontpageRepository *repository.FrontpageRepository
+	PostRepository      *repository.PostRepository
+	UserRepository      *repository.UserRepository
+	PostService         *PostService
+	Validator           *validator.Validate
+}
+
+func NewFrontpageService(db *gorm.DB, frontpageRepository *repository.FrontpageRepository, postRepository *repository.PostRepository, userRepository *repository.UserRepository, postService *PostService, validator *validator.Validate) *FrontpageService {
+	return &FrontpageService{
+		DB:                  db,
+		FrontpageRepository: frontpageRepository,
+		PostRepository:      postRepository,
+		UserRepository:      userRepository,
+		PostService:         postService,
+		Validator:           validator,
+	}
+}
+
+func (s *FrontpageService) Get(ctx context.Context) (*model.FrontpageResponse, error) {
+	db := s.DB.WithContext(ctx)
+
+	var breaking []entity.Post
+	if err := s.PostRepository.FindBreaking(db, &breaking, time.Now().Unix()); err != nil {
+		slog.Error("Failed to find breaking posts", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
+	var slots []entity.FrontpageSlot
+	if err := s.FrontpageRepository.FindAll(db, &slots); err != nil {
+		slog.Error("Failed to find frontpage slots", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
+	var pinned []entity.Post
+	if err := s.PostRepository.FindPinned(db, &pinned); err != nil {
+		slog.Error("Failed to find pinned posts", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
+	response := &model.FrontpageResponse{
+		Breaking: make([]model.PostResponseWithPreload, 0, len(breaking)),
+		Slots:    make([]model.FrontpageSlotResponse, 0, len(constant.FrontpageSlots)),
+		Pinned:   []model.FrontpagePinnedResponse{},
+	}
+
+	for _, post := range breaking {
+		response.Breaking = append(response.Breaking, s.PostService.toPostSummaryResponse(&post))
+	}
+
+	slotPosts := make(map[string][]model.PostResponseWithPreload)
+	for _, slot := range slots {
+		slotPosts[slot.Name] = append(slotPosts[slot.Name], s.PostService.toPostSummaryResponse(&slot.Post))
+	}
+	for _, name := range constant.FrontpageSlots {
+		posts := slotPosts[name]
+		if posts == nil {
+			posts = []model.PostResponseWithPreload{}
+		}
+		response.Slots = append(response.Slots, model.FrontpageSlotResponse{Name: name, Posts: posts})
+	}
+
+	for _, post := range pinned {
+		last := len(response.Pinned) - 1
+		if last < 0 || response.Pinned[last].Category.ID != post.CategoryID {
+			response.Pinned = append(response.Pinned, model.FrontpagePinnedResponse{
+				Category: &model.CategoryResponse{
+					ID:   post.Category.ID,
+					Name: post.Category.Name,
+				},
+			})
+			last++
+		}
+		response.Pinned[last].Posts = append(response.Pinned[last].Posts, s.PostService.toPostSummaryResponse(&post))
+	}
+
+	return response, nil
+}
+
+func (s *FrontpageService) UpdateSlot(ctx context.Context, request *model.FrontpageSlotUpdate, auth *model.Auth) (*model.FrontpageSlotResponse, error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.UserRepository.IsAdmin(tx, auth.ID); err != nil {
+		slog.Error("Failed to check admin status for frontpage slot update", "error", err)
+		return nil, utility.ErrForbidden
+	}
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error("Validation failed for frontpage slot update", "error", err)
+		return nil, utility.ErrBadRequest
+	}
+
+	seen := make(map[int32]bool, len(request.PostIDs))
+	for _, id := range request.PostIDs {
+		if seen[id] {
+			return nil, utility.NewCustomError(400, "Duplicate post in slot")
+		}
+		seen[id] = true
+	}
+
+	var posts []entity.Post
+	if len(request.PostIDs) > 0 {
+		if err := s.PostRepository.FindPublishedByIDs(tx, &posts, request.PostIDs); err != nil {
+			slog.Error("Failed to find posts for frontpage slot", "error", err)
+			return nil, utility.ErrInternalServer
+		}
+	}
+	if len(posts) != len(request.PostIDs) {
+		return nil, utility.NewCustomError(404, "Post not found or not published")
+	}
+
+	postMap := make(map[int32]*entity.Post, len(posts))
+	for i := range posts {
+		postMap[posts[i].ID] = &posts[i]
+	}
+
+	slots := make([]entity.FrontpageSlot, 0, len(request.PostIDs))
+	response := &model.FrontpageSlotResponse{
+		Name:  request.Name,
+		Posts: make([]model.PostResponseWithPreload, 0, len(request.PostIDs)),
+	}
+	for i, id := range request.PostIDs {
+		slots = append(slots, entity.FrontpageSlot{PostID: id, Position: i})
+		response.Posts = append(response.Posts, s.PostService.toPostSummaryResponse(postMap[id]))
+	}
+
+	if err := s.FrontpageRepository.ReplaceSlot(tx, request.Name, slots); err != nil {
+		slog.Error("Failed to replace frontpage slot", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error("Failed to commit transaction for frontpage slot update", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
+	return response, nil
+}
+
+func (s *FrontpageService) UpdatePlacement(ctx context.Context, request *model.PostPlacementUpdate, auth *model.Auth) (*model.PostResponseWithPreload, error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.UserRepository.IsAdmin(tx, auth.ID); err != nil {
+		slog.Error("Failed to check admin status for post placement update", "error", err)
+		return nil, utility.ErrForbidden
+	}
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error("Validation failed for post placement update", "error", err)
+		return nil, utility.ErrBadRequest
+	}
+
+	post := new(entity.Post)
+	if err := s.PostRepository.FindByID(tx, post, request.ID); err != nil {
+		slog.Error("Failed to find post by ID for placement update", "error", err)
+		return nil, utility.ErrNotFound
+	}
+
+	now := time.Now().Unix()
+
+	if !request.Pinned {
+		post.PinnedAt = nil
+	} else if post.PinnedAt == nil {
+		post.PinnedAt = &now
+	}
+
+	if request.BreakingUntil == 0 {
+		post.BreakingUntil = nil
+	} else if request.BreakingUntil <= now {
+		return nil, utility.NewCustomError(400, "Breaking news expiry must be in the future")
+	} else {
+		post.BreakingUntil = &request.BreakingUntil
+	}
+
+	if err := s.PostRepository.UpdatePlacement(tx, post.ID, post.PinnedAt, post.BreakingUntil); err != nil {
+		slog.Error("Failed to update post placement", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error("Failed to commit transaction for post placement update", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
+	response := s.PostService.toPostSummaryResponse(post)
+	return &response, nil
+}
@@ -11,9 +11,11 @@ import (
 	"context"
 	"log/slog"
 	"math"
+	"net/http"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"gorm.io/gorm"
@@ -25,7 +27,9 @@ type PostService struct {
 	UserRepository     *repository.UserRepository
 	FileRepository     *repository.FileRepository
 	CategoryRepository *repository.CategoryRepository
-	StorageAdapter     *adapter.StorageAdapter
+	StorageAdapter     adapter.StorageAdapter
+	ScannerAdapter     adapter.ScannerAdapter
+	ViewCounter        *ViewCounter
 	Validator          *validator.Validate
 	Config             *config.Config
 }
@@ -36,7 +40,9 @@ func NewPostService(
 	userRepository *repository.UserRepository,
 	fileRepository *repository.FileRepository,
 	categoryRepository *repository.CategoryRepository,
-	storageAdapter *adapter.StorageAdapter,
+	storageAdapter adapter.StorageAdapter,
+	scannerAdapter adapter.ScannerAdapter,
+	viewCounter *ViewCounter,
 	validator *validator.Validate,
 	config *config.Config,
 ) *PostService {
@@ -47,6 +53,8 @@ func NewPostService(
 		FileRepository:     fileRepository,
 		CategoryRepository: categoryRepository,
 		StorageAdapter:     storageAdapter,
+		ScannerAdapter:     scannerAdapter,
+		ViewCounter:        viewCounter,
 		Validator:          validator,
 		Config:             config,
 	}
@@ -81,37 +89,7 @@ func (s *PostService) Search(ctx context.Context, request *model.PostSearch) (*[
 
 	var response []model.PostResponseWithPreload
 	for _, post := range posts {
-		var thumbnail string
-		if len(post.Files) > 0 {
-			thumbnail = utility.BuildImageURL(s.Config, s.Config.Storage.Thumbnail, post.Files[0].Name)
-		}
-
-		var profilePicture string
-		for _, file := range post.User.Files {
-			if file.Type == constant.FileTypeProfile {
-				profilePicture = utility.BuildImageURL(s.Config, s.Config.Storage.Profile, file.Name)
-				break
-			}
-		}
-
-		response = append(response, model.PostResponseWithPreload{
-			ID:        post.ID,
-			Title:     post.Title,
-			Summary:   post.Summary,
-			CreatedAt: post.CreatedAt,
-			UpdatedAt: post.UpdatedAt,
-			Thumbnail: thumbnail,
-			ViewCount: post.ViewCount,
-			User: &model.UserPublicResponse{
-				ID:             post.User.ID,
-				Name:           post.User.Name,
-				ProfilePicture: profilePicture,
-			},
-			Category: &model.CategoryResponse{
-				ID:   post.Category.ID,
-				Name: post.Category.Name,
-			},
-		})
+		response = append(response, s.toPostSummaryResponse(&post))
 	}
 
 	pagination := model.Pagination{
@@ -143,57 +121,51 @@ func (s *PostService) Get(ctx context.Context, request *model.PostGet) (*model.P
 		return nil, utility.ErrNotFound
 	}
 
-	fileIDs, err := utility.ExtractFileIDsFromContent(post.Content)
-	if err != nil {
-		slog.Error("Failed to extract file IDs from content", "error", err)
-		return nil, utility.ErrInternalServer
+	if post.Status != constant.PostStatusPublished {
+		return nil, utility.ErrNotFound
 	}
-	fileMap := s.FileRepository.FindAsMap(db, fileIDs)
 
-	for id, fileName := range fileMap {
-		fileMap[id].Name = utility.BuildImageURL(s.Config, s.Config.Storage.Attachment, fileName.Name)
-	}
+	return s.toPostDetailResponse(db, post, false)
+}
 
-	rebuiltContent, err := utility.RebuildContentWithImageSrc(post.Content, fileMap)
-	if err != nil {
-		slog.Error("Failed to rebuild content with image src", "error", err)
-		return nil, utility.ErrInternalServer
+// Preview returns a post in any status to its authors and admins, so drafts
+// and posts under review can be read while they are edited and reviewed.
+// Assigned editors are always admins.
+func (s *PostService) Preview(ctx context.Context, request *model.PostGet, auth *model.Auth) (*model.PostResponseWithPreload, error) {
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error("Validation failed for post preview", "error", err)
+		return nil, utility.ErrBadRequest
 	}
 
-	var thumbnail string
-	for _, file := range post.Files {
-		if file.Type == constant.FileTypeThumbnail {
-			thumbnail = utility.BuildImageURL(s.Config, s.Config.Storage.Thumbnail, file.Name)
-			break
+	db := s.DB.WithContext(ctx)
+
+	post := &entity.Post{}
+	if err := s.UserRepository.IsAdmin(db, auth.ID); err != nil {
+		if err := s.PostRepository.FindByIDAndAuthorID(db, post, request.ID, auth.ID); err != nil {
+			slog.Error("Failed to find post by ID and author for preview", "error", err)
+			return nil, utility.ErrNotFound
+		}
+	} else {
+		if err := s.PostRepository.FindByID(db, post, request.ID); err != nil {
+			slog.Error("Failed to find post by ID for preview", "error", err)
+			return nil, utility.ErrNotFound
 		}
 	}
 
-	var profilePicture string
-	for _, file := range post.User.Files {
-		if file.Type == constant.FileTypeProfile {
-			profilePicture = utility.BuildImageURL(s.Config, s.Config.Storage.Profile, file.Name)
-			break
-		}
+	response, err := s.toPostDetailResponse(db, post, true)
+	if err != nil {
+		return nil, err
 	}
+	response.Status = post.Status
 
-	response := &model.PostResponseWithPreload{
-		ID:        post.ID,
-		Title:     post.Title,
-		Summary:   post.Summary,
-		Content:   rebuiltContent,
-		ViewCount: post.ViewCount,
-		Thumbnail: thumbnail,
-		User: &model.UserPublicResponse{
-			ID:             post.User.ID,
-			Name:           post.User.Name,
-			ProfilePicture: profilePicture,
-		},
-		Category: &model.CategoryResponse{
-			ID:   post.Category.ID,
-			Name: post.Category.Name,
-		},
-		CreatedAt: post.CreatedAt,
-		UpdatedAt: post.UpdatedAt,
+	// Signed URLs expire, so a preview is the place editors get fresh ones
+	// for a thumbnail that is still private.
+	for i := range post.Files {
+		if post.Files[i].Type == constant.FileTypeThumbnail {
+			response.Thumbnail = utility.BuildFileURL(s.StorageAdapter, s.Config, &post.Files[i])
+			response.ThumbnailVariants = utility.BuildFileVariants(s.StorageAdapter, s.Config, &post.Files[i])
+			break
+		}
 	}
 
 	return response, nil
@@ -205,23 +177,15 @@ func (s *PostService) IncrementViewCount(ctx context.Context, request *model.Pos
 		return utility.ErrBadRequest
 	}
 
-	tx := s.DB.WithContext(ctx).Begin()
-	defer tx.Rollback()
+	db := s.DB.WithContext(ctx)
 
-	post := &entity.Post{}
-	if err := s.PostRepository.FindByID(tx, post, request.ID); err != nil {
-		slog.Error("Failed to find post by ID for incrementing view", "error", err)
+	if err := s.PostRepository.IsPublished(db, request.ID); err != nil {
+		slog.Error("Failed to find published post for incrementing view", "error", err)
 		return utility.ErrNotFound
 	}
 
-	post.ViewCount = post.ViewCount + 1
-	if err := s.PostRepository.Update(tx, post); err != nil {
-		slog.Error("Failed to update post view count", "error", err)
-		return utility.ErrInternalServer
-	}
-
-	if err := tx.Commit().Error; err != nil {
-		slog.Error("Failed to commit transaction for incrementing view count", "error", err)
+	if _, err := s.ViewCounter.Record(ctx, request.ID, request.Visitors, request.Referrer); err != nil {
+		slog.Error("Failed to record post view", "error", err)
 		return utility.ErrInternalServer
 	}
 
@@ -232,8 +196,10 @@ func (s *PostService) Create(ctx context.Context, request *model.PostCreate, aut
 	tx := s.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
+	// Only admins publish directly; everyone else goes through review.
 	if err := s.UserRepository.IsAdmin(tx, auth.ID); err != nil {
 		request.UserID = auth.ID
+		request.Draft = true
 	}
 	if request.UserID == 0 {
 		request.UserID = auth.ID
@@ -254,24 +220,39 @@ func (s *PostService) Create(ctx context.Context, request *model.PostCreate, aut
 		return nil, utility.ErrNotFound
 	}
 
-	fileIDs, err := utility.ExtractFileIDsFromContent(request.Content)
+	content, removed, err := s.sanitizeContent(request.Content, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	fileIDs, err := utility.ExtractFileIDsFromContent(content)
 	if err != nil {
 		slog.Error("Failed to parse content for file IDs", "error", err)
 		return nil, utility.ErrInternalServer
 	}
 
-	sanitizedContent, err := utility.StripImageSrcFromContent(request.Content)
+	if err := s.requireScannedFiles(tx, fileIDs); err != nil {
+		return nil, err
+	}
+
+	sanitizedContent, err := utility.StripImageSrcFromContent(content)
 	if err != nil {
 		slog.Error("Failed to strip image src from content", "error", err)
 		return nil, utility.ErrInternalServer
 	}
 
+	status := constant.PostStatusPublished
+	if request.Draft {
+		status = constant.PostStatusDraft
+	}
+
 	post := &entity.Post{
 		Title:      request.Title,
 		Summary:    request.Summary,
 		Content:    sanitizedContent,
 		UserID:     request.UserID,
 		CategoryID: request.CategoryID,
+		Status:     status,
 	}
 
 	if err := s.PostRepository.Create(tx, post); err != nil {
@@ -279,6 +260,16 @@ func (s *PostService) Create(ctx context.Context, request *model.PostCreate, aut
 		return nil, utility.ErrInternalServer
 	}
 
+	authors, err := s.resolveAuthors(tx, post.UserID, request.Authors)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.PostRepository.ReplaceAuthors(tx, post.ID, authors); err != nil {
+		slog.Error("Failed to create post authors", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
 	var thumbnailName string
 	var thumbnailFile *entity.File
 
@@ -287,7 +278,8 @@ func (s *PostService) Create(ctx context.Context, request *model.PostCreate, aut
 		thumbnailFile = &entity.File{
 			Name:         thumbnailName,
 			Type:         constant.FileTypeThumbnail,
-			Status:       constant.FileStatusPending,
+			Status:       thumbnailStatus(post.Status),
+			UploadedByID: &auth.ID,
 			UsedByPostID: &post.ID,
 		}
 		if err := s.FileRepository.Create(tx, thumbnailFile); err != nil {
@@ -307,20 +299,26 @@ func (s *PostService) Create(ctx context.Context, request *model.PostCreate, aut
 	}
 
 	if request.Thumbnail != nil {
-		storagePath := s.Config.Storage.Thumbnail
+		storagePath := utility.FileObjectFolder(s.Config, thumbnailFile)
 		fullPath := filepath.Join(storagePath, thumbnailName)
 
-		if err := s.StorageAdapter.Store(request.Thumbnail, fullPath); err != nil {
+		if err := storeUploadedImage(ctx, s.StorageAdapter, s.ScannerAdapter, s.Config, request.Thumbnail, fullPath, thumbnailFile); err != nil {
 			slog.Error("Failed to store thumbnail file", "error", err)
 
 			if delErr := s.FileRepository.Delete(s.DB.WithContext(ctx), thumbnailFile); delErr != nil {
 				slog.Error("Failed to delete file record after storage failure", "error", delErr)
 			}
 
-			thumbnailName = ""
+			thumbnailFile = nil
+		} else if err := s.FileRepository.UpdateDimensions(s.DB.WithContext(ctx), thumbnailFile); err != nil {
+			slog.Error("Failed to record thumbnail dimensions", "error", err)
 		}
 	}
 
+	if post.Status == constant.PostStatusPublished {
+		publishPostFiles(ctx, s.DB.WithContext(ctx), s.FileRepository, s.StorageAdapter, s.Config, post.ID)
+	}
+
 	response := &model.PostResponse{
 		ID:         post.ID,
 		CategoryID: post.CategoryID,
@@ -330,7 +328,9 @@ func (s *PostService) Create(ctx context.Context, request *model.PostCreate, aut
 		Content:    post.Content,
 		CreatedAt:  post.CreatedAt,
 		UpdatedAt:  post.UpdatedAt,
-		Thumbnail:  utility.BuildImageURL(s.Config, s.Config.Storage.Thumbnail, thumbnailName),
+		Thumbnail:  utility.BuildFileURL(s.StorageAdapter, s.Config, thumbnailFile),
+		Status:     post.Status,
+		Sanitized:  removed,
 	}
 
 	return response, nil
@@ -342,11 +342,20 @@ func (s *PostService) Update(ctx context.Context, request *model.PostUpdate, aut
 
 	post := &entity.Post{}
 	if err := s.UserRepository.IsAdmin(tx, auth.ID); err != nil {
-		request.UserID = auth.ID
-		if err := s.PostRepository.FindByIDAndUserID(tx, post, request.ID, auth.ID); err != nil {
-			slog.Error("Failed to find post by ID and UserID for update", "error", err)
+		if err := s.PostRepository.FindByIDAndAuthorID(tx, post, request.ID, auth.ID); err != nil {
+			slog.Error("Failed to find post by ID and author for update", "error", err)
 			return nil, utility.ErrNotFound
 		}
+		request.UserID = post.UserID
+
+		// Authors cannot change a post the editor is reviewing, and changes
+		// to a published post take it back to draft for another review.
+		switch post.Status {
+		case constant.PostStatusInReview:
+			return nil, utility.NewCustomError(http.StatusConflict, "Post is under review and cannot be edited")
+		case constant.PostStatusPublished:
+			post.Status = constant.PostStatusDraft
+		}
 	} else {
 		if err := s.PostRepository.FindByID(tx, post, request.ID); err != nil {
 			slog.Error("Failed to find post by ID for update", "error", err)
@@ -364,13 +373,22 @@ func (s *PostService) Update(ctx context.Context, request *model.PostUpdate, aut
 		return nil, utility.ErrNotFound
 	}
 
-	currentFileIDs, err := utility.ExtractFileIDsFromContent(request.Content)
+	content, removed, err := s.sanitizeContent(request.Content, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	currentFileIDs, err := utility.ExtractFileIDsFromContent(content)
 	if err != nil {
 		slog.Error("Failed to parse content for file IDs", "error", err)
 		return nil, utility.ErrInternalServer
 	}
 
-	sanitizedContent, err := utility.StripImageSrcFromContent(request.Content)
+	if err := s.requireScannedFiles(tx, currentFileIDs); err != nil {
+		return nil, err
+	}
+
+	sanitizedContent, err := utility.StripImageSrcFromContent(content)
 	if err != nil {
 		slog.Error("Failed to strip image src from content", "error", err)
 		return nil, utility.ErrInternalServer
@@ -390,9 +408,10 @@ func (s *PostService) Update(ctx context.Context, request *model.PostUpdate, aut
 	if request.Thumbnail != nil {
 		newThumbnailName = utility.CreateFileName(request.Thumbnail)
 		newThumbnailFile = &entity.File{
-			Name:   newThumbnailName,
-			Type:   constant.FileTypeThumbnail,
-			Status: constant.FileStatusPending,
+			Name:         newThumbnailName,
+			Type:         constant.FileTypeThumbnail,
+			Status:       thumbnailStatus(post.Status),
+			UploadedByID: &auth.ID,
 		}
 		if err := s.FileRepository.Create(tx, newThumbnailFile); err != nil {
 			slog.Error("Failed to create new thumbnail file record", "error", err)
@@ -405,6 +424,8 @@ func (s *PostService) Update(ctx context.Context, request *model.PostUpdate, aut
 		currentFileIDs = append(currentFileIDs, oldThumbnailFile.ID)
 	}
 
+	previousOwnerID := post.UserID
+
 	post.Title = request.Title
 	post.Summary = request.Summary
 	post.Content = sanitizedContent
@@ -425,6 +446,30 @@ func (s *PostService) Update(ctx context.Context, request *model.PostUpdate, aut
 		return nil, utility.ErrInternalServer
 	}
 
+	authorRequests := request.Authors
+	if authorRequests == nil {
+		for _, author := range post.Authors {
+			if author.UserID == previousOwnerID && previousOwnerID != post.UserID {
+				continue
+			}
+			authorRequest := model.PostAuthorRequest{UserID: author.UserID}
+			if author.Role != nil {
+				authorRequest.Role = *author.Role
+			}
+			authorRequests = append(authorRequests, authorRequest)
+		}
+	}
+
+	authors, err := s.resolveAuthors(tx, post.UserID, authorRequests)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.PostRepository.ReplaceAuthors(tx, post.ID, authors); err != nil {
+		slog.Error("Failed to update post authors", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
 	if err := s.FileRepository.UnlinkUnusedFiles(tx, post.ID, currentFileIDs); err != nil {
 		slog.Error("Failed to unlink unused files", "error", err)
 		return nil, utility.ErrInternalServer
@@ -441,10 +486,10 @@ func (s *PostService) Update(ctx context.Context, request *model.PostUpdate, aut
 	}
 
 	if request.Thumbnail != nil {
-		storagePath := s.Config.Storage.Thumbnail
+		storagePath := utility.FileObjectFolder(s.Config, newThumbnailFile)
 		fullPath := filepath.Join(storagePath, newThumbnailName)
 
-		if err := s.StorageAdapter.Store(request.Thumbnail, fullPath); err != nil {
+		if err := storeUploadedImage(ctx, s.StorageAdapter, s.ScannerAdapter, s.Config, request.Thumbnail, fullPath, newThumbnailFile); err != nil {
 			slog.Error("Failed to store new thumbnail file", "error", err)
 
 			if delErr := s.FileRepository.Delete(s.DB.WithContext(ctx), newThumbnailFile); delErr != nil {
@@ -452,12 +497,24 @@ func (s *PostService) Update(ctx context.Context, request *model.PostUpdate, aut
 			}
 
 			newThumbnailFile = nil
-			newThumbnailName = ""
+		} else if err := s.FileRepository.UpdateDimensions(s.DB.WithContext(ctx), newThumbnailFile); err != nil {
+			slog.Error("Failed to record thumbnail dimensions", "error", err)
 		}
 	}
 
-	if newThumbnailName == "" && oldThumbnailFile != nil && !request.DeleteThumbnail {
-		newThumbnailName = oldThumbnailFile.Name
+	if newThumbnailFile == nil && oldThumbnailFile != nil && !request.DeleteThumbnail {
+		newThumbnailFile = oldThumbnailFile
+	}
+
+	if post.Status == constant.PostStatusPublished {
+		publishPostFiles(ctx, s.DB.WithContext(ctx), s.FileRepository, s.StorageAdapter, s.Config, post.ID)
+
+		// A thumbnail that was still private has just been moved.
+		if newThumbnailFile != nil && newThumbnailFile.Status == constant.FileStatusPrivate {
+			if file, err := s.FileRepository.FindByID(s.DB.WithContext(ctx), newThumbnailFile.ID); err == nil {
+				newThumbnailFile = file
+			}
+		}
 	}
 
 	response := &model.PostResponse{
@@ -469,12 +526,213 @@ func (s *PostService) Update(ctx context.Context, request *model.PostUpdate, aut
 		Content:    post.Content,
 		CreatedAt:  post.CreatedAt,
 		UpdatedAt:  post.UpdatedAt,
-		Thumbnail:  utility.BuildImageURL(s.Config, s.Config.Storage.Thumbnail, newThumbnailName),
+		Thumbnail:  utility.BuildFileURL(s.StorageAdapter, s.Config, newThumbnailFile),
+		Status:     post.Status,
+		Sanitized:  removed,
 	}
 
 	return response, nil
 }
 
+// sanitizeContent applies the configured allowlist to submitted content and
+// logs anything it removed.
+func (s *PostService) sanitizeContent(content string, auth *model.Auth) (string, []model.ContentRemoval, error) {
+	sanitized, removed, err := utility.SanitizeContent(content, utility.NewContentPolicy(s.Config.Content))
+	if err != nil {
+		slog.Error("Failed to sanitize post content", "error", err)
+		return "", nil, utility.ErrInternalServer
+	}
+	if len(removed) > 0 {
+		slog.Warn("Removed disallowed markup from post content", "userID", auth.ID, "removed", removed)
+	}
+	return sanitized, removed, nil
+}
+
+// thumbnailStatus keeps the thumbnail of an unpublished post private until
+// the post is published.
+func thumbnailStatus(postStatus string) string {
+	if postStatus == constant.PostStatusPublished {
+		return constant.FileStatusPending
+	}
+	return constant.FileStatusPrivate
+}
+
+// requireScannedFiles rejects content that references files the malware
+// scanner has not marked clean.
+func (s *PostService) requireScannedFiles(tx *gorm.DB, fileIDs []int32) error {
+	count, err := s.FileRepository.CountUnscanned(tx, fileIDs)
+	if err != nil {
+		slog.Error("Failed to check scan status of content files", "error", err)
+		return utility.ErrInternalServer
+	}
+	if count > 0 {
+		return utility.NewCustomError(http.StatusConflict, "Content references files that have not passed the malware scan")
+	}
+	return nil
+}
+
+func (s *PostService) resolveAuthors(tx *gorm.DB, ownerID int32, requests []model.PostAuthorRequest) ([]entity.PostAuthor, error) {
+	seen := make(map[int32]bool)
+	var authors []entity.PostAuthor
+
+	hasOwner := false
+	for _, request := range requests {
+		if request.UserID == ownerID {
+			hasOwner = true
+			break
+		}
+	}
+	if !hasOwner {
+		requests = append([]model.PostAuthorRequest{{UserID: ownerID}}, requests...)
+	}
+
+	for _, request := range requests {
+		if seen[request.UserID] {
+			slog.Error("Duplicate author in post byline", "userID", request.UserID)
+			return nil, utility.ErrBadRequest
+		}
+		seen[request.UserID] = true
+
+		if request.UserID != ownerID {
+			if err := s.UserRepository.FindByID(tx, &entity.User{}, request.UserID); err != nil {
+				slog.Error("Author not found for post byline", "userID", request.UserID, "error", err)
+				return nil, utility.ErrNotFound
+			}
+		}
+
+		author := entity.PostAuthor{
+			UserID:   request.UserID,
+			Position: len(authors),
+		}
+		if request.Role != "" {
+			role := request.Role
+			author.Role = &role
+		}
+		authors = append(authors, author)
+	}
+
+	return authors, nil
+}
+
+// toPostDetailResponse adds the content, with the src of embedded files
+// filled in, to the summary of a post. A preview signs the URLs of files that
+// are still private.
+func (s *PostService) toPostDetailResponse(db *gorm.DB, post *entity.Post, preview bool) (*model.PostResponseWithPreload, error) {
+	fileIDs, err := utility.ExtractFileIDsFromContent(post.Content)
+	if err != nil {
+		slog.Error("Failed to extract file IDs from content", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+	fileMap := s.FileRepository.FindAsMap(db, fileIDs)
+
+	var rebuiltContent string
+	if preview {
+		rebuiltContent, err = utility.RebuildPreviewContent(s.StorageAdapter, s.Config, post.Content, fileMap)
+	} else {
+		rebuiltContent, err = utility.RebuildContentWithImageSrc(s.StorageAdapter, s.Config.Storage.Attachment, post.Content, fileMap)
+	}
+	if err != nil {
+		slog.Error("Failed to rebuild content with image src", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
+	response := s.toPostSummaryResponse(post)
+	response.Content = rebuiltContent
+
+	return &response, nil
+}
+
+func (s *PostService) toPostSummaryResponse(post *entity.Post) model.PostResponseWithPreload {
+	var thumbnail, thumbnailAlt string
+	var thumbnailVariants map[string]string
+	var thumbnailCrops map[string]model.ImageCrop
+	for _, file := range post.Files {
+		if file.Type == constant.FileTypeThumbnail {
+			thumbnail = utility.BuildImageURL(s.StorageAdapter, s.Config.Storage.Thumbnail, file.Name)
+			thumbnailAlt = file.AltText
+			thumbnailVariants = utility.BuildImageVariants(s.StorageAdapter, s.Config.Storage.Thumbnail, &file)
+			thumbnailCrops = utility.BuildImageCrops(&file)
+			break
+		}
+	}
+
+	var profilePicture string
+	var profilePictureVariants map[string]string
+	for _, file := range post.User.Files {
+		if file.Type == constant.FileTypeProfile {
+			profilePicture = utility.BuildImageURL(s.StorageAdapter, s.Config.Storage.Profile, file.Name)
+			profilePictureVariants = utility.BuildImageVariants(s.StorageAdapter, s.Config.Storage.Profile, &file)
+			break
+		}
+	}
+
+	owner := model.UserPublicResponse{
+		ID:                     post.User.ID,
+		Name:                   post.User.Name,
+		ProfilePicture:         profilePicture,
+		ProfilePictureVariants: profilePictureVariants,
+		Slug:                   userSlug(&post.User),
+	}
+
+	response := model.PostResponseWithPreload{
+		ID:                post.ID,
+		Title:             post.Title,
+		Summary:           post.Summary,
+		CreatedAt:         post.CreatedAt,
+		UpdatedAt:         post.UpdatedAt,
+		Thumbnail:         thumbnail,
+		ThumbnailAlt:      thumbnailAlt,
+		ThumbnailVariants: thumbnailVariants,
+		ThumbnailCrops:    thumbnailCrops,
+		ViewCount:         post.ViewCount,
+		User:              &owner,
+		Authors:           s.buildAuthors(post, owner),
+		Category: &model.CategoryResponse{
+			ID:   post.Category.ID,
+			Name: post.Category.Name,
+		},
+		Pinned: post.PinnedAt != nil,
+	}
+	if post.BreakingUntil != nil && *post.BreakingUntil > time.Now().Unix() {
+		response.BreakingUntil = *post.BreakingUntil
+	}
+
+	return response
+}
+
+func (s *PostService) buildAuthors(post *entity.Post, owner model.UserPublicResponse) []model.UserPublicResponse {
+	if len(post.Authors) == 0 {
+		return []model.UserPublicResponse{owner}
+	}
+
+	authors := make([]model.UserPublicResponse, 0, len(post.Authors))
+	for _, author := range post.Authors {
+		var profilePicture string
+		var profilePictureVariants map[string]string
+		for _, file := range author.User.Files {
+			if file.Type == constant.FileTypeProfile {
+				profilePicture = utility.BuildImageURL(s.StorageAdapter, s.Config.Storage.Profile, file.Name)
+				profilePictureVariants = utility.BuildImageVariants(s.StorageAdapter, s.Config.Storage.Profile, &file)
+				break
+			}
+		}
+
+		response := model.UserPublicResponse{
+			ID:                     author.User.ID,
+			Name:                   author.User.Name,
+			ProfilePicture:         profilePicture,
+			ProfilePictureVariants: profilePictureVariants,
+			Slug:                   userSlug(&author.User),
+		}
+		if author.Role != nil {
+			response.Role = *author.Role
+		}
+		authors = append(authors, response)
+	}
+
+	return authors
+}
+
 func (s *PostService) Delete(ctx context.Context, request *model.PostDelete, auth *model.Auth) error {
 	tx := s.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
@@ -498,6 +756,11 @@ func (s *PostService) Delete(ctx context.Context, request *model.PostDelete, aut
 		}
 	}
 
+	if err := s.FileRepository.UnlinkUnusedFiles(tx, post.ID, nil); err != nil {
+		slog.Error("Failed to unlink files from deleted post", "error", err)
+		return utility.ErrInternalServer
+	}
+
 	if err := s.PostRepository.Delete(tx, post); err != nil {
 		slog.Error("Failed to delete post", "error", err)
 		return utility.ErrInternalServer
@@ -0,0 +1,529 @@
+package service
+
+import (
+	"chrononewsapi/internal/adapter"
+	"chrononewsapi/internal/config"
+	"chrononewsapi/internal/constant"
+	"chrononewsapi/internal/entity"
+	"chrononewsapi/internal/model"
+	"chrononewsapi/internal/repository"
+	"chrononewsapi/internal/utility"
+	"context"
+	"embed"
+	"errors"
+	"log/slog"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+//go:embed template/review_notification_email.html
+var reviewNotificationTemplate embed.FS
+
+var reviewStatusLabels = map[string]string{
+	constant.PostStatusDraft:            "Draf",
+	constant.PostStatusInReview:         "Menunggu Tinjauan",
+	constant.PostStatusChangesRequested: "Perlu Revisi",
+	constant.PostStatusRejected:         "Ditolak",
+	constant.PostStatusPublished:        "Diterbitkan",
+}
+
+var reviewDecisionStatus = map[string]string{
+	constant.ReviewDecisionApprove:        constant.PostStatusPublished,
+	constant.ReviewDecisionReject:         constant.PostStatusRejected,
+	constant.ReviewDecisionRequestChanges: constant.PostStatusChangesRequested,
+}
+
+type ReviewService struct {
+	DB               *gorm.DB
+	ReviewRepository *repository.ReviewRepository
+	PostRepository   *repository.PostRepository
+	UserRepository   *repository.UserRepository
+	FileRepository   *repository.FileRepository
+	StorageAdapter   adapter.StorageAdapter
+	EmailAdapter     *adapter.EmailAdapter
+	Validator        *validator.Validate
+	Config           *config.Config
+}
+
+func NewReviewService(db *gorm.DB, reviewRepository *repository.ReviewRepository, postRepository *repository.PostRepository, userRepository *repository.UserRepository, fileRepository *repository.FileRepository, storageAdapter adapter.StorageAdapter, emailAdapter *adapter.EmailAdapter, validator *validator.Validate, config *config.Config) *ReviewService {
+	return &ReviewService{
+		DB:               db,
+		ReviewRepository: reviewRepository,
+		PostRepository:   postRepository,
+		UserRepository:   userRepository,
+		FileRepository:   fileRepository,
+		StorageAdapter:   storageAdapter,
+		EmailAdapter:     emailAdapter,
+		Validator:        validator,
+		Config:           config,
+	}
+}
+
+func (s *ReviewService) Search(ctx context.Context, request *model.ReviewSearch, auth *model.Auth) (*[]model.ReviewResponse, *model.Pagination, error) {
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error("Validation failed for review search", "error", err)
+		return nil, nil, utility.ErrBadRequest
+	}
+
+	db := s.DB.WithContext(ctx)
+	isAdmin := s.UserRepository.IsAdmin(db, auth.ID) == nil
+
+	var reviews []entity.Review
+	total, err := s.ReviewRepository.Search(db, request, &reviews, auth.ID, isAdmin)
+	if err != nil {
+		slog.Error("Failed to search reviews", "error", err)
+		return nil, nil, utility.ErrInternalServer
+	}
+
+	if len(reviews) == 0 {
+		return &[]model.ReviewResponse{}, &model.Pagination{}, nil
+	}
+
+	var response []model.ReviewResponse
+	for i := range reviews {
+		response = append(response, *s.toReviewResponse(&reviews[i]))
+	}
+
+	pagination := &model.Pagination{
+		TotalItem: total,
+	}
+
+	if request.Page != 0 && request.Size != 0 {
+		pagination.Page = request.Page
+		pagination.Size = request.Size
+		pagination.TotalPage = int64(math.Ceil(float64(total) / float64(request.Size)))
+	} else {
+		pagination.TotalPage = 1
+	}
+
+	return &response, pagination, nil
+}
+
+func (s *ReviewService) Get(ctx context.Context, request *model.ReviewGet, auth *model.Auth) (*model.ReviewResponse, error) {
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error("Validation failed for review get", "error", err)
+		return nil, utility.ErrBadRequest
+	}
+
+	db := s.DB.WithContext(ctx)
+
+	if _, err := s.findAccessiblePost(db, request.PostID, auth); err != nil {
+		return nil, err
+	}
+
+	review := new(entity.Review)
+	if err := s.ReviewRepository.FindByPostID(db, review, request.PostID); err != nil {
+		slog.Error("Failed to find review by post ID", "error", err)
+		return nil, utility.ErrNotFound
+	}
+
+	return s.toReviewResponse(review), nil
+}
+
+func (s *ReviewService) Submit(ctx context.Context, request *model.ReviewSubmit, auth *model.Auth) (*model.ReviewResponse, error) {
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error("Validation failed for review submit", "error", err)
+		return nil, utility.ErrBadRequest
+	}
+
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	post, err := s.findAccessiblePost(tx, request.PostID, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	if post.Status != constant.PostStatusDraft &&
+		post.Status != constant.PostStatusChangesRequested &&
+		post.Status != constant.PostStatusRejected {
+		return nil, utility.NewCustomError(http.StatusConflict, "Post cannot be submitted for review in its current status")
+	}
+
+	review, err := s.findOrInitReview(tx, post.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var editor *entity.User
+	if request.EditorID != 0 {
+		if editor, err = s.findEditor(tx, request.EditorID); err != nil {
+			return nil, err
+		}
+		review.EditorID = &editor.ID
+	}
+
+	review.SubmitterID = &auth.ID
+	if err := s.transition(tx, review, post, constant.PostStatusInReview, auth.ID, ""); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error("Failed to commit transaction for review submit", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
+	if editor == nil && review.EditorID != nil {
+		editor = s.findUser(ctx, *review.EditorID)
+	}
+	s.notify(ctx, editor, auth.ID, post, "")
+
+	return s.reload(ctx, post.ID)
+}
+
+func (s *ReviewService) Assign(ctx context.Context, request *model.ReviewAssign, auth *model.Auth) (*model.ReviewResponse, error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.UserRepository.IsAdmin(tx, auth.ID); err != nil {
+		slog.Error("Failed to check admin status for review assign", "error", err)
+		return nil, utility.ErrForbidden
+	}
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error("Validation failed for review assign", "error", err)
+		return nil, utility.ErrBadRequest
+	}
+
+	post := new(entity.Post)
+	if err := s.PostRepository.FindByID(tx, post, request.PostID); err != nil {
+		slog.Error("Failed to find post by ID for review assign", "error", err)
+		return nil, utility.ErrNotFound
+	}
+
+	if post.Status != constant.PostStatusInReview {
+		return nil, utility.NewCustomError(http.StatusConflict, "Post is not under review")
+	}
+
+	review := new(entity.Review)
+	if err := s.ReviewRepository.FindByPostID(tx, review, post.ID); err != nil {
+		slog.Error("Failed to find review by post ID for assign", "error", err)
+		return nil, utility.ErrNotFound
+	}
+
+	editor, err := s.findEditor(tx, request.EditorID)
+	if err != nil {
+		return nil, err
+	}
+
+	review.EditorID = &editor.ID
+	if err := s.ReviewRepository.Update(tx, review); err != nil {
+		slog.Error("Failed to update review editor", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
+	event := &entity.ReviewEvent{
+		ReviewID:   review.ID,
+		ActorID:    &auth.ID,
+		FromStatus: post.Status,
+		ToStatus:   post.Status,
+		Note:       "Editor assigned: " + editor.Name,
+	}
+	if err := s.ReviewRepository.CreateEvent(tx, event); err != nil {
+		slog.Error("Failed to record review assignment event", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error("Failed to commit transaction for review assign", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
+	s.notify(ctx, editor, auth.ID, post, "")
+
+	return s.reload(ctx, post.ID)
+}
+
+func (s *ReviewService) Comment(ctx context.Context, request *model.ReviewCommentCreate, auth *model.Auth) (*model.ReviewCommentResponse, error) {
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error("Validation failed for review comment", "error", err)
+		return nil, utility.ErrBadRequest
+	}
+
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if _, err := s.findAccessiblePost(tx, request.PostID, auth); err != nil {
+		return nil, err
+	}
+
+	review := new(entity.Review)
+	if err := s.ReviewRepository.FindByPostID(tx, review, request.PostID); err != nil {
+		slog.Error("Failed to find review by post ID for comment", "error", err)
+		return nil, utility.ErrNotFound
+	}
+
+	comment := &entity.ReviewComment{
+		ReviewID: review.ID,
+		UserID:   &auth.ID,
+		Anchor:   request.Anchor,
+		Body:     request.Body,
+	}
+	if err := s.ReviewRepository.CreateComment(tx, comment); err != nil {
+		slog.Error("Failed to create review comment", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
+	user := new(entity.User)
+	if err := s.UserRepository.FindByID(tx, user, auth.ID); err != nil {
+		slog.Error("Failed to find comment author", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error("Failed to commit transaction for review comment", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
+	return &model.ReviewCommentResponse{
+		ID:        comment.ID,
+		User:      toUserPublicResponse(user),
+		Anchor:    comment.Anchor,
+		Body:      comment.Body,
+		CreatedAt: comment.CreatedAt,
+	}, nil
+}
+
+func (s *ReviewService) Decide(ctx context.Context, request *model.ReviewDecision, auth *model.Auth) (*model.ReviewResponse, error) {
+	tx := s.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := s.UserRepository.IsAdmin(tx, auth.ID); err != nil {
+		slog.Error("Failed to check admin status for review decision", "error", err)
+		return nil, utility.ErrForbidden
+	}
+
+	if err := s.Validator.Struct(request); err != nil {
+		slog.Error("Validation failed for review decision", "error", err)
+		return nil, utility.ErrBadRequest
+	}
+
+	post := new(entity.Post)
+	if err := s.PostRepository.FindByID(tx, post, request.PostID); err != nil {
+		slog.Error("Failed to find post by ID for review decision", "error", err)
+		return nil, utility.ErrNotFound
+	}
+
+	if post.Status != constant.PostStatusInReview {
+		return nil, utility.NewCustomError(http.StatusConflict, "Post is not under review")
+	}
+
+	review := new(entity.Review)
+	if err := s.ReviewRepository.FindByPostID(tx, review, post.ID); err != nil {
+		slog.Error("Failed to find review by post ID for decision", "error", err)
+		return nil, utility.ErrNotFound
+	}
+
+	if review.EditorID != nil && *review.EditorID != auth.ID {
+		return nil, utility.NewCustomError(http.StatusForbidden, "Post is assigned to another editor")
+	}
+	review.EditorID = &auth.ID
+
+	if err := s.transition(tx, review, post, reviewDecisionStatus[request.Decision], auth.ID, request.Note); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		slog.Error("Failed to commit transaction for review decision", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
+	if post.Status == constant.PostStatusPublished {
+		publishPostFiles(ctx, s.DB.WithContext(ctx), s.FileRepository, s.StorageAdapter, s.Config, post.ID)
+	}
+
+	authorID := post.UserID
+	if review.SubmitterID != nil {
+		authorID = *review.SubmitterID
+	}
+	s.notify(ctx, s.findUser(ctx, authorID), auth.ID, post, request.Note)
+
+	return s.reload(ctx, post.ID)
+}
+
+func (s *ReviewService) findAccessiblePost(db *gorm.DB, postID int32, auth *model.Auth) (*entity.Post, error) {
+	post := new(entity.Post)
+	if err := s.UserRepository.IsAdmin(db, auth.ID); err != nil {
+		if err := s.PostRepository.FindByIDAndAuthorID(db, post, postID, auth.ID); err != nil {
+			slog.Error("Failed to find post by ID and author for review", "error", err)
+			return nil, utility.ErrNotFound
+		}
+		return post, nil
+	}
+
+	if err := s.PostRepository.FindByID(db, post, postID); err != nil {
+		slog.Error("Failed to find post by ID for review", "error", err)
+		return nil, utility.ErrNotFound
+	}
+	return post, nil
+}
+
+func (s *ReviewService) findOrInitReview(tx *gorm.DB, postID int32) (*entity.Review, error) {
+	review := new(entity.Review)
+	err := s.ReviewRepository.FindByPostID(tx, review, postID)
+	if err == nil {
+		return review, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		slog.Error("Failed to find review by post ID", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
+	review = &entity.Review{PostID: postID}
+	if err := s.ReviewRepository.Create(tx, review); err != nil {
+		slog.Error("Failed to create review", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+	return review, nil
+}
+
+func (s *ReviewService) findEditor(tx *gorm.DB, editorID int32) (*entity.User, error) {
+	if err := s.UserRepository.IsAdmin(tx, editorID); err != nil {
+		slog.Error("Assigned editor is not an admin", "editorID", editorID, "error", err)
+		return nil, utility.NewCustomError(http.StatusBadRequest, "Assigned editor must be an admin")
+	}
+
+	editor := new(entity.User)
+	if err := s.UserRepository.FindByID(tx, editor, editorID); err != nil {
+		slog.Error("Failed to find editor by ID", "error", err)
+		return nil, utility.ErrNotFound
+	}
+	return editor, nil
+}
+
+func (s *ReviewService) findUser(ctx context.Context, userID int32) *entity.User {
+	user := new(entity.User)
+	if err := s.UserRepository.FindByID(s.DB.WithContext(ctx), user, userID); err != nil {
+		slog.Warn("Failed to find user for review notification", "userID", userID, "error", err)
+		return nil
+	}
+	return user
+}
+
+func (s *ReviewService) transition(tx *gorm.DB, review *entity.Review, post *entity.Post, status string, actorID int32, note string) error {
+	event := &entity.ReviewEvent{
+		ReviewID:   review.ID,
+		ActorID:    &actorID,
+		FromStatus: post.Status,
+		ToStatus:   status,
+		Note:       note,
+	}
+
+	if err := s.PostRepository.UpdateStatus(tx, post.ID, status); err != nil {
+		slog.Error("Failed to update post status", "error", err)
+		return utility.ErrInternalServer
+	}
+
+	if err := s.ReviewRepository.Update(tx, review); err != nil {
+		slog.Error("Failed to update review", "error", err)
+		return utility.ErrInternalServer
+	}
+
+	if err := s.ReviewRepository.CreateEvent(tx, event); err != nil {
+		slog.Error("Failed to record review event", "error", err)
+		return utility.ErrInternalServer
+	}
+
+	post.Status = status
+	return nil
+}
+
+func (s *ReviewService) reload(ctx context.Context, postID int32) (*model.ReviewResponse, error) {
+	review := new(entity.Review)
+	if err := s.ReviewRepository.FindByPostID(s.DB.WithContext(ctx), review, postID); err != nil {
+		slog.Error("Failed to reload review", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+	return s.toReviewResponse(review), nil
+}
+
+func (s *ReviewService) notify(ctx context.Context, recipient *entity.User, actorID int32, post *entity.Post, note string) {
+	if recipient == nil || recipient.ID == actorID {
+		return
+	}
+
+	actorName := ""
+	if actor := s.findUser(ctx, actorID); actor != nil {
+		actorName = actor.Name
+	}
+
+	emailBody := &model.EmailBodyData{
+		PostTitle: post.Title,
+		ActorName: actorName,
+		Status:    reviewStatusLabels[post.Status],
+		Note:      note,
+		Year:      time.Now().Year(),
+	}
+
+	bodyContent, err := utility.GenerateEmailBody(reviewNotificationTemplate, "template/review_notification_email.html", emailBody)
+	if err != nil {
+		slog.Error("Failed to generate review notification email body", "error", err)
+		return
+	}
+
+	emailRequest := &model.EmailData{
+		To:        recipient.Email,
+		Body:      bodyContent,
+		SMTPHost:  s.Config.SMTP.Host,
+		SMTPPort:  s.Config.SMTP.Port,
+		FromName:  s.Config.SMTP.From.Name,
+		FromEmail: s.Config.SMTP.From.Email,
+		Username:  s.Config.SMTP.Username,
+		Password:  s.Config.SMTP.Password,
+		Subject:   "Tinjauan Berita: " + post.Title + " - " + s.Config.SMTP.From.Name,
+	}
+
+	if err := s.EmailAdapter.Send(emailRequest); err != nil {
+		slog.Error("Failed to send review notification email", "to", recipient.Email, "error", err)
+	}
+}
+
+func (s *ReviewService) toReviewResponse(review *entity.Review) *model.ReviewResponse {
+	response := &model.ReviewResponse{
+		PostID:    review.PostID,
+		Title:     review.Post.Title,
+		Status:    review.Post.Status,
+		Submitter: toUserPublicResponse(review.Submitter),
+		Editor:    toUserPublicResponse(review.Editor),
+		CreatedAt: review.CreatedAt,
+		UpdatedAt: review.UpdatedAt,
+	}
+
+	for _, comment := range review.Comments {
+		response.Comments = append(response.Comments, model.ReviewCommentResponse{
+			ID:        comment.ID,
+			User:      toUserPublicResponse(comment.User),
+			Anchor:    comment.Anchor,
+			Body:      comment.Body,
+			CreatedAt: comment.CreatedAt,
+		})
+	}
+
+	for _, event := range review.Events {
+		response.Events = append(response.Events, model.ReviewEventResponse{
+			ID:         event.ID,
+			Actor:      toUserPublicResponse(event.Actor),
+			FromStatus: event.FromStatus,
+			ToStatus:   event.ToStatus,
+			Note:       event.Note,
+			CreatedAt:  event.CreatedAt,
+		})
+	}
+
+	return response
+}
+
+func toUserPublicResponse(user *entity.User) *model.UserPublicResponse {
+	if user == nil {
+		return nil
+	}
+	return &model.UserPublicResponse{
+		ID:   user.ID,
+		Name: user.Name,
+		Slug: userSlug(user),
+	}
+}
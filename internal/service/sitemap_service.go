@@ -21,13 +21,15 @@ const postsPerPage = 1000
 type SitemapService struct {
 	postRepository     *repository.PostRepository
 	categoryRepository *repository.CategoryRepository
+	userRepository     *repository.UserRepository
 	cfg                *config.Config
 }
 
-func NewSitemapService(postRepository *repository.PostRepository, categoryRepository *repository.CategoryRepository, cfg *config.Config) *SitemapService {
+func NewSitemapService(postRepository *repository.PostRepository, categoryRepository *repository.CategoryRepository, userRepository *repository.UserRepository, cfg *config.Config) *SitemapService {
 	return &SitemapService{
 		postRepository:     postRepository,
 		categoryRepository: categoryRepository,
+		userRepository:     userRepository,
 		cfg:                cfg,
 	}
 }
@@ -42,6 +44,9 @@ func (s *SitemapService) GenerateSitemapIndex(db *gorm.DB) ([]byte, error) {
 	sitemapIndex.Sitemaps = append(sitemapIndex.Sitemaps, model.Sitemap{
 		Loc: fmt.Sprintf("%s/sitemap/categories.xml", s.cfg.Web.BaseURL),
 	})
+	sitemapIndex.Sitemaps = append(sitemapIndex.Sitemaps, model.Sitemap{
+		Loc: fmt.Sprintf("%s/sitemap/authors.xml", s.cfg.Web.BaseURL),
+	})
 
 	totalPages := int(math.Ceil(float64(totalPosts) / float64(postsPerPage)))
 	for i := 1; i <= totalPages; i++ {
@@ -105,3 +110,26 @@ func (s *SitemapService) GenerateCategoriesSitemap(db *gorm.DB) ([]byte, error)
 
 	return []byte(xml.Header + string(output)), nil
 }
+
+func (s *SitemapService) GenerateAuthorsSitemap(db *gorm.DB) ([]byte, error) {
+	var users []entity.User
+	if err := s.userRepository.FindAllAuthors(db, &users); err != nil {
+		return nil, err
+	}
+
+	urlset := model.URLSet{}
+	for _, user := range users {
+		authorURL := fmt.Sprintf("%s%s/%s", s.cfg.Web.ClientURL, s.cfg.Web.ClientPaths.Author, url.PathEscape(*user.Slug))
+		urlset.URLs = append(urlset.URLs, model.URL{
+			Loc:     authorURL,
+			LastMod: time.Now().Format(time.RFC3339),
+		})
+	}
+
+	output, err := xml.MarshalIndent(urlset, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(xml.Header + string(output)), nil
+}
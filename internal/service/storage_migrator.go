@@ -0,0 +1,305 @@
+package service
+
+import (
+	"bytes"
+	"chrononewsapi/internal/adapter"
+	"chrononewsapi/internal/config"
+	"chrononewsapi/internal/entity"
+	"chrononewsapi/internal/model"
+	"chrononewsapi/internal/repository"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// StorageMigrator copies the objects of every file, including its processed
+// variants, from one storage backend to another. Each copy is verified with a
+// SHA-256 checksum of the stored object. Progress is checkpointed after every
+// finished batch, and objects already present with a matching checksum are
+// skipped, so an interrupted run can simply be started again.
+type StorageMigrator struct {
+	DB             *gorm.DB
+	FileRepository *repository.FileRepository
+	Source         adapter.StorageAdapter
+	Target         adapter.StorageAdapter
+	SourceConfig   *config.Config
+	TargetConfig   *config.Config
+	Concurrency    int
+	BatchSize      int
+	DryRun         bool
+	StatePath      string
+}
+
+type storageMigrationResult int
+
+const (
+	storageMigrationCopied storageMigrationResult = iota
+	storageMigrationSkipped
+	storageMigrationMissing
+)
+
+type storageMigrationTask struct {
+	FileID     int32
+	SourcePath string
+	TargetPath string
+}
+
+func NewStorageMigrator(db *gorm.DB, fileRepository *repository.FileRepository, source adapter.StorageAdapter, target adapter.StorageAdapter, sourceConfig *config.Config, targetConfig *config.Config) *StorageMigrator {
+	return &StorageMigrator{
+		DB:             db,
+		FileRepository: fileRepository,
+		Source:         source,
+		Target:         target,
+		SourceConfig:   sourceConfig,
+		TargetConfig:   targetConfig,
+		Concurrency:    4,
+		BatchSize:      100,
+	}
+}
+
+func (m *StorageMigrator) Run(ctx context.Context) (*model.StorageMigrationReport, error) {
+	lastID, err := m.readCheckpoint()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &model.StorageMigrationReport{
+		Missing: []model.StorageMigrationObject{},
+		Failed:  []model.StorageMigrationObject{},
+		LastID:  lastID,
+		DryRun:  m.DryRun,
+	}
+	if lastID > 0 {
+		slog.Info("Resuming storage migration", "afterID", lastID)
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		var files []entity.File
+		if err := m.FileRepository.FindAfterID(m.DB.WithContext(ctx), report.LastID, m.BatchSize, &files); err != nil {
+			return report, err
+		}
+		if len(files) == 0 {
+			return report, nil
+		}
+
+		var tasks []storageMigrationTask
+		for _, file := range files {
+			tasks = append(tasks, m.tasksFor(&file)...)
+		}
+
+		m.runBatch(ctx, tasks, report)
+		if err := ctx.Err(); err != nil {
+			// The batch may be incomplete, so the checkpoint is left where it was.
+			return report, err
+		}
+
+		report.Files += int64(len(files))
+		report.LastID = files[len(files)-1].ID
+		if err := m.writeCheckpoint(report.LastID); err != nil {
+			return report, err
+		}
+
+		slog.Info("Storage migration batch finished", "lastID", report.LastID, "files", report.Files, "copied", report.Copied, "skipped", report.Skipped)
+	}
+}
+
+func (m *StorageMigrator) tasksFor(file *entity.File) []storageMigrationTask {
+	sourcePaths := fileObjectPaths(m.SourceConfig, file)
+	targetPaths := fileObjectPaths(m.TargetConfig, file)
+
+	tasks := make([]storageMigrationTask, len(sourcePaths))
+	for i := range sourcePaths {
+		tasks[i] = storageMigrationTask{
+			FileID:     file.ID,
+			SourcePath: sourcePaths[i],
+			TargetPath: targetPaths[i],
+		}
+	}
+	return tasks
+}
+
+func (m *StorageMigrator) runBatch(ctx context.Context, tasks []storageMigrationTask, report *model.StorageMigrationReport) {
+	concurrency := m.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, concurrency)
+
+	for _, task := range tasks {
+		if ctx.Err() != nil {
+			break
+		}
+
+		semaphore <- struct{}{}
+		wg.Add(1)
+		go func(task storageMigrationTask) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			result, err := m.migrateObject(ctx, task)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err != nil:
+				slog.Error("Failed to migrate object", "fileID", task.FileID, "path", task.SourcePath, "error", err)
+				report.Failed = append(report.Failed, model.StorageMigrationObject{FileID: task.FileID, Path: task.SourcePath, Error: err.Error()})
+			case result == storageMigrationMissing:
+				slog.Warn("Object is missing from source storage", "fileID", task.FileID, "path", task.SourcePath)
+				report.Missing = append(report.Missing, model.StorageMigrationObject{FileID: task.FileID, Path: task.SourcePath})
+			case result == storageMigrationSkipped:
+				report.Skipped++
+			default:
+				report.Copied++
+			}
+		}(task)
+	}
+
+	wg.Wait()
+}
+
+func (m *StorageMigrator) migrateObject(ctx context.Context, task storageMigrationTask) (storageMigrationResult, error) {
+	sourceInfo, err := m.Source.Stat(ctx, task.SourcePath)
+	if errors.Is(err, adapter.ErrObjectNotFound) {
+		return storageMigrationMissing, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	targetInfo, err := m.Target.Stat(ctx, task.TargetPath)
+	if err != nil && !errors.Is(err, adapter.ErrObjectNotFound) {
+		return 0, err
+	}
+	if targetInfo != nil && targetInfo.Size == sourceInfo.Size {
+		sourceSum, err := storageChecksum(ctx, m.Source, task.SourcePath)
+		if err != nil {
+			return 0, err
+		}
+		targetSum, err := storageChecksum(ctx, m.Target, task.TargetPath)
+		if err != nil {
+			return 0, err
+		}
+		if bytes.Equal(sourceSum, targetSum) {
+			return storageMigrationSkipped, nil
+		}
+	}
+
+	if m.DryRun {
+		return storageMigrationCopied, nil
+	}
+
+	body, err := m.Source.Get(ctx, task.SourcePath)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if cerr := body.Close(); cerr != nil {
+			slog.Warn("Error closing source object", "error", cerr)
+		}
+	}()
+
+	hash := sha256.New()
+	if err := m.Target.Put(ctx, task.TargetPath, io.TeeReader(body, hash), storageContentType(task.SourcePath)); err != nil {
+		return 0, err
+	}
+
+	targetSum, err := storageChecksum(ctx, m.Target, task.TargetPath)
+	if err != nil {
+		return 0, err
+	}
+	if !bytes.Equal(hash.Sum(nil), targetSum) {
+		if err := m.Target.Delete(ctx, task.TargetPath); err != nil {
+			slog.Error("Failed to delete corrupted copy", "path", task.TargetPath, "error", err)
+		}
+		return 0, fmt.Errorf("checksum mismatch after copying to %s", task.TargetPath)
+	}
+
+	return storageMigrationCopied, nil
+}
+
+func (m *StorageMigrator) readCheckpoint() (int32, error) {
+	if m.StatePath == "" {
+		return 0, nil
+	}
+
+	data, err := os.ReadFile(m.StatePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	lastID, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid checkpoint in %s: %w", m.StatePath, err)
+	}
+	return int32(lastID), nil
+}
+
+func (m *StorageMigrator) writeCheckpoint(lastID int32) error {
+	if m.StatePath == "" || m.DryRun {
+		return nil
+	}
+
+	// Written to a temporary file first so an interruption never leaves a
+	// half-written checkpoint behind.
+	temporaryPath := m.StatePath + ".tmp"
+	if err := os.WriteFile(temporaryPath, []byte(strconv.FormatInt(int64(lastID), 10)+"\n"), 0644); err != nil {
+		return err
+	}
+	return os.Rename(temporaryPath, m.StatePath)
+}
+
+func storageChecksum(ctx context.Context, storage adapter.StorageAdapter, path string) ([]byte, error) {
+	body, err := storage.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := body.Close(); cerr != nil {
+			slog.Warn("Error closing storage object", "error", cerr)
+		}
+	}()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, body); err != nil {
+		return nil, err
+	}
+	return hash.Sum(nil), nil
+}
+
+func storageContentType(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png":
+		return "image/png"
+	case ".jpg", ".jpeg":
+		return "image/jpeg"
+	case ".webp":
+		return "image/webp"
+	case ".avif":
+		return "image/avif"
+	case ".gif":
+		return "image/gif"
+	default:
+		return "application/octet-stream"
+	}
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"chrononewsapi/internal/adapter"
+	"chrononewsapi/internal/config"
+	"chrononewsapi/internal/constant"
+	"chrononewsapi/internal/entity"
+	"chrononewsapi/internal/model"
+	"chrononewsapi/internal/repository"
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const storageReconcileBatchSize = 500
+
+// StorageReconciler compares file rows with the objects in storage. Rows whose
+// object is missing are only reported, since they usually point at a storage
+// problem rather than garbage. Stored objects without a row are removed once
+// they are older than the grace period, and so are attachments once that long
+// has passed since they were unlinked from a post. Attachments that were never
+// linked stay in their uploader's library, unless they are presigned uploads
+// that were never completed.
+type StorageReconciler struct {
+	DB             *gorm.DB
+	FileRepository *repository.FileRepository
+	StorageAdapter adapter.StorageAdapter
+	Config         *config.Config
+}
+
+func NewStorageReconciler(db *gorm.DB, fileRepository *repository.FileRepository, storageAdapter adapter.StorageAdapter, config *config.Config) *StorageReconciler {
+	return &StorageReconciler{
+		DB:             db,
+		FileRepository: fileRepository,
+		StorageAdapter: storageAdapter,
+		Config:         config,
+	}
+}
+
+// Start runs the reconciliation every storage.reconcile_interval seconds. It
+// does nothing when the interval is zero.
+func (r *StorageReconciler) Start(ctx context.Context, wg *sync.WaitGroup) {
+	if r.Config.Storage.ReconcileInterval <= 0 {
+		return
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(time.Duration(r.Config.Storage.ReconcileInterval) * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				report, err := r.Reconcile(ctx, false)
+				if err != nil {
+					slog.Error("Failed to reconcile storage", "error", err)
+					continue
+				}
+				slog.Info("Storage reconciled",
+					"missingObjects", len(report.MissingObjects),
+					"deletedFiles", report.DeletedFiles,
+					"deletedObjects", report.DeletedObjects,
+				)
+			}
+		}
+	}()
+}
+
+func (r *StorageReconciler) Reconcile(ctx context.Context, dryRun bool) (*model.StorageReconcileReport, error) {
+	now := time.Now()
+	graceCutoff := now.Add(-time.Duration(r.Config.Storage.OrphanGracePeriod) * time.Second)
+	uploadCutoff := now.Add(-time.Duration(r.Config.Storage.UploadTTL) * time.Second).Unix()
+	db := r.DB.WithContext(ctx)
+
+	report := &model.StorageReconcileReport{
+		MissingObjects: []model.StorageReconcileItem{},
+		OrphanObjects:  []model.StorageReconcileItem{},
+		UnusedFiles:    []model.StorageReconcileItem{},
+		DryRun:         dryRun,
+	}
+
+	stored := make(map[string]adapter.ObjectInfo)
+	listed := make(map[string]bool)
+	for _, folder := range []string{r.Config.Storage.Thumbnail, r.Config.Storage.Attachment, r.Config.Storage.Profile, r.Config.Storage.Private} {
+		if listed[adapter.ObjectKey(folder)] {
+			continue
+		}
+		listed[adapter.ObjectKey(folder)] = true
+
+		objects, err := r.StorageAdapter.List(ctx, folder)
+		if err != nil {
+			return nil, err
+		}
+		for _, object := range objects {
+			stored[adapter.ObjectKey(object.Path)] = object
+		}
+	}
+
+	known := make(map[string]bool)
+	var sourcePaths []string
+	if err := r.FileRepository.FindSourcePaths(db, &sourcePaths); err != nil {
+		return nil, err
+	}
+	for _, path := range sourcePaths {
+		known[adapter.ObjectKey(path)] = true
+	}
+
+	var unused []entity.File
+	var afterID int32
+	for {
+		var files []entity.File
+		if err := r.FileRepository.FindAfterID(db, afterID, storageReconcileBatchSize, &files); err != nil {
+			return nil, err
+		}
+		if len(files) == 0 {
+			break
+		}
+
+		for _, file := range files {
+			paths := fileObjectPaths(r.Config, &file)
+			for _, path := range paths {
+				known[adapter.ObjectKey(path)] = true
+			}
+
+			// Presigned uploads have no object until the client has sent it.
+			uploading := file.Status == constant.FileStatusUploading && file.CreatedAt > uploadCutoff
+			if _, ok := stored[adapter.ObjectKey(paths[0])]; !ok && !uploading {
+				report.MissingObjects = append(report.MissingObjects, model.StorageReconcileItem{FileID: file.ID, Path: paths[0]})
+			}
+
+			unlinked := file.UnlinkedAt != nil && *file.UnlinkedAt < graceCutoff.Unix()
+			expired := file.Status == constant.FileStatusUploading && file.CreatedAt < uploadCutoff && file.CreatedAt < graceCutoff.Unix()
+			if file.Type == constant.FileTypeAttachment && file.UsedByPostID == nil && file.UsedByUserID == nil && (unlinked || expired) {
+				unused = append(unused, file)
+				report.UnusedFiles = append(report.UnusedFiles, model.StorageReconcileItem{FileID: file.ID, Path: paths[0], Size: file.Size})
+			}
+		}
+
+		afterID = files[len(files)-1].ID
+	}
+
+	for key, object := range stored {
+		// Objects without a known age are left alone rather than guessed at.
+		if known[key] || object.ModifiedAt.IsZero() || object.ModifiedAt.After(graceCutoff) {
+			continue
+		}
+		report.OrphanObjects = append(report.OrphanObjects, model.StorageReconcileItem{Path: object.Path, Size: object.Size})
+	}
+	sort.Slice(report.OrphanObjects, func(i, j int) bool {
+		return report.OrphanObjects[i].Path < report.OrphanObjects[j].Path
+	})
+
+	if dryRun {
+		return report, nil
+	}
+
+	for i := range unused {
+		deleted, err := r.discardUnused(ctx, &unused[i])
+		if err != nil {
+			slog.Error("Failed to discard unused file", "fileID", unused[i].ID, "error", err)
+			continue
+		}
+		if deleted {
+			report.DeletedFiles++
+		}
+	}
+
+	for _, orphan := range report.OrphanObjects {
+		if err := r.StorageAdapter.Delete(ctx, orphan.Path); err != nil {
+			slog.Error("Failed to delete orphaned object", "path", orphan.Path, "error", err)
+			continue
+		}
+		report.DeletedObjects++
+	}
+
+	return report, nil
+}
+
+// discardUnused removes a file that was unlinked when the scan ran. The link
+// is checked again inside the transaction in case the file was used since.
+func (r *StorageReconciler) discardUnused(ctx context.Context, file *entity.File) (bool, error) {
+	tx := r.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	current, err := r.FileRepository.FindByID(tx, file.ID)
+	if err != nil {
+		return false, err
+	}
+	if current.UsedByPostID != nil || current.UsedByUserID != nil {
+		return false, nil
+	}
+
+	var sources []entity.SourceFileToDelete
+	if err := r.FileRepository.FindSourceFiles(tx, current.ID, &sources); err != nil {
+		return false, err
+	}
+
+	shared, err := r.FileRepository.CountSharing(tx, current)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.FileRepository.Discard(tx, current); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return false, err
+	}
+
+	deleteFileObjects(ctx, r.StorageAdapter, r.Config, current, shared > 0, sources)
+
+	return true, nil
+}
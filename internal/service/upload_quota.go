@@ -0,0 +1,129 @@
+package service
+
+import (
+	"chrononewsapi/internal/config"
+	"chrononewsapi/internal/constant"
+	"chrononewsapi/internal/model"
+	"chrononewsapi/internal/repository"
+	"chrononewsapi/internal/utility"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UploadQuota enforces the per-role upload limits. The storage quota covers
+// the files an account still has, while the daily quota counts every byte
+// received since midnight UTC, so deleting files does not reset it. Presigned
+// uploads are counted once they are confirmed. Checks and
+// records are not atomic; concurrent uploads may overshoot a limit by their
+// own size.
+type UploadQuota struct {
+	FileRepository        *repository.FileRepository
+	UserRepository        *repository.UserRepository
+	UploadUsageRepository *repository.UploadUsageRepository
+	Config                *config.Config
+}
+
+func NewUploadQuota(fileRepository *repository.FileRepository, userRepository *repository.UserRepository, uploadUsageRepository *repository.UploadUsageRepository, config *config.Config) *UploadQuota {
+	return &UploadQuota{
+		FileRepository:        fileRepository,
+		UserRepository:        userRepository,
+		UploadUsageRepository: uploadUsageRepository,
+		Config:                config,
+	}
+}
+
+func (q *UploadQuota) limits(role string) config.QuotaConfig {
+	if role == constant.Admin {
+		return q.Config.Upload.Admin
+	}
+	return q.Config.Upload.Journalist
+}
+
+func (q *UploadQuota) Usage(db *gorm.DB, userID int32, role string) (*model.UploadUsage, error) {
+	limits := q.limits(role)
+	now := time.Now()
+	today := quotaDay(now)
+	uploadCutoff := now.Add(-time.Duration(q.Config.Storage.UploadTTL) * time.Second).Unix()
+
+	stored, err := q.FileRepository.SumSizeByUploader(db, userID, uploadCutoff)
+	if err != nil {
+		return nil, err
+	}
+	uploaded, err := q.UploadUsageRepository.FindBytes(db, userID, today)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.UploadUsage{
+		StoredBytes:  stored,
+		StorageLimit: limits.StorageBytes,
+		TodayBytes:   uploaded,
+		DailyLimit:   limits.DailyBytes,
+		ResetsAt:     today + 86400,
+	}, nil
+}
+
+// Check reports whether size more bytes fit in the account's quotas. It
+// returns 413 when the storage quota would be exceeded and 429 when the daily
+// quota would be.
+func (q *UploadQuota) Check(db *gorm.DB, userID int32, size int64) error {
+	role := constant.Journalist
+	if err := q.UserRepository.IsAdmin(db, userID); err == nil {
+		role = constant.Admin
+	}
+
+	limits := q.limits(role)
+	if limits.StorageBytes <= 0 && limits.DailyBytes <= 0 {
+		return nil
+	}
+
+	usage, err := q.Usage(db, userID, role)
+	if err != nil {
+		slog.Error("Failed to load upload usage", "userID", userID, "error", err)
+		return utility.ErrInternalServer
+	}
+
+	if usage.StorageLimit > 0 && usage.StoredBytes+size > usage.StorageLimit {
+		return utility.NewCustomError(http.StatusRequestEntityTooLarge, fmt.Sprintf(
+			"Storage quota exceeded: %s of %s used, this upload needs %s",
+			formatBytes(usage.StoredBytes), formatBytes(usage.StorageLimit), formatBytes(size)))
+	}
+	if usage.DailyLimit > 0 && usage.TodayBytes+size > usage.DailyLimit {
+		return utility.NewCustomError(http.StatusTooManyRequests, fmt.Sprintf(
+			"Daily upload limit reached: %s of %s uploaded today, resets at %s",
+			formatBytes(usage.TodayBytes), formatBytes(usage.DailyLimit),
+			time.Unix(usage.ResetsAt, 0).UTC().Format(time.RFC3339)))
+	}
+
+	return nil
+}
+
+// Record adds size to the account's bytes uploaded today. A failure is only
+// logged, since the upload itself has already succeeded.
+func (q *UploadQuota) Record(db *gorm.DB, userID int32, size int64) {
+	if err := q.UploadUsageRepository.Increment(db, userID, quotaDay(time.Now()), size); err != nil {
+		slog.Error("Failed to record upload usage", "userID", userID, "error", err)
+	}
+}
+
+func quotaDay(t time.Time) int64 {
+	unix := t.Unix()
+	return unix - unix%86400
+}
+
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
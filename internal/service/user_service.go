@@ -29,14 +29,16 @@ type UserService struct {
 	PostRepository  *repository.PostRepository
 	FileRepository  *repository.FileRepository
 	ResetRepository *repository.ResetRepository
-	StorageAdapter  *adapter.StorageAdapter
+	StorageAdapter  adapter.StorageAdapter
+	ScannerAdapter  adapter.ScannerAdapter
 	CaptchaAdapter  *adapter.CaptchaAdapter
 	EmailAdapter    *adapter.EmailAdapter
+	UploadQuota     *UploadQuota
 	Validator       *validator.Validate
 	Config          *config.Config
 }
 
-func NewUserService(db *gorm.DB, userRepository *repository.UserRepository, postRepository *repository.PostRepository, fileRepository *repository.FileRepository, resetRepository *repository.ResetRepository, storageAdapter *adapter.StorageAdapter, captchaAdapter *adapter.CaptchaAdapter, emailAdapter *adapter.EmailAdapter, validator *validator.Validate, config *config.Config) *UserService {
+func NewUserService(db *gorm.DB, userRepository *repository.UserRepository, postRepository *repository.PostRepository, fileRepository *repository.FileRepository, resetRepository *repository.ResetRepository, storageAdapter adapter.StorageAdapter, scannerAdapter adapter.ScannerAdapter, captchaAdapter *adapter.CaptchaAdapter, emailAdapter *adapter.EmailAdapter, uploadQuota *UploadQuota, validator *validator.Validate, config *config.Config) *UserService {
 	return &UserService{
 		DB:              db,
 		UserRepository:  userRepository,
@@ -44,8 +46,10 @@ func NewUserService(db *gorm.DB, userRepository *repository.UserRepository, post
 		FileRepository:  fileRepository,
 		ResetRepository: resetRepository,
 		StorageAdapter:  storageAdapter,
+		ScannerAdapter:  scannerAdapter,
 		CaptchaAdapter:  captchaAdapter,
 		EmailAdapter:    emailAdapter,
+		UploadQuota:     uploadQuota,
 		Validator:       validator,
 		Config:          config,
 	}
@@ -126,20 +130,34 @@ func (s *UserService) Current(ctx context.Context, request *model.Auth) (*model.
 	}
 
 	var profilePicture string
+	var profilePictureVariants map[string]string
 	for _, file := range user.Files {
 		if file.Type == constant.FileTypeProfile {
-			profilePicture = utility.BuildImageURL(s.Config, s.Config.Storage.Profile, file.Name)
+			profilePicture = utility.BuildImageURL(s.StorageAdapter, s.Config.Storage.Profile, file.Name)
+			profilePictureVariants = utility.BuildImageVariants(s.StorageAdapter, s.Config.Storage.Profile, &file)
 			break
 		}
 	}
 
+	usage, err := s.UploadQuota.Usage(db, user.ID, user.Role)
+	if err != nil {
+		slog.Error("Failed to load upload usage for current user", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
 	return &model.UserResponse{
-		ID:             user.ID,
-		Name:           user.Name,
-		ProfilePicture: profilePicture,
-		PhoneNumber:    user.PhoneNumber,
-		Email:          user.Email,
-		Role:           user.Role,
+		ID:                     user.ID,
+		Name:                   user.Name,
+		ProfilePicture:         profilePicture,
+		ProfilePictureVariants: profilePictureVariants,
+		PhoneNumber:            user.PhoneNumber,
+		Email:                  user.Email,
+		Role:                   user.Role,
+		Slug:                   userSlug(user),
+		JobTitle:               user.JobTitle,
+		Bio:                    user.Bio,
+		SocialLinks:            user.SocialLinks,
+		Usage:                  usage,
 	}, nil
 }
 
@@ -189,6 +207,7 @@ func (s *UserService) UpdateProfile(ctx context.Context, request *model.UserUpda
 			Name:         newProfilePictureName,
 			Type:         constant.FileTypeProfile,
 			Status:       constant.FileStatusPending,
+			UploadedByID: &auth.ID,
 			UsedByUserID: &user.ID,
 		}
 		if err := s.FileRepository.Create(tx, newProfilePictureFile); err != nil {
@@ -200,6 +219,13 @@ func (s *UserService) UpdateProfile(ctx context.Context, request *model.UserUpda
 	user.Name = request.Name
 	user.Email = request.Email
 	user.PhoneNumber = request.PhoneNumber
+	user.JobTitle = request.JobTitle
+	user.Bio = request.Bio
+	user.SocialLinks = request.SocialLinks
+
+	if err := s.assignSlug(tx, user, request.Slug); err != nil {
+		return nil, err
+	}
 
 	if err := s.UserRepository.Update(tx, user); err != nil {
 		slog.Error("Failed to update user profile", "error", err)
@@ -215,18 +241,20 @@ func (s *UserService) UpdateProfile(ctx context.Context, request *model.UserUpda
 		storagePath := s.Config.Storage.Profile
 		fullPath := filepath.Join(storagePath, newProfilePictureName)
 
-		if err := s.StorageAdapter.Store(request.ProfilePicture, fullPath); err != nil {
+		if err := storeUploadedImage(ctx, s.StorageAdapter, s.ScannerAdapter, s.Config, request.ProfilePicture, fullPath, newProfilePictureFile); err != nil {
 			slog.Error("Failed to store new profile picture file", "error", err)
 			if delErr := s.FileRepository.Delete(s.DB.WithContext(ctx), newProfilePictureFile); delErr != nil {
 				slog.Error("Failed to delete file record after storage failure", "error", delErr)
 			}
 			newProfilePictureFile = nil
+		} else if err := s.FileRepository.UpdateDimensions(s.DB.WithContext(ctx), newProfilePictureFile); err != nil {
+			slog.Error("Failed to record profile picture dimensions", "error", err)
 		}
 	}
 
 	var profilePictureURL string
 	if newProfilePictureFile != nil {
-		profilePictureURL = utility.BuildImageURL(s.Config, s.Config.Storage.Profile, newProfilePictureFile.Name)
+		profilePictureURL = utility.BuildImageURL(s.StorageAdapter, s.Config.Storage.Profile, newProfilePictureFile.Name)
 	}
 
 	return &model.UserResponse{
@@ -236,6 +264,10 @@ func (s *UserService) UpdateProfile(ctx context.Context, request *model.UserUpda
 		PhoneNumber:    user.PhoneNumber,
 		Email:          user.Email,
 		Role:           user.Role,
+		Slug:           userSlug(user),
+		JobTitle:       user.JobTitle,
+		Bio:            user.Bio,
+		SocialLinks:    user.SocialLinks,
 	}, nil
 }
 
@@ -301,19 +333,23 @@ func (s *UserService) Search(ctx context.Context, request *model.UserSearch, aut
 	var response []model.UserResponse
 	for _, v := range users {
 		var profilePicture string
+		var profilePictureVariants map[string]string
 		for _, file := range v.Files {
 			if file.Type == constant.FileTypeProfile {
-				profilePicture = utility.BuildImageURL(s.Config, s.Config.Storage.Profile, file.Name)
+				profilePicture = utility.BuildImageURL(s.StorageAdapter, s.Config.Storage.Profile, file.Name)
+				profilePictureVariants = utility.BuildImageVariants(s.StorageAdapter, s.Config.Storage.Profile, &file)
 				break
 			}
 		}
 		response = append(response, model.UserResponse{
-			ID:             v.ID,
-			Name:           v.Name,
-			ProfilePicture: profilePicture,
-			PhoneNumber:    v.PhoneNumber,
-			Email:          v.Email,
-			Role:           v.Role,
+			ID:                     v.ID,
+			Name:                   v.Name,
+			ProfilePicture:         profilePicture,
+			ProfilePictureVariants: profilePictureVariants,
+			PhoneNumber:            v.PhoneNumber,
+			Email:                  v.Email,
+			Role:                   v.Role,
+			Slug:                   userSlug(&v),
 		})
 	}
 
@@ -356,20 +392,24 @@ func (s *UserService) Get(ctx context.Context, request *model.UserGet, auth *mod
 	}
 
 	var profilePicture string
+	var profilePictureVariants map[string]string
 	for _, file := range user.Files {
 		if file.Type == constant.FileTypeProfile {
-			profilePicture = utility.BuildImageURL(s.Config, s.Config.Storage.Profile, file.Name)
+			profilePicture = utility.BuildImageURL(s.StorageAdapter, s.Config.Storage.Profile, file.Name)
+			profilePictureVariants = utility.BuildImageVariants(s.StorageAdapter, s.Config.Storage.Profile, &file)
 			break
 		}
 	}
 
 	return &model.UserResponse{
-		ID:             user.ID,
-		Name:           user.Name,
-		ProfilePicture: profilePicture,
-		PhoneNumber:    user.PhoneNumber,
-		Email:          user.Email,
-		Role:           user.Role,
+		ID:                     user.ID,
+		Name:                   user.Name,
+		ProfilePicture:         profilePicture,
+		ProfilePictureVariants: profilePictureVariants,
+		PhoneNumber:            user.PhoneNumber,
+		Email:                  user.Email,
+		Role:                   user.Role,
+		Slug:                   userSlug(user),
 	}, nil
 }
 
@@ -412,6 +452,15 @@ func (s *UserService) Create(ctx context.Context, request *model.UserCreate, aut
 		return nil, utility.ErrInternalServer
 	}
 
+	if err := s.assignSlug(tx, user, ""); err != nil {
+		return nil, err
+	}
+
+	if err := s.UserRepository.Updates(tx, user); err != nil {
+		slog.Error("Failed to set slug for new user", "error", err)
+		return nil, utility.ErrInternalServer
+	}
+
 	var profilePictureName string
 	var profilePictureFile *entity.File
 
@@ -421,6 +470,7 @@ func (s *UserService) Create(ctx context.Context, request *model.UserCreate, aut
 			Name:         profilePictureName,
 			Type:         constant.FileTypeProfile,
 			Status:       constant.FileStatusPending,
+			UploadedByID: &auth.ID,
 			UsedByUserID: &user.ID,
 		}
 		if err := s.FileRepository.Create(tx, profilePictureFile); err != nil {
@@ -482,18 +532,20 @@ func (s *UserService) Create(ctx context.Context, request *model.UserCreate, aut
 
 	if request.ProfilePicture != nil {
 		destinationPath := filepath.Join(s.Config.Storage.Profile, profilePictureName)
-		if err := s.StorageAdapter.Store(request.ProfilePicture, destinationPath); err != nil {
+		if err := storeUploadedImage(ctx, s.StorageAdapter, s.ScannerAdapter, s.Config, request.ProfilePicture, destinationPath, profilePictureFile); err != nil {
 			slog.Error("Failed to store profile picture for new user", "error", err)
 			if delErr := s.FileRepository.Delete(s.DB.WithContext(ctx), profilePictureFile); delErr != nil {
 				slog.Error("Failed to delete file record after storage failure", "error", delErr)
 			}
 			profilePictureName = ""
+		} else if err := s.FileRepository.UpdateDimensions(s.DB.WithContext(ctx), profilePictureFile); err != nil {
+			slog.Error("Failed to record profile picture dimensions", "error", err)
 		}
 	}
 
 	var profilePictureURL string
 	if profilePictureName != "" {
-		profilePictureURL = utility.BuildImageURL(s.Config, s.Config.Storage.Profile, profilePictureName)
+		profilePictureURL = utility.BuildImageURL(s.StorageAdapter, s.Config.Storage.Profile, profilePictureName)
 	}
 
 	return &model.UserResponse{
@@ -503,6 +555,7 @@ func (s *UserService) Create(ctx context.Context, request *model.UserCreate, aut
 		PhoneNumber:    user.PhoneNumber,
 		Email:          user.Email,
 		Role:           user.Role,
+		Slug:           userSlug(user),
 	}, nil
 }
 
@@ -565,6 +618,7 @@ func (s *UserService) Update(ctx context.Context, request *model.UserUpdate, aut
 			Name:         newProfilePictureName,
 			Type:         constant.FileTypeProfile,
 			Status:       constant.FileStatusPending,
+			UploadedByID: &auth.ID,
 			UsedByUserID: &user.ID,
 		}
 		if err := s.FileRepository.Create(tx, newProfilePictureFile); err != nil {
@@ -578,6 +632,10 @@ func (s *UserService) Update(ctx context.Context, request *model.UserUpdate, aut
 	user.PhoneNumber = request.PhoneNumber
 	user.Role = request.Role
 
+	if err := s.assignSlug(tx, user, ""); err != nil {
+		return nil, err
+	}
+
 	if request.Password != "" {
 		hashedPassword, err := utility.HashPassword(request.Password)
 		if err != nil {
@@ -599,21 +657,28 @@ func (s *UserService) Update(ctx context.Context, request *model.UserUpdate, aut
 
 	if request.ProfilePicture != nil {
 		destinationPath := filepath.Join(s.Config.Storage.Profile, newProfilePictureName)
-		if err := s.StorageAdapter.Store(
+		if err := storeUploadedImage(
+			ctx,
+			s.StorageAdapter,
+			s.ScannerAdapter,
+			s.Config,
 			request.ProfilePicture,
 			destinationPath,
+			newProfilePictureFile,
 		); err != nil {
 			slog.Error("Failed to store new profile picture on user update", "error", err)
 			if delErr := s.FileRepository.Delete(s.DB.WithContext(ctx), newProfilePictureFile); delErr != nil {
 				slog.Error("Failed to delete file record after storage failure", "error", delErr)
 			}
 			newProfilePictureFile = nil
+		} else if err := s.FileRepository.UpdateDimensions(s.DB.WithContext(ctx), newProfilePictureFile); err != nil {
+			slog.Error("Failed to record profile picture dimensions", "error", err)
 		}
 	}
 
 	var profilePictureURL string
 	if newProfilePictureFile != nil {
-		profilePictureURL = utility.BuildImageURL(s.Config, s.Config.Storage.Profile, newProfilePictureFile.Name)
+		profilePictureURL = utility.BuildImageURL(s.StorageAdapter, s.Config.Storage.Profile, newProfilePictureFile.Name)
 	}
 
 	return &model.UserResponse{
@@ -623,6 +688,7 @@ func (s *UserService) Update(ctx context.Context, request *model.UserUpdate, aut
 		PhoneNumber:    user.PhoneNumber,
 		Email:          user.Email,
 		Role:           user.Role,
+		Slug:           userSlug(user),
 	}, nil
 }
 
@@ -670,3 +736,38 @@ func (s *UserService) Delete(ctx context.Context, request *model.UserDelete, aut
 
 	return nil
 }
+
+func (s *UserService) assignSlug(tx *gorm.DB, user *entity.User, requested string) error {
+	if requested != "" {
+		slug := utility.Slugify(requested)
+		if slug == "" {
+			return utility.ErrBadRequest
+		}
+		if unique := s.UserRepository.FindIDBySlug(tx, slug); unique != 0 && unique != user.ID {
+			return utility.NewCustomError(http.StatusConflict, "Slug already exists")
+		}
+		user.Slug = &slug
+		return nil
+	}
+
+	if user.Slug != nil {
+		return nil
+	}
+
+	slug := utility.Slugify(user.Name)
+	if slug == "" {
+		slug = "author"
+	}
+	if unique := s.UserRepository.FindIDBySlug(tx, slug); unique != 0 && unique != user.ID {
+		slug = fmt.Sprintf("%s-%d", slug, user.ID)
+	}
+	user.Slug = &slug
+	return nil
+}
+
+func userSlug(user *entity.User) string {
+	if user.Slug == nil {
+		return ""
+	}
+	return *user.Slug
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"chrononewsapi/internal/config"
+	"chrononewsapi/internal/repository"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ViewCounter buffers post view increments in memory and writes them to the
+// database in batches, together with the hourly analytics buckets. Repeated
+// views of the same post by the same visitor inside the dedup window are
+// ignored. With a zero flush interval every view is written immediately.
+type ViewCounter struct {
+	DB                  *gorm.DB
+	PostRepository      *repository.PostRepository
+	AnalyticsRepository *repository.AnalyticsRepository
+	FlushInterval       time.Duration
+	DedupWindow         time.Duration
+
+	mu      sync.Mutex
+	pending map[int32]int64
+	buckets map[viewBucket]int64
+	seen    map[string]time.Time
+}
+
+type viewBucket struct {
+	PostID      int32
+	BucketStart int64
+	Referrer    string
+}
+
+func NewViewCounter(db *gorm.DB, postRepository *repository.PostRepository, analyticsRepository *repository.AnalyticsRepository, config *config.Config) *ViewCounter {
+	return &ViewCounter{
+		DB:                  db,
+		PostRepository:      postRepository,
+		AnalyticsRepository: analyticsRepository,
+		FlushInterval:       time.Duration(config.View.FlushInterval) * time.Second,
+		DedupWindow:         time.Duration(config.View.DedupWindow) * time.Second,
+		pending:             make(map[int32]int64),
+		buckets:             make(map[viewBucket]int64),
+		seen:                make(map[string]time.Time),
+	}
+}
+
+// Start flushes buffered views every FlushInterval until ctx is cancelled,
+// then flushes once more and marks wg done.
+func (c *ViewCounter) Start(ctx context.Context, wg *sync.WaitGroup) {
+	interval := c.FlushInterval
+	if interval <= 0 {
+		interval = c.DedupWindow
+	}
+	if interval <= 0 {
+		return
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				if err := c.Flush(context.Background()); err != nil {
+					slog.Error("Failed to flush view counts on shutdown", "error", err)
+				}
+				return
+			case <-ticker.C:
+				if err := c.Flush(ctx); err != nil {
+					slog.Error("Failed to flush view counts", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// Record counts a view of the post unless any of the visitor's keys has
+// already been counted within the dedup window. It reports whether the view
+// was counted.
+func (c *ViewCounter) Record(ctx context.Context, postID int32, visitors []string, referrer string) (bool, error) {
+	now := time.Now()
+	bucket := viewBucket{
+		PostID:      postID,
+		BucketStart: now.Unix() - now.Unix()%3600,
+		Referrer:    referrer,
+	}
+
+	c.mu.Lock()
+	if c.DedupWindow > 0 && len(visitors) > 0 {
+		keys := make([]string, 0, len(visitors))
+		for _, visitor := range visitors {
+			if visitor == "" {
+				continue
+			}
+			key := visitorKey(postID, visitor)
+			if expiry, ok := c.seen[key]; ok && now.Before(expiry) {
+				c.mu.Unlock()
+				return false, nil
+			}
+			keys = append(keys, key)
+		}
+		for _, key := range keys {
+			c.seen[key] = now.Add(c.DedupWindow)
+		}
+	}
+
+	if c.FlushInterval > 0 {
+		c.pending[postID]++
+		c.buckets[bucket]++
+		c.mu.Unlock()
+		return true, nil
+	}
+	c.mu.Unlock()
+
+	if err := c.write(ctx, map[int32]int64{postID: 1}, map[viewBucket]int64{bucket: 1}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *ViewCounter) Flush(ctx context.Context) error {
+	now := time.Now()
+
+	c.mu.Lock()
+	pending := c.pending
+	buckets := c.buckets
+	c.pending = make(map[int32]int64)
+	c.buckets = make(map[viewBucket]int64)
+	for key, expiry := range c.seen {
+		if !now.Before(expiry) {
+			delete(c.seen, key)
+		}
+	}
+	c.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	if err := c.write(ctx, pending, buckets); err != nil {
+		c.mu.Lock()
+		for postID, count := range pending {
+			c.pending[postID] += count
+		}
+		for bucket, count := range buckets {
+			c.buckets[bucket] += count
+		}
+		c.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+func (c *ViewCounter) write(ctx context.Context, counts map[int32]int64, buckets map[viewBucket]int64) error {
+	postIDs := make([]int32, 0, len(counts))
+	for postID := range counts {
+		postIDs = append(postIDs, postID)
+	}
+	// A stable order keeps concurrent flushes from deadlocking on row locks.
+	sort.Slice(postIDs, func(i, j int) bool { return postIDs[i] < postIDs[j] })
+
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	for _, postID := range postIDs {
+		if err := c.PostRepository.IncrementViewCount(tx, postID, counts[postID]); err != nil {
+			return err
+		}
+	}
+
+	keys := make([]viewBucket, 0, len(buckets))
+	for bucket := range buckets {
+		keys = append(keys, bucket)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].PostID != keys[j].PostID {
+			return keys[i].PostID < keys[j].PostID
+		}
+		if keys[i].BucketStart != keys[j].BucketStart {
+			return keys[i].BucketStart < keys[j].BucketStart
+		}
+		return keys[i].Referrer < keys[j].Referrer
+	})
+
+	for _, bucket := range keys {
+		if err := c.AnalyticsRepository.IncrementBucket(tx, bucket.PostID, bucket.BucketStart, bucket.Referrer, buckets[bucket]); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+func visitorKey(postID int32, visitor string) string {
+	sum := sha256.Sum256([]byte(strconv.Itoa(int(postID)) + "|" + visitor))
+	return hex.EncodeToString(sum[:16])
+}
@@ -1,7 +1,11 @@
 package utility
 
 import (
+	"chrononewsapi/internal/adapter"
+	"chrononewsapi/internal/config"
+	"chrononewsapi/internal/constant"
 	"chrononewsapi/internal/entity"
+	"html"
 	"mime/multipart"
 	"path/filepath"
 	"strconv"
@@ -11,8 +15,24 @@ import (
 	"github.com/google/uuid"
 )
 
+// mediaSelector matches the elements that embed an uploaded file by data-id.
+// Images are rebuilt with their variants; the other elements only get a URL.
+const mediaSelector = "img, a[data-id], audio[data-id], video[data-id]"
+
+// mediaURLAttributes names the attribute each embedding element keeps its URL in.
+var mediaURLAttributes = map[string]string{
+	"img":   "src",
+	"a":     "href",
+	"audio": "src",
+	"video": "src",
+}
+
 func CreateFileName(file *multipart.FileHeader) string {
-	return uuid.New().String() + filepath.Ext(file.Filename)
+	return CreateFileNameFromOriginal(file.Filename)
+}
+
+func CreateFileNameFromOriginal(originalName string) string {
+	return uuid.New().String() + filepath.Ext(originalName)
 }
 
 func ExtractFileIDsFromContent(content string) ([]int32, error) {
@@ -24,7 +44,7 @@ func ExtractFileIDsFromContent(content string) ([]int32, error) {
 	var fileIDs []int32
 	seenIDs := make(map[int32]bool)
 
-	doc.Find("img").Each(func(_ int, sel *goquery.Selection) {
+	doc.Find(mediaSelector).Each(func(_ int, sel *goquery.Selection) {
 		if dataID, exists := sel.Attr("data-id"); exists {
 			id, err := strconv.ParseUint(dataID, 10, 32)
 			if err == nil && !seenIDs[int32(id)] {
@@ -46,27 +66,132 @@ func StripImageSrcFromContent(content string) (string, error) {
 	doc.Find("img").Each(func(_ int, sel *goquery.Selection) {
 		sel.RemoveAttr("src")
 	})
+	doc.Find("a[data-id], audio[data-id], video[data-id]").Each(func(_ int, sel *goquery.Selection) {
+		sel.RemoveAttr(mediaURLAttributes[goquery.NodeName(sel)])
+	})
 
 	return doc.Html()
 }
 
-func RebuildContentWithImageSrc(content string, fileMap map[int32]*entity.File) (string, error) {
+func RebuildContentWithImageSrc(storage adapter.StorageAdapter, folderPathFromConfig string, content string, fileMap map[int32]*entity.File) (string, error) {
+	return rebuildContent(content, fileMap, func(file *entity.File) string {
+		return BuildImageURL(storage, folderPathFromConfig, file.Name)
+	}, func(file *entity.File) string {
+		return BuildImageSrcset(storage, folderPathFromConfig, file)
+	})
+}
+
+// RebuildPreviewContent works like RebuildContentWithImageSrc but gives files
+// that are still private a signed URL, so it may only be used for viewers who
+// are allowed to see unpublished content.
+func RebuildPreviewContent(storage adapter.StorageAdapter, cfg *config.Config, content string, fileMap map[int32]*entity.File) (string, error) {
+	return rebuildContent(content, fileMap, func(file *entity.File) string {
+		return BuildFileURL(storage, cfg, file)
+	}, func(file *entity.File) string {
+		if file.Status == constant.FileStatusUploading || file.Status == constant.FileStatusPrivate {
+			return ""
+		}
+		return BuildImageSrcset(storage, FileFolder(cfg, file.Type), file)
+	})
+}
+
+func rebuildContent(content string, fileMap map[int32]*entity.File, fileURL func(*entity.File) string, fileSrcset func(*entity.File) string) (string, error) {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
 	if err != nil {
 		return "", err
 	}
 
-	doc.Find("img").Each(func(_ int, sel *goquery.Selection) {
-		if dataID, exists := sel.Attr("data-id"); exists {
-			if id, err := strconv.ParseInt(dataID, 10, 32); err == nil {
-				if file, ok := fileMap[int32(id)]; ok {
-					sel.SetAttr("src", file.Name)
-				} else {
-					sel.SetAttr("src", "")
-				}
+	doc.Find(mediaSelector).Each(func(_ int, sel *goquery.Selection) {
+		dataID, exists := sel.Attr("data-id")
+		if !exists {
+			return
+		}
+		id, err := strconv.ParseInt(dataID, 10, 32)
+		if err != nil {
+			return
+		}
+
+		attribute := mediaURLAttributes[goquery.NodeName(sel)]
+		file, ok := fileMap[int32(id)]
+		if !ok {
+			sel.SetAttr(attribute, "")
+			return
+		}
+
+		sel.SetAttr(attribute, fileURL(file))
+		if goquery.NodeName(sel) != "img" {
+			applyMediaMetadata(sel, file)
+			return
+		}
+
+		if srcset := fileSrcset(file); srcset != "" {
+			sel.SetAttr("srcset", srcset)
+			if _, exists := sel.Attr("sizes"); !exists {
+				sel.SetAttr("sizes", constant.ImageSizes)
 			}
 		}
+		applyImageMetadata(sel, file)
 	})
 
 	return doc.Html()
 }
+
+// applyMediaMetadata adds the content type and, for players, the controls an
+// editor is unlikely to have set by hand.
+func applyMediaMetadata(sel *goquery.Selection, file *entity.File) {
+	if goquery.NodeName(sel) == "a" {
+		if _, exists := sel.Attr("type"); !exists && file.MimeType != "" {
+			sel.SetAttr("type", file.MimeType)
+		}
+		return
+	}
+
+	if _, exists := sel.Attr("controls"); !exists {
+		sel.SetAttr("controls", "")
+	}
+	if _, exists := sel.Attr("preload"); !exists {
+		sel.SetAttr("preload", "metadata")
+	}
+	if _, exists := sel.Attr("aria-label"); !exists && file.AltText != "" {
+		sel.SetAttr("aria-label", file.AltText)
+	}
+}
+
+// applyImageMetadata fills in alt text and wraps the image in a figure with
+// its caption and credit, leaving anything the editor wrote by hand alone.
+func applyImageMetadata(sel *goquery.Selection, file *entity.File) {
+	if alt, exists := sel.Attr("alt"); (!exists || strings.TrimSpace(alt) == "") && file.AltText != "" {
+		sel.SetAttr("alt", file.AltText)
+	}
+
+	if file.Caption == "" && file.Credit == "" {
+		return
+	}
+	if sel.Closest("figure").Length() > 0 {
+		return
+	}
+
+	// An image alone in a paragraph takes the paragraph's place, since a
+	// figure is not allowed inside a <p>.
+	if parent := sel.Parent(); goquery.NodeName(parent) == "p" && parent.Children().Length() == 1 && strings.TrimSpace(parent.Text()) == "" {
+		parent.ReplaceWithSelection(sel)
+	}
+
+	var caption strings.Builder
+	caption.WriteString("<figcaption>")
+	caption.WriteString(html.EscapeString(file.Caption))
+	if file.Credit != "" {
+		caption.WriteString(`<span class="credit">`)
+		caption.WriteString(html.EscapeString(file.Credit))
+		caption.WriteString("</span>")
+	}
+	if file.License != "" {
+		caption.WriteString(`<span class="license">`)
+		caption.WriteString(html.EscapeString(file.License))
+		caption.WriteString("</span>")
+	}
+	caption.WriteString("</figcaption>")
+
+	sel.WrapHtml("<figure></figure>")
+	sel.AfterHtml(caption.String())
+}
@@ -0,0 +1,207 @@
+package utility
+
+import (
+	"chrononewsapi/internal/config"
+	"chrononewsapi/internal/model"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+const (
+	defaultContentTags = "p,br,hr,h1,h2,h3,h4,h5,h6,blockquote,pre,code,ul,ol,li,strong,b,em,i,u,s,sub,sup,mark,small,span,div," +
+		"a,img,figure,figcaption,audio,video,table,caption,thead,tbody,tfoot,tr,th,td"
+	defaultContentAttributes = "*:class,*:title,*:data-id,a:href,a:target,a:rel,a:type,img:src,img:srcset,img:sizes,img:alt," +
+		"img:width,img:height,img:loading,audio:src,audio:controls,audio:preload,audio:aria-label,video:src,video:controls,video:preload," +
+		"video:aria-label,video:width,video:height,blockquote:cite,ol:start,th:colspan,th:rowspan,th:scope,td:colspan,td:rowspan"
+	defaultContentSchemes = "http,https,mailto,tel"
+)
+
+const (
+	contentRemovalTag       = "tag not allowed"
+	contentRemovalAttribute = "attribute not allowed"
+	contentRemovalScheme    = "URL scheme not allowed"
+)
+
+// contentDropTags are removed together with their content when they are not
+// allowed. Any other disallowed element is replaced by its children.
+var contentDropTags = map[string]bool{
+	"script": true, "style": true, "iframe": true, "frame": true, "frameset": true, "object": true, "embed": true,
+	"applet": true, "noscript": true, "template": true, "svg": true, "math": true, "title": true, "textarea": true,
+	"select": true,
+}
+
+// contentURLAttributes hold a URL whose scheme is checked against the policy.
+var contentURLAttributes = map[string]bool{
+	"href": true, "src": true, "cite": true, "poster": true, "action": true, "formaction": true,
+	"background": true, "longdesc": true, "usemap": true, "xlink:href": true,
+}
+
+// ContentPolicy is the allowlist post content is sanitized against. Attributes
+// listed under "*" are allowed on every allowed tag.
+type ContentPolicy struct {
+	Tags       map[string]bool
+	Attributes map[string]map[string]bool
+	Schemes    map[string]bool
+}
+
+// NewContentPolicy builds the policy from comma-separated lists. An empty list
+// falls back to the built-in one. Attributes are given as tag:attribute.
+func NewContentPolicy(cfg config.ContentConfig) *ContentPolicy {
+	policy := &ContentPolicy{
+		Tags:       make(map[string]bool),
+		Attributes: make(map[string]map[string]bool),
+		Schemes:    make(map[string]bool),
+	}
+
+	for _, tag := range splitContentList(cfg.AllowedTags, defaultContentTags) {
+		policy.Tags[tag] = true
+	}
+	for _, entry := range splitContentList(cfg.AllowedAttributes, defaultContentAttributes) {
+		tag, attribute, ok := strings.Cut(entry, ":")
+		if !ok {
+			tag, attribute = "*", entry
+		}
+		if policy.Attributes[tag] == nil {
+			policy.Attributes[tag] = make(map[string]bool)
+		}
+		policy.Attributes[tag][attribute] = true
+	}
+	for _, scheme := range splitContentList(cfg.AllowedSchemes, defaultContentSchemes) {
+		policy.Schemes[strings.TrimSuffix(scheme, ":")] = true
+	}
+
+	return policy
+}
+
+func splitContentList(list string, fallback string) []string {
+	if strings.TrimSpace(list) == "" {
+		list = fallback
+	}
+
+	var entries []string
+	for _, entry := range strings.Split(list, ",") {
+		if entry = strings.ToLower(strings.TrimSpace(entry)); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// SanitizeContent removes every tag, attribute and URL the policy does not
+// allow, along with comments, and reports what was removed.
+func SanitizeContent(content string, policy *ContentPolicy) (string, []model.ContentRemoval, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
+	if err != nil {
+		return "", nil, err
+	}
+
+	removals := &contentRemovals{}
+
+	// Attributes on a <body> or <html> tag in the content end up on the
+	// document's own elements, which are never allowed any.
+	doc.Find("html, head, body").Each(func(_ int, sel *goquery.Selection) {
+		node := sel.Get(0)
+		for _, attribute := range node.Attr {
+			removals.add(node.Data, attribute.Key, contentRemovalAttribute)
+		}
+		node.Attr = nil
+	})
+
+	// The parser moves elements such as <script> or <style> that open the
+	// content into <head>.
+	doc.Find("head, body").Each(func(_ int, sel *goquery.Selection) {
+		policy.sanitizeChildren(sel.Get(0), removals)
+	})
+
+	sanitized, err := doc.Html()
+	if err != nil {
+		return "", nil, err
+	}
+	return sanitized, removals.items, nil
+}
+
+func (p *ContentPolicy) sanitizeChildren(parent *html.Node, removals *contentRemovals) {
+	for node := parent.FirstChild; node != nil; {
+		next := node.NextSibling
+
+		switch node.Type {
+		case html.CommentNode:
+			removals.add("#comment", "", contentRemovalTag)
+			parent.RemoveChild(node)
+		case html.ElementNode:
+			if !p.Tags[node.Data] || node.Namespace != "" {
+				removals.add(node.Data, "", contentRemovalTag)
+				if !contentDropTags[node.Data] && node.Namespace == "" && node.FirstChild != nil {
+					next = node.FirstChild
+					for child := node.FirstChild; child != nil; child = node.FirstChild {
+						node.RemoveChild(child)
+						parent.InsertBefore(child, node)
+					}
+				}
+				parent.RemoveChild(node)
+				break
+			}
+
+			p.sanitizeAttributes(node, removals)
+			p.sanitizeChildren(node, removals)
+		}
+
+		node = next
+	}
+}
+
+func (p *ContentPolicy) sanitizeAttributes(node *html.Node, removals *contentRemovals) {
+	kept := node.Attr[:0]
+	for _, attribute := range node.Attr {
+		key := strings.ToLower(attribute.Key)
+		if attribute.Namespace != "" {
+			key = attribute.Namespace + ":" + key
+		}
+
+		switch {
+		case !p.Attributes[node.Data][key] && !p.Attributes["*"][key]:
+			removals.add(node.Data, key, contentRemovalAttribute)
+		case contentURLAttributes[key] && !p.allowsURL(attribute.Val):
+			removals.add(node.Data, key, contentRemovalScheme)
+		default:
+			kept = append(kept, attribute)
+		}
+	}
+	node.Attr = kept
+}
+
+// allowsURL accepts relative URLs and absolute ones with an allowed scheme.
+// Whitespace and control characters are ignored while reading the scheme, as
+// browsers do, so "java\tscript:" is still caught.
+func (p *ContentPolicy) allowsURL(value string) bool {
+	value = strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, value)
+
+	end := strings.IndexAny(value, ":/?#")
+	if end < 0 || value[end] != ':' {
+		return true
+	}
+	return p.Schemes[strings.ToLower(value[:end])]
+}
+
+// contentRemovals counts removals by tag, attribute and reason, in the order
+// they were first seen.
+type contentRemovals struct {
+	items []model.ContentRemoval
+}
+
+func (r *contentRemovals) add(tag string, attribute string, reason string) {
+	for i := range r.items {
+		if r.items[i].Tag == tag && r.items[i].Attribute == attribute && r.items[i].Reason == reason {
+			r.items[i].Count++
+			return
+		}
+	}
+	r.items = append(r.items, model.ContentRemoval{Tag: tag, Attribute: attribute, Reason: reason, Count: 1})
+}
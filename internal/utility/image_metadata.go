@@ -0,0 +1,502 @@
+package utility
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+)
+
+const (
+	exifTagOrientation = 0x0112
+	exifTagArtist      = 0x013B
+	exifTagCopyright   = 0x8298
+
+	sanitizedJPEGQuality = 92
+)
+
+var (
+	jpegSOI       = []byte{0xFF, 0xD8}
+	pngSignature  = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1A, '\n'}
+	exifHeader    = []byte("Exif\x00\x00")
+	iccHeader     = []byte("ICC_PROFILE\x00")
+	errBadImage   = errors.New("malformed image data")
+	pngDropChunks = map[string]bool{"eXIf": true, "tEXt": true, "zTXt": true, "iTXt": true, "tIME": true}
+)
+
+type imageMetadata struct {
+	Orientation int
+	Artist      string
+	Copyright   string
+}
+
+// SanitizeImage removes EXIF, XMP, IPTC and text metadata from JPEG, PNG and
+// WebP data and applies the EXIF orientation to JPEG and PNG pixels. The
+// artist and copyright fields are written back when keepCredit is set. Other
+// formats are returned unchanged.
+func SanitizeImage(data []byte, keepCredit bool) ([]byte, error) {
+	var metadata imageMetadata
+	var err error
+
+	switch {
+	case bytes.HasPrefix(data, jpegSOI):
+		metadata, err = readJPEGMetadata(data)
+	case bytes.HasPrefix(data, pngSignature):
+		metadata, err = readPNGMetadata(data)
+	case isWebP(data):
+		// There is no WebP encoder to apply the orientation with.
+		metadata, err = readWebPMetadata(data)
+		if err != nil {
+			return nil, err
+		}
+		return stripWebP(data, creditFor(metadata, keepCredit))
+	default:
+		return data, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if metadata.Orientation > 1 && metadata.Orientation <= 8 {
+		if data, err = orientImage(data, metadata.Orientation); err != nil {
+			return nil, err
+		}
+	}
+
+	credit := creditFor(metadata, keepCredit)
+	if bytes.HasPrefix(data, jpegSOI) {
+		return stripJPEG(data, credit)
+	}
+	return stripPNG(data, credit)
+}
+
+func creditFor(metadata imageMetadata, keepCredit bool) []byte {
+	if !keepCredit || (metadata.Artist == "" && metadata.Copyright == "") {
+		return nil
+	}
+	return buildCreditTIFF(metadata.Artist, metadata.Copyright)
+}
+
+func isWebP(data []byte) bool {
+	return len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WEBP"
+}
+
+func readJPEGMetadata(data []byte) (imageMetadata, error) {
+	var metadata imageMetadata
+	err := walkJPEG(data, func(marker byte, segment []byte) bool {
+		if marker == 0xE1 && bytes.HasPrefix(segment, exifHeader) {
+			metadata = parseTIFF(segment[len(exifHeader):])
+			return false
+		}
+		return true
+	})
+	return metadata, err
+}
+
+func readPNGMetadata(data []byte) (imageMetadata, error) {
+	var metadata imageMetadata
+	err := walkPNG(data, func(chunkType string, chunk []byte) bool {
+		if chunkType == "eXIf" {
+			metadata = parseTIFF(chunk)
+			return false
+		}
+		return true
+	})
+	return metadata, err
+}
+
+func readWebPMetadata(data []byte) (imageMetadata, error) {
+	var metadata imageMetadata
+	err := walkWebP(data, func(chunkType string, chunk []byte) bool {
+		if chunkType == "EXIF" {
+			metadata = parseTIFF(bytes.TrimPrefix(chunk, exifHeader))
+			return false
+		}
+		return true
+	})
+	return metadata, err
+}
+
+// walkJPEG calls visit with every marker segment before the image data.
+// Returning false stops the walk.
+func walkJPEG(data []byte, visit func(marker byte, segment []byte) bool) error {
+	offset := len(jpegSOI)
+	for offset < len(data) {
+		if data[offset] != 0xFF {
+			return errBadImage
+		}
+		for offset < len(data) && data[offset] == 0xFF {
+			offset++
+		}
+		if offset >= len(data) {
+			return errBadImage
+		}
+		marker := data[offset]
+		offset++
+
+		if marker == 0xDA || marker == 0xD9 {
+			return nil
+		}
+		if marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7) {
+			continue
+		}
+		if offset+2 > len(data) {
+			return errBadImage
+		}
+		length := int(binary.BigEndian.Uint16(data[offset:]))
+		if length < 2 || offset+length > len(data) {
+			return errBadImage
+		}
+		if !visit(marker, data[offset+2:offset+length]) {
+			return nil
+		}
+		offset += length
+	}
+	return errBadImage
+}
+
+func walkPNG(data []byte, visit func(chunkType string, chunk []byte) bool) error {
+	offset := len(pngSignature)
+	for offset+12 <= len(data) {
+		length := int(binary.BigEndian.Uint32(data[offset:]))
+		if length < 0 || offset+12+length > len(data) {
+			return errBadImage
+		}
+		chunkType := string(data[offset+4 : offset+8])
+		if !visit(chunkType, data[offset+8:offset+8+length]) || chunkType == "IEND" {
+			return nil
+		}
+		offset += 12 + length
+	}
+	return errBadImage
+}
+
+func walkWebP(data []byte, visit func(chunkType string, chunk []byte) bool) error {
+	offset := 12
+	for offset+8 <= len(data) {
+		size := int(binary.LittleEndian.Uint32(data[offset+4:]))
+		if size < 0 || offset+8+size > len(data) {
+			return errBadImage
+		}
+		if !visit(string(data[offset:offset+4]), data[offset+8:offset+8+size]) {
+			return nil
+		}
+		offset += 8 + size + size%2
+	}
+	return nil
+}
+
+// parseTIFF reads the orientation and credit fields from the first IFD of an
+// EXIF TIFF structure. Anything it cannot read is left at its zero value.
+func parseTIFF(tiff []byte) imageMetadata {
+	var metadata imageMetadata
+	if len(tiff) < 8 {
+		return metadata
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return metadata
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return metadata
+	}
+	count := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < count; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			break
+		}
+		tag := order.Uint16(tiff[entry:])
+		valueType := order.Uint16(tiff[entry+2:])
+		valueCount := int(order.Uint32(tiff[entry+4:]))
+
+		switch {
+		case tag == exifTagOrientation && valueType == 3:
+			metadata.Orientation = int(order.Uint16(tiff[entry+8:]))
+		case (tag == exifTagArtist || tag == exifTagCopyright) && valueType == 2:
+			value := tiff[entry+8 : entry+12]
+			if valueCount > 4 {
+				start := int(order.Uint32(tiff[entry+8:]))
+				if start < 0 || valueCount < 0 || start+valueCount > len(tiff) {
+					continue
+				}
+				value = tiff[start : start+valueCount]
+			} else {
+				value = value[:valueCount]
+			}
+			text := string(bytes.TrimRight(value, "\x00 "))
+			if tag == exifTagArtist {
+				metadata.Artist = text
+			} else {
+				metadata.Copyright = text
+			}
+		}
+	}
+
+	return metadata
+}
+
+// buildCreditTIFF writes a little-endian TIFF structure holding only the
+// artist and copyright fields.
+func buildCreditTIFF(artist, copyright string) []byte {
+	type field struct {
+		tag   uint16
+		value []byte
+	}
+	var fields []field
+	if artist != "" {
+		fields = append(fields, field{exifTagArtist, append([]byte(artist), 0)})
+	}
+	if copyright != "" {
+		fields = append(fields, field{exifTagCopyright, append([]byte(copyright), 0)})
+	}
+
+	order := binary.LittleEndian
+	dataOffset := 8 + 2 + len(fields)*12 + 4
+
+	var header, values bytes.Buffer
+	header.WriteString("II")
+	_ = binary.Write(&header, order, uint16(42))
+	_ = binary.Write(&header, order, uint32(8))
+	_ = binary.Write(&header, order, uint16(len(fields)))
+	for _, f := range fields {
+		_ = binary.Write(&header, order, f.tag)
+		_ = binary.Write(&header, order, uint16(2))
+		_ = binary.Write(&header, order, uint32(len(f.value)))
+		if len(f.value) <= 4 {
+			inline := make([]byte, 4)
+			copy(inline, f.value)
+			header.Write(inline)
+			continue
+		}
+		_ = binary.Write(&header, order, uint32(dataOffset+values.Len()))
+		values.Write(f.value)
+		if values.Len()%2 == 1 {
+			values.WriteByte(0)
+		}
+	}
+	_ = binary.Write(&header, order, uint32(0))
+
+	return append(header.Bytes(), values.Bytes()...)
+}
+
+// stripJPEG copies the JPEG without EXIF, XMP, IPTC, comment and vendor
+// segments. JFIF, ICC profiles and the Adobe segment are kept because they
+// change how the image is decoded.
+func stripJPEG(data []byte, credit []byte) ([]byte, error) {
+	var out bytes.Buffer
+	out.Grow(len(data))
+	out.Write(jpegSOI)
+
+	if credit != nil {
+		segment := append(append([]byte{}, exifHeader...), credit...)
+		if len(segment)+2 <= 0xFFFF {
+			out.Write([]byte{0xFF, 0xE1})
+			_ = binary.Write(&out, binary.BigEndian, uint16(len(segment)+2))
+			out.Write(segment)
+		}
+	}
+
+	offset := len(jpegSOI)
+	for offset < len(data) {
+		if data[offset] != 0xFF {
+			return nil, errBadImage
+		}
+		start := offset
+		for offset < len(data) && data[offset] == 0xFF {
+			offset++
+		}
+		if offset >= len(data) {
+			return nil, errBadImage
+		}
+		marker := data[offset]
+		offset++
+
+		if marker == 0xDA || marker == 0xD9 {
+			out.Write(data[start:])
+			return out.Bytes(), nil
+		}
+		if marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7) {
+			out.Write(data[start:offset])
+			continue
+		}
+		if offset+2 > len(data) {
+			return nil, errBadImage
+		}
+		length := int(binary.BigEndian.Uint16(data[offset:]))
+		if length < 2 || offset+length > len(data) {
+			return nil, errBadImage
+		}
+		segment := data[offset+2 : offset+length]
+		offset += length
+
+		keep := true
+		switch {
+		case marker == 0xFE:
+			keep = false
+		case marker == 0xE2:
+			keep = bytes.HasPrefix(segment, iccHeader)
+		case marker >= 0xE1 && marker <= 0xEF && marker != 0xEE:
+			keep = false
+		}
+		if keep {
+			out.Write(data[start:offset])
+		}
+	}
+
+	return nil, errBadImage
+}
+
+func stripPNG(data []byte, credit []byte) ([]byte, error) {
+	var out bytes.Buffer
+	out.Grow(len(data))
+	out.Write(pngSignature)
+
+	offset := len(pngSignature)
+	for offset+12 <= len(data) {
+		length := int(binary.BigEndian.Uint32(data[offset:]))
+		if length < 0 || offset+12+length > len(data) {
+			return nil, errBadImage
+		}
+		chunkType := string(data[offset+4 : offset+8])
+		end := offset + 12 + length
+
+		// eXIf has to come before the image data.
+		if chunkType == "IDAT" && credit != nil {
+			writePNGChunk(&out, "eXIf", credit)
+			credit = nil
+		}
+		if !pngDropChunks[chunkType] {
+			out.Write(data[offset:end])
+		}
+		if chunkType == "IEND" {
+			return out.Bytes(), nil
+		}
+		offset = end
+	}
+
+	return nil, errBadImage
+}
+
+// stripWebP drops the EXIF and XMP chunks and clears their flags in the
+// extended header. The credit is only written back to extended files, since
+// simple WebP files cannot carry metadata.
+func stripWebP(data []byte, credit []byte) ([]byte, error) {
+	var out bytes.Buffer
+	out.Grow(len(data))
+	out.Write(data[:12])
+
+	extended := false
+	err := walkWebP(data, func(chunkType string, chunk []byte) bool {
+		switch chunkType {
+		case "EXIF", "XMP ":
+			return true
+		case "VP8X":
+			extended = true
+			chunk = bytes.Clone(chunk)
+			if len(chunk) > 0 {
+				chunk[0] &^= 0x0C
+				if credit != nil {
+					chunk[0] |= 0x08
+				}
+			}
+		}
+		writeWebPChunk(&out, chunkType, chunk)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if extended && credit != nil {
+		writeWebPChunk(&out, "EXIF", credit)
+	}
+
+	result := out.Bytes()
+	binary.LittleEndian.PutUint32(result[4:], uint32(len(result)-8))
+	return result, nil
+}
+
+func writeWebPChunk(out *bytes.Buffer, chunkType string, chunk []byte) {
+	out.WriteString(chunkType)
+	_ = binary.Write(out, binary.LittleEndian, uint32(len(chunk)))
+	out.Write(chunk)
+	if len(chunk)%2 == 1 {
+		out.WriteByte(0)
+	}
+}
+
+func writePNGChunk(out *bytes.Buffer, chunkType string, chunk []byte) {
+	_ = binary.Write(out, binary.BigEndian, uint32(len(chunk)))
+	crc := crc32.NewIEEE()
+	crc.Write([]byte(chunkType))
+	crc.Write(chunk)
+	out.WriteString(chunkType)
+	out.Write(chunk)
+	_ = binary.Write(out, binary.BigEndian, crc.Sum32())
+}
+
+// orientImage redraws the image the way EXIF orientation 2-8 says it should
+// be displayed and encodes it again in its original format.
+func orientImage(data []byte, orientation int) ([]byte, error) {
+	decoded, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := decoded.Bounds()
+	source := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(source, source.Bounds(), decoded, bounds.Min, draw.Src)
+
+	width, height := bounds.Dx(), bounds.Dy()
+	outWidth, outHeight := width, height
+	if orientation >= 5 {
+		outWidth, outHeight = height, width
+	}
+	oriented := image.NewRGBA(image.Rect(0, 0, outWidth, outHeight))
+
+	for y := 0; y < outHeight; y++ {
+		for x := 0; x < outWidth; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2:
+				sx, sy = width-1-x, y
+			case 3:
+				sx, sy = width-1-x, height-1-y
+			case 4:
+				sx, sy = x, height-1-y
+			case 5:
+				sx, sy = y, x
+			case 6:
+				sx, sy = y, height-1-x
+			case 7:
+				sx, sy = width-1-y, height-1-x
+			case 8:
+				sx, sy = width-1-y, x
+			}
+			copy(oriented.Pix[oriented.PixOffset(x, y):oriented.PixOffset(x, y)+4], source.Pix[source.PixOffset(sx, sy):source.PixOffset(sx, sy)+4])
+		}
+	}
+
+	var out bytes.Buffer
+	if format == "png" {
+		err = png.Encode(&out, oriented)
+	} else {
+		err = jpeg.Encode(&out, oriented, &jpeg.Options{Quality: sanitizedJPEGQuality})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
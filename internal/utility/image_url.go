@@ -1,27 +1,206 @@
 package utility
 
 import (
+	"chrononewsapi/internal/adapter"
 	"chrononewsapi/internal/config"
+	"chrononewsapi/internal/constant"
+	"chrononewsapi/internal/entity"
+	"chrononewsapi/internal/model"
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"math"
+	"net/url"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 )
 
-func BuildImageURL(cfg *config.Config, folderPathFromConfig string, fileName string) string {
+func BuildImageURL(storage adapter.StorageAdapter, folderPathFromConfig string, fileName string) string {
 	if fileName == "" {
 		return ""
 	}
 
-	var baseURL string
-	if cfg.Storage.Mode == "local" {
-		baseURL = cfg.Web.BaseURL
-	} else {
-		baseURL = cfg.Storage.CdnURL
-	}
-
 	cleanInput := strings.TrimLeft(folderPathFromConfig, "/\\.")
 
 	cleanPath := filepath.ToSlash(filepath.Join(".", cleanInput))
 
-	return fmt.Sprintf("%s/%s/%s", baseURL, cleanPath, fileName)
+	return storage.URL(cleanPath + "/" + fileName)
+}
+
+func FileFolder(cfg *config.Config, fileType string) string {
+	switch fileType {
+	case constant.FileTypeThumbnail:
+		return cfg.Storage.Thumbnail
+	case constant.FileTypeProfile:
+		return cfg.Storage.Profile
+	default:
+		return cfg.Storage.Attachment
+	}
+}
+
+// FileObjectFolder returns the folder holding a file's objects. Files that
+// are not published yet live in the private folder.
+func FileObjectFolder(cfg *config.Config, file *entity.File) string {
+	if file.Status == constant.FileStatusUploading || file.Status == constant.FileStatusPrivate {
+		return cfg.Storage.Private
+	}
+	return FileFolder(cfg, file.Type)
+}
+
+// BuildFileURL returns the URL of a file's original. Private files get a
+// signed URL that expires, so it may only be returned to viewers who are
+// allowed to see unpublished content.
+func BuildFileURL(storage adapter.StorageAdapter, cfg *config.Config, file *entity.File) string {
+	if file == nil || file.Name == "" {
+		return ""
+	}
+	if file.Status != constant.FileStatusUploading && file.Status != constant.FileStatusPrivate {
+		return BuildImageURL(storage, FileFolder(cfg, file.Type), file.Name)
+	}
+	return BuildPrivateURL(storage, cfg, filepath.Join(cfg.Storage.Private, file.Name))
+}
+
+// BuildFileVariants works like BuildImageVariants but signs the original of
+// a private file, which has no variants yet.
+func BuildFileVariants(storage adapter.StorageAdapter, cfg *config.Config, file *entity.File) map[string]string {
+	if file == nil || file.Name == "" {
+		return nil
+	}
+	if file.Status != constant.FileStatusUploading && file.Status != constant.FileStatusPrivate {
+		return BuildImageVariants(storage, FileFolder(cfg, file.Type), file)
+	}
+	return map[string]string{constant.ImageVariantOriginal: BuildFileURL(storage, cfg, file)}
+}
+
+// BuildPrivateURL presigns a read of the object when the storage backend
+// supports it and otherwise signs a link to the API's private file endpoint.
+func BuildPrivateURL(storage adapter.StorageAdapter, cfg *config.Config, path string) string {
+	ttl := time.Duration(cfg.Storage.PrivateURLTTL) * time.Second
+	if ttl <= 0 {
+		ttl = time.Hour
+	}
+
+	signedURL, err := storage.PresignGet(context.Background(), path, ttl)
+	if err == nil {
+		return signedURL
+	}
+	if !errors.Is(err, adapter.ErrPresignNotSupported) {
+		slog.Error("Failed to presign private file URL", "path", path, "error", err)
+		return ""
+	}
+
+	token, err := CreateMediaToken(cfg.JWT.Secret, path, time.Now().Add(ttl))
+	if err != nil {
+		slog.Error("Failed to sign private file URL", "path", path, "error", err)
+		return ""
+	}
+	return cfg.Web.BaseURL + "/api/file/private/" + url.PathEscape(token)
+}
+
+// BuildImageVariants maps each variant name to its URL. Until processing has
+// finished only the original is returned.
+func BuildImageVariants(storage adapter.StorageAdapter, folderPathFromConfig string, file *entity.File) map[string]string {
+	if file == nil || file.Name == "" {
+		return nil
+	}
+
+	variants := map[string]string{
+		constant.ImageVariantOriginal: BuildImageURL(storage, folderPathFromConfig, file.Name),
+	}
+	if file.Status != constant.FileStatusCompressed {
+		return variants
+	}
+
+	for _, variant := range file.Variants {
+		if variant.Name == "" || variant.File == "" {
+			continue
+		}
+		variants[variant.Name] = BuildImageURL(storage, folderPathFromConfig, variant.File)
+	}
+
+	return variants
+}
+
+// BuildImageSrcset returns a width-descriptor srcset, preferring WebP when two
+// variants share a width. It is empty while the file is still being processed.
+func BuildImageSrcset(storage adapter.StorageAdapter, folderPathFromConfig string, file *entity.File) string {
+	if file == nil || file.Status != constant.FileStatusCompressed {
+		return ""
+	}
+
+	byWidth := make(map[int]entity.FileVariant)
+	for _, variant := range file.Variants {
+		if variant.Width <= 0 || variant.File == "" {
+			continue
+		}
+		if existing, ok := byWidth[variant.Width]; ok && existing.Format == constant.ImageFormatWebP {
+			continue
+		}
+		byWidth[variant.Width] = variant
+	}
+	if len(byWidth) == 0 {
+		return ""
+	}
+
+	if _, ok := byWidth[file.Width]; file.Width > 0 && !ok {
+		byWidth[file.Width] = entity.FileVariant{Width: file.Width, File: file.Name}
+	}
+
+	widths := make([]int, 0, len(byWidth))
+	for width := range byWidth {
+		widths = append(widths, width)
+	}
+	sort.Ints(widths)
+
+	entries := make([]string, len(widths))
+	for i, width := range widths {
+		entries[i] = fmt.Sprintf("%s %dw", BuildImageURL(storage, folderPathFromConfig, byWidth[width].File), width)
+	}
+
+	return strings.Join(entries, ", ")
+}
+
+// BuildImageCrops returns a crop rectangle for each ratio in
+// constant.ImageCropRatios. Each is the largest that fits inside the editor's
+// crop, or the whole image, centred on the focal point as far as the edges
+// allow. It is nil while the image dimensions are unknown.
+func BuildImageCrops(file *entity.File) map[string]model.ImageCrop {
+	if file == nil || file.Width <= 0 || file.Height <= 0 {
+		return nil
+	}
+
+	region := entity.FileCrop{Width: file.Width, Height: file.Height}
+	if file.Crop != nil {
+		region = *file.Crop
+	}
+
+	focusX := float64(region.X) + float64(region.Width)/2
+	focusY := float64(region.Y) + float64(region.Height)/2
+	if file.FocalX != nil && file.FocalY != nil {
+		focusX = *file.FocalX * float64(file.Width)
+		focusY = *file.FocalY * float64(file.Height)
+	}
+
+	crops := make(map[string]model.ImageCrop, len(constant.ImageCropRatios))
+	for name, ratio := range constant.ImageCropRatios {
+		width, height := region.Width, region.Width*ratio[1]/ratio[0]
+		if height > region.Height {
+			width, height = region.Height*ratio[0]/ratio[1], region.Height
+		}
+		width, height = max(width, 1), max(height, 1)
+
+		x := int(math.Round(focusX - float64(width)/2))
+		y := int(math.Round(focusY - float64(height)/2))
+		crops[name] = model.ImageCrop{
+			X:      min(max(x, region.X), region.X+region.Width-width),
+			Y:      min(max(y, region.Y), region.Y+region.Height-height),
+			Width:  width,
+			Height: height,
+		}
+	}
+
+	return crops
 }
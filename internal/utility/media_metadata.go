@@ -0,0 +1,216 @@
+package utility
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+)
+
+const mediaBoxLimit = 16 << 20
+
+var (
+	pdfPagePattern  = regexp.MustCompile(`/Type\s*/Page[^s]`)
+	pdfCountPattern = regexp.MustCompile(`/Count\s+(\d+)`)
+
+	mp3Bitrates = [2][16]int{
+		{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 0},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},
+	}
+	mp3SampleRates = [4][3]int{
+		{11025, 12000, 8000},
+		{0, 0, 0},
+		{22050, 24000, 16000},
+		{44100, 48000, 32000},
+	}
+)
+
+// DetectMediaType works like http.DetectContentType but also recognizes MP3
+// files without an ID3 tag, M4A audio and Ogg audio.
+func DetectMediaType(head []byte) string {
+	switch {
+	case len(head) >= 12 && string(head[4:8]) == "ftyp" && string(head[8:11]) == "M4A":
+		return "audio/mp4"
+	case bytes.HasPrefix(head, []byte("OggS")):
+		return "audio/ogg"
+	case len(head) >= 2 && head[0] == 0xFF && head[1]&0xE6 == 0xE2:
+		return "audio/mpeg"
+	}
+	return http.DetectContentType(head)
+}
+
+// PDFPageCount counts the page objects of a PDF. Pages kept in compressed
+// object streams cannot be seen without inflating them, so the largest page
+// tree count is used as a fallback and 0 is returned when neither is found.
+func PDFPageCount(data []byte) int {
+	if pages := len(pdfPagePattern.FindAll(data, -1)); pages > 0 {
+		return pages
+	}
+
+	pages := 0
+	for _, match := range pdfCountPattern.FindAllSubmatch(data, -1) {
+		if count, err := strconv.Atoi(string(match[1])); err == nil {
+			pages = max(pages, count)
+		}
+	}
+	return pages
+}
+
+// MediaDuration returns the playing time in seconds of MP4, M4A, WAV and MP3
+// files, or 0 when it cannot be read from the headers.
+func MediaDuration(r io.ReaderAt, size int64, mimeType string) float64 {
+	switch mimeType {
+	case "video/mp4", "audio/mp4":
+		return mp4Duration(r, size)
+	case "audio/wave":
+		return wavDuration(r, size)
+	case "audio/mpeg":
+		return mp3Duration(r, size)
+	}
+	return 0
+}
+
+// mp4Duration reads the movie header inside the moov box, which may sit at
+// either end of the file.
+func mp4Duration(r io.ReaderAt, size int64) float64 {
+	header := make([]byte, 16)
+	for offset := int64(0); offset+8 <= size; {
+		if _, err := r.ReadAt(header[:8], offset); err != nil {
+			return 0
+		}
+		boxSize := int64(binary.BigEndian.Uint32(header))
+		headerSize := int64(8)
+		switch boxSize {
+		case 0:
+			boxSize = size - offset
+		case 1:
+			if _, err := r.ReadAt(header[8:16], offset+8); err != nil {
+				return 0
+			}
+			boxSize = int64(binary.BigEndian.Uint64(header[8:]))
+			headerSize = 16
+		}
+		if boxSize < headerSize || offset+boxSize > size {
+			return 0
+		}
+
+		if string(header[4:8]) == "moov" {
+			if boxSize-headerSize > mediaBoxLimit {
+				return 0
+			}
+			moov := make([]byte, boxSize-headerSize)
+			if _, err := r.ReadAt(moov, offset+headerSize); err != nil {
+				return 0
+			}
+			return mvhdDuration(moov)
+		}
+		offset += boxSize
+	}
+	return 0
+}
+
+func mvhdDuration(moov []byte) float64 {
+	for len(moov) >= 8 {
+		boxSize := int(binary.BigEndian.Uint32(moov))
+		if boxSize < 8 || boxSize > len(moov) {
+			return 0
+		}
+		if string(moov[4:8]) != "mvhd" {
+			moov = moov[boxSize:]
+			continue
+		}
+
+		body := moov[8:boxSize]
+		var timescale, duration uint64
+		switch {
+		case len(body) >= 32 && body[0] == 1:
+			timescale = uint64(binary.BigEndian.Uint32(body[20:]))
+			duration = binary.BigEndian.Uint64(body[24:])
+		case len(body) >= 20:
+			timescale = uint64(binary.BigEndian.Uint32(body[12:]))
+			duration = uint64(binary.BigEndian.Uint32(body[16:]))
+		}
+		if timescale == 0 {
+			return 0
+		}
+		return float64(duration) / float64(timescale)
+	}
+	return 0
+}
+
+func wavDuration(r io.ReaderAt, size int64) float64 {
+	header := make([]byte, 8)
+	var byteRate uint32
+	for offset := int64(12); offset+8 <= size; {
+		if _, err := r.ReadAt(header, offset); err != nil {
+			return 0
+		}
+		chunkSize := int64(binary.LittleEndian.Uint32(header[4:]))
+
+		switch string(header[:4]) {
+		case "fmt ":
+			format := make([]byte, 12)
+			if _, err := r.ReadAt(format, offset+8); err != nil {
+				return 0
+			}
+			byteRate = binary.LittleEndian.Uint32(format[8:])
+		case "data":
+			if byteRate == 0 {
+				return 0
+			}
+			return float64(min(chunkSize, size-offset-8)) / float64(byteRate)
+		}
+		offset += 8 + chunkSize + chunkSize%2
+	}
+	return 0
+}
+
+// mp3Duration uses the frame count of a Xing or Info header when there is
+// one, and otherwise assumes a constant bitrate.
+func mp3Duration(r io.ReaderAt, size int64) float64 {
+	head := make([]byte, 10)
+	if _, err := r.ReadAt(head, 0); err != nil {
+		return 0
+	}
+
+	var offset int64
+	if string(head[:3]) == "ID3" {
+		offset = 10 + (int64(head[6])<<21 | int64(head[7])<<14 | int64(head[8])<<7 | int64(head[9]))
+	}
+
+	frame := make([]byte, 64)
+	if _, err := r.ReadAt(frame, offset); err != nil && err != io.EOF {
+		return 0
+	}
+	if frame[0] != 0xFF || frame[1]&0xE0 != 0xE0 || frame[1]&0x06 != 0x02 {
+		return 0
+	}
+
+	version := frame[1] >> 3 & 0x03
+	bitrateIndex := frame[2] >> 4
+	sampleRateIndex := frame[2] >> 2 & 0x03
+	if version == 1 || sampleRateIndex == 3 {
+		return 0
+	}
+
+	table, samplesPerFrame := 0, 1152
+	if version != 3 {
+		table, samplesPerFrame = 1, 576
+	}
+	bitrate := mp3Bitrates[table][bitrateIndex] * 1000
+	sampleRate := mp3SampleRates[version][sampleRateIndex]
+	if bitrate == 0 || sampleRate == 0 {
+		return 0
+	}
+
+	for _, marker := range [][]byte{[]byte("Xing"), []byte("Info")} {
+		if index := bytes.Index(frame, marker); index >= 0 && index+12 <= len(frame) && frame[index+7]&0x01 != 0 {
+			frames := binary.BigEndian.Uint32(frame[index+8:])
+			return float64(frames) * float64(samplesPerFrame) / float64(sampleRate)
+		}
+	}
+
+	return float64(size-offset) * 8 / float64(bitrate)
+}
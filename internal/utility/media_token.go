@@ -0,0 +1,47 @@
+package utility
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// CreateMediaToken signs read access to a single private object until
+// expiresAt.
+func CreateMediaToken(secret string, path string, expiresAt time.Time) (string, error) {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"aud":  "media",
+		"path": path,
+		"exp":  expiresAt.Unix(),
+	}).SignedString([]byte(secret))
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func ValidateMediaToken(secret string, token string) (string, error) {
+	t, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(secret), nil
+	}, jwt.WithAudience("media"), jwt.WithExpirationRequired())
+
+	if err != nil {
+		return "", err
+	}
+
+	claims, ok := t.Claims.(jwt.MapClaims)
+	if !ok {
+		return "", fmt.Errorf("failed to parse claims")
+	}
+
+	path, ok := claims["path"].(string)
+	if !ok || path == "" {
+		return "", fmt.Errorf("invalid path claim")
+	}
+
+	return path, nil
+}
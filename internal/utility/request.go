@@ -0,0 +1,49 @@
+package utility
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ClientIP returns the address of the client. Forwarding headers are already
+// applied to RemoteAddr by config.TrustedRealIP when they come from a trusted
+// proxy, so they are not read here.
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// VisitorKeys identifies the client for view deduplication. The IP address
+// and user agent are always included, and a client-provided X-Visitor-ID is
+// added alongside them. A view is only counted when none of the keys has been
+// seen, so sending a new X-Visitor-ID with each request does not count again.
+func VisitorKeys(r *http.Request) []string {
+	keys := []string{"ip:" + ClientIP(r) + "|" + r.UserAgent()}
+	if id := strings.TrimSpace(r.Header.Get("X-Visitor-ID")); id != "" {
+		if len(id) > 128 {
+			id = id[:128]
+		}
+		keys = append(keys, "id:"+id)
+	}
+	return keys
+}
+
+func ReferrerHost(referrer string) string {
+	if referrer == "" {
+		return ""
+	}
+	parsed, err := url.Parse(referrer)
+	if err != nil || parsed.Hostname() == "" {
+		return ""
+	}
+	host := strings.ToLower(parsed.Hostname())
+	if len(host) > 255 {
+		return ""
+	}
+	return host
+}
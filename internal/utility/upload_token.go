@@ -0,0 +1,69 @@
+package utility
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// UploadClaims describes a single direct upload the holder of the token may
+// perform against the local storage endpoint.
+type UploadClaims struct {
+	FileID      int32
+	Path        string
+	ContentType string
+	Size        int64
+}
+
+func CreateUploadToken(secret string, claims UploadClaims, expiresAt time.Time) (string, error) {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"aud":  "upload",
+		"file": claims.FileID,
+		"path": claims.Path,
+		"type": claims.ContentType,
+		"size": claims.Size,
+		"exp":  expiresAt.Unix(),
+	}).SignedString([]byte(secret))
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func ValidateUploadToken(secret string, token string) (*UploadClaims, error) {
+	t, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(secret), nil
+	}, jwt.WithAudience("upload"), jwt.WithExpirationRequired())
+
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := t.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse claims")
+	}
+
+	fileID, ok := claims["file"].(float64)
+	if !ok || fileID <= 0 {
+		return nil, fmt.Errorf("invalid file claim")
+	}
+	path, ok := claims["path"].(string)
+	if !ok || path == "" {
+		return nil, fmt.Errorf("invalid path claim")
+	}
+	contentType, ok := claims["type"].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid type claim")
+	}
+	size, ok := claims["size"].(float64)
+	if !ok || size <= 0 {
+		return nil, fmt.Errorf("invalid size claim")
+	}
+
+	return &UploadClaims{FileID: int32(fileID), Path: path, ContentType: contentType, Size: int64(size)}, nil
+}
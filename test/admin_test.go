@@ -0,0 +1,351 @@
+package test
+
+import (
+	"bytes"
+	"chrononewsapi/internal/config"
+	"chrononewsapi/internal/constant"
+	"chrononewsapi/internal/entity"
+	"chrononewsapi/internal/model"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminEndpoints(t *testing.T) {
+	ts := httptest.NewServer(testRouter)
+	defer ts.Close()
+
+	clearTables(testDB)
+
+	client := config.NewClient()
+
+	adminToken, err := getAuthToken(t, testDB, ts.URL, "admin-stats@test.com", "admin")
+	assert.NoError(t, err, "Failed to get admin token")
+
+	journalistToken, err := getAuthToken(t, testDB, ts.URL, "journalist-stats@test.com", "journalist")
+	assert.NoError(t, err, "Failed to get journalist token")
+
+	var journalistUser entity.User
+	err = testDB.Where("email = ?", "journalist-stats@test.com").First(&journalistUser).Error
+	assert.NoError(t, err, "Failed to find journalist user for stats tests")
+
+	categoryID, err := createTestCategory(t, client, adminToken, ts.URL)
+	assert.NoError(t, err, "Failed to create test category")
+
+	published := entity.Post{UserID: journalistUser.ID, CategoryID: categoryID, Title: "Stats Post", Summary: "Summary", ViewCount: 42}
+	assert.NoError(t, testDB.Create(&published).Error)
+	draft := entity.Post{UserID: journalistUser.ID, CategoryID: categoryID, Title: "Stats Draft", Summary: "Summary", Status: constant.PostStatusDraft}
+	assert.NoError(t, testDB.Create(&draft).Error)
+
+	failed := entity.File{Name: "failed.png", Type: constant.FileTypeAttachment, Status: constant.FileStatusFailed}
+	assert.NoError(t, testDB.Create(&failed).Error)
+	assert.NoError(t, testDB.Create(&entity.DeadLetterQueue{FileID: failed.ID, ErrorMessage: "decode error"}).Error)
+
+	t.Run("Get Stats - As Journalist", func(t *testing.T) {
+		req, err := http.NewRequest("GET", ts.URL+"/api/admin/stats", nil)
+		assert.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+journalistToken)
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("Get Stats - As Admin", func(t *testing.T) {
+		req, err := http.NewRequest("GET", ts.URL+"/api/admin/stats", nil)
+		assert.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+adminToken)
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data model.AdminStatsResponse `json:"data"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), result.Data.PostsByStatus[constant.PostStatusPublished])
+		assert.Equal(t, int64(1), result.Data.PostsByStatus[constant.PostStatusDraft])
+		assert.Equal(t, int64(42), result.Data.TotalViews)
+		assert.Equal(t, int64(1), result.Data.FilesByStatus[constant.FileStatusFailed])
+		assert.Equal(t, int64(1), result.Data.DeadLetterQueueSize)
+		if assert.NotEmpty(t, result.Data.TopPosts) {
+			assert.Equal(t, published.ID, result.Data.TopPosts[0].ID)
+		}
+		if assert.Len(t, result.Data.PostsByAuthor, 1) {
+			assert.Equal(t, journalistUser.ID, result.Data.PostsByAuthor[0].UserID)
+			assert.Equal(t, int64(2), result.Data.PostsByAuthor[0].Count)
+		}
+	})
+
+	t.Run("List Dead Letters - As Journalist", func(t *testing.T) {
+		req, err := http.NewRequest("GET", ts.URL+"/api/admin/dlq", nil)
+		assert.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+journalistToken)
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("List Dead Letters - As Admin", func(t *testing.T) {
+		req, err := http.NewRequest("GET", ts.URL+"/api/admin/dlq", nil)
+		assert.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+adminToken)
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data       []model.DeadLetterResponse `json:"data"`
+			Pagination model.Pagination           `json:"pagination"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), result.Pagination.TotalItem)
+		if assert.Len(t, result.Data, 1) {
+			assert.Equal(t, "decode error", result.Data[0].ErrorMessage)
+			assert.Equal(t, failed.ID, result.Data[0].File.ID)
+		}
+	})
+
+	t.Run("List Files - Failed By Default", func(t *testing.T) {
+		req, err := http.NewRequest("GET", ts.URL+"/api/admin/file", nil)
+		assert.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+adminToken)
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data []model.AdminFileResponse `json:"data"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		assert.NoError(t, err)
+		if assert.Len(t, result.Data, 1) {
+			assert.Equal(t, failed.ID, result.Data[0].ID)
+			assert.Equal(t, constant.FileStatusFailed, result.Data[0].Status)
+		}
+	})
+
+	t.Run("Requeue Files - As Admin", func(t *testing.T) {
+		lastError := "timeout"
+		assert.NoError(t, testDB.Model(&failed).Updates(map[string]interface{}{"failed_attempts": 5, "last_error": lastError}).Error)
+
+		payload, err := json.Marshal(model.AdminFileRequeue{IDs: []int32{failed.ID}})
+		assert.NoError(t, err)
+		req, err := http.NewRequest("POST", ts.URL+"/api/admin/file/requeue", bytes.NewBuffer(payload))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+adminToken)
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data model.AdminFileRequeueResponse `json:"data"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), result.Data.Requeued)
+
+		var file entity.File
+		assert.NoError(t, testDB.First(&file, failed.ID).Error)
+		assert.Equal(t, constant.FileStatusPending, file.Status)
+		assert.Equal(t, 0, file.FailedAttempts)
+		assert.Nil(t, file.LastError)
+
+		var count int64
+		testDB.Model(&entity.DeadLetterQueue{}).Where("file_id = ?", failed.ID).Count(&count)
+		assert.Equal(t, int64(0), count)
+	})
+
+	t.Run("Requeue Files - Empty IDs", func(t *testing.T) {
+		req, err := http.NewRequest("POST", ts.URL+"/api/admin/file/requeue", bytes.NewBufferString(`{"ids": []}`))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+adminToken)
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("Discard File - As Admin", func(t *testing.T) {
+		broken := entity.File{Name: "broken.png", Type: constant.FileTypeAttachment, Status: constant.FileStatusFailed}
+		assert.NoError(t, testDB.Create(&broken).Error)
+		assert.NoError(t, testDB.Create(&entity.DeadLetterQueue{FileID: broken.ID, ErrorMessage: "corrupt"}).Error)
+
+		req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/api/admin/file/%d", ts.URL, broken.ID), nil)
+		assert.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+adminToken)
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var count int64
+		testDB.Model(&entity.File{}).Where("id = ?", broken.ID).Count(&count)
+		assert.Equal(t, int64(0), count)
+		testDB.Model(&entity.DeadLetterQueue{}).Where("file_id = ?", broken.ID).Count(&count)
+		assert.Equal(t, int64(0), count)
+	})
+
+	t.Run("Discard File - Not Found", func(t *testing.T) {
+		req, err := http.NewRequest("DELETE", ts.URL+"/api/admin/file/999999", nil)
+		assert.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+adminToken)
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	reconcile := func(t *testing.T, token string, dryRun bool) (*http.Response, model.StorageReconcileReport) {
+		req, err := http.NewRequest("POST", fmt.Sprintf("%s/api/admin/storage/reconcile?dryRun=%t", ts.URL, dryRun), nil)
+		assert.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+
+		var result struct {
+			Data model.StorageReconcileReport `json:"data"`
+		}
+		if resp.StatusCode == http.StatusOK {
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		}
+		return resp, result.Data
+	}
+
+	t.Run("Reconcile Storage - As Journalist", func(t *testing.T) {
+		resp, _ := reconcile(t, journalistToken, true)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	gracePeriod := appConfig.Storage.OrphanGracePeriod
+	appConfig.Storage.OrphanGracePeriod = 3600
+	defer func() { appConfig.Storage.OrphanGracePeriod = gracePeriod }()
+
+	old := time.Now().Add(-2 * time.Hour)
+
+	unlinkedAt := old.Unix()
+	unused := entity.File{Name: "unused.png", Type: constant.FileTypeAttachment, Status: constant.FileStatusCompressed, UnlinkedAt: &unlinkedAt}
+	assert.NoError(t, testDB.Create(&unused).Error)
+	assert.NoError(t, testDB.Model(&unused).UpdateColumn("created_at", old.Unix()).Error)
+	unusedPath := filepath.Join(appConfig.Storage.Attachment, unused.Name)
+	assert.NoError(t, os.WriteFile(unusedPath, []byte("unused"), 0644))
+
+	// Never linked, or unlinked recently, or not an attachment: all kept.
+	recentlyUnlinkedAt := time.Now().Unix()
+	kept := []entity.File{
+		{Name: "library.png", Type: constant.FileTypeAttachment, Status: constant.FileStatusCompressed},
+		{Name: "recently-unlinked.png", Type: constant.FileTypeAttachment, Status: constant.FileStatusCompressed, UnlinkedAt: &recentlyUnlinkedAt},
+		{Name: "old-profile.png", Type: constant.FileTypeProfile, Status: constant.FileStatusCompressed, UnlinkedAt: &unlinkedAt},
+	}
+	for i := range kept {
+		assert.NoError(t, testDB.Create(&kept[i]).Error)
+		assert.NoError(t, testDB.Model(&kept[i]).UpdateColumn("created_at", old.Unix()).Error)
+		folder := appConfig.Storage.Attachment
+		if kept[i].Type == constant.FileTypeProfile {
+			folder = appConfig.Storage.Profile
+		}
+		assert.NoError(t, os.WriteFile(filepath.Join(folder, kept[i].Name), []byte("kept"), 0644))
+	}
+
+	missing := entity.File{Name: "missing.png", Type: constant.FileTypeAttachment, Status: constant.FileStatusPending}
+	assert.NoError(t, testDB.Create(&missing).Error)
+
+	orphanPath := filepath.Join(appConfig.Storage.Attachment, "orphan.png")
+	assert.NoError(t, os.WriteFile(orphanPath, []byte("orphan"), 0644))
+	assert.NoError(t, os.Chtimes(orphanPath, old, old))
+
+	recentOrphanPath := filepath.Join(appConfig.Storage.Attachment, "recent-orphan.png")
+	assert.NoError(t, os.WriteFile(recentOrphanPath, []byte("recent"), 0644))
+
+	t.Run("Reconcile Storage - Dry Run", func(t *testing.T) {
+		resp, report := reconcile(t, adminToken, true)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.True(t, report.DryRun)
+
+		missingIDs := make([]int32, 0, len(report.MissingObjects))
+		for _, item := range report.MissingObjects {
+			missingIDs = append(missingIDs, item.FileID)
+		}
+		assert.Contains(t, missingIDs, missing.ID)
+		assert.NotContains(t, missingIDs, unused.ID)
+
+		if assert.Len(t, report.UnusedFiles, 1) {
+			assert.Equal(t, unused.ID, report.UnusedFiles[0].FileID)
+		}
+		if assert.Len(t, report.OrphanObjects, 1) {
+			assert.Equal(t, "orphan.png", filepath.Base(report.OrphanObjects[0].Path))
+		}
+		assert.Equal(t, int64(0), report.DeletedFiles)
+
+		assert.FileExists(t, unusedPath)
+		assert.FileExists(t, orphanPath)
+	})
+
+	t.Run("Reconcile Storage - Delete", func(t *testing.T) {
+		resp, report := reconcile(t, adminToken, false)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, int64(1), report.DeletedFiles)
+		assert.Equal(t, int64(1), report.DeletedObjects)
+
+		var count int64
+		testDB.Model(&entity.File{}).Where("id = ?", unused.ID).Count(&count)
+		assert.Equal(t, int64(0), count)
+		for _, file := range kept {
+			testDB.Model(&entity.File{}).Where("id = ?", file.ID).Count(&count)
+			assert.Equal(t, int64(1), count, file.Name)
+		}
+		testDB.Model(&entity.File{}).Where("id = ?", missing.ID).Count(&count)
+		assert.Equal(t, int64(1), count, "Files with a missing object are only reported")
+
+		assert.NoFileExists(t, unusedPath)
+		assert.NoFileExists(t, orphanPath)
+		assert.FileExists(t, recentOrphanPath)
+	})
+}
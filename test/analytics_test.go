@@ -0,0 +1,166 @@
+package test
+
+import (
+	"chrononewsapi/internal/config"
+	"chrononewsapi/internal/entity"
+	"chrononewsapi/internal/model"
+	"chrononewsapi/internal/repository"
+	"chrononewsapi/internal/service"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyticsEndpoints(t *testing.T) {
+	ts := httptest.NewServer(testRouter)
+	defer ts.Close()
+
+	clearTables(testDB)
+
+	client := config.NewClient()
+
+	adminToken, err := getAuthToken(t, testDB, ts.URL, "admin-analytics@test.com", "admin")
+	assert.NoError(t, err, "Failed to get admin token")
+
+	journalistToken, err := getAuthToken(t, testDB, ts.URL, "journalist-analytics@test.com", "journalist")
+	assert.NoError(t, err, "Failed to get journalist token")
+
+	otherJournalistToken, err := getAuthToken(t, testDB, ts.URL, "other-journalist-analytics@test.com", "journalist")
+	assert.NoError(t, err, "Failed to get other journalist token")
+
+	var journalistUser entity.User
+	err = testDB.Where("email = ?", "journalist-analytics@test.com").First(&journalistUser).Error
+	assert.NoError(t, err, "Failed to find journalist user for analytics tests")
+
+	categoryID, err := createTestCategory(t, client, adminToken, ts.URL)
+	assert.NoError(t, err, "Failed to create test category")
+
+	popular := entity.Post{UserID: journalistUser.ID, CategoryID: categoryID, Title: "Popular Post", Summary: "Summary"}
+	assert.NoError(t, testDB.Create(&popular).Error)
+	quiet := entity.Post{UserID: journalistUser.ID, CategoryID: categoryID, Title: "Quiet Post", Summary: "Summary"}
+	assert.NoError(t, testDB.Create(&quiet).Error)
+
+	view := func(t *testing.T, postID int32, visitor, referrer string) {
+		path := fmt.Sprintf("/api/post/%d/view", postID)
+		if referrer != "" {
+			path += "?referrer=" + url.QueryEscape(referrer)
+		}
+		req, err := http.NewRequest("PATCH", ts.URL+path, nil)
+		assert.NoError(t, err)
+		req.Header.Set("User-Agent", visitor)
+		req.Header.Set("X-Visitor-ID", visitor)
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.NoError(t, resp.Body.Close())
+	}
+
+	view(t, popular.ID, "visitor-1", "https://www.google.com/search?q=news")
+	view(t, popular.ID, "visitor-2", "https://www.google.com/")
+	view(t, popular.ID, "visitor-3", "")
+	view(t, quiet.ID, "visitor-1", "https://twitter.com/someone")
+
+	t.Run("Trending - Last 24 Hours", func(t *testing.T) {
+		resp, err := client.Get(ts.URL + "/api/post/trending?window=24h")
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data []model.TrendingPostResponse `json:"data"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		assert.NoError(t, err)
+		if assert.Len(t, result.Data, 2) {
+			assert.Equal(t, popular.ID, result.Data[0].ID)
+			assert.Equal(t, int64(3), result.Data[0].WindowViews)
+			assert.Equal(t, quiet.ID, result.Data[1].ID)
+		}
+	})
+
+	t.Run("Trending - Invalid Window", func(t *testing.T) {
+		resp, err := client.Get(ts.URL + "/api/post/trending?window=90d")
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("Post Analytics - As Author", func(t *testing.T) {
+		req, err := http.NewRequest("GET", ts.URL+fmt.Sprintf("/api/post/%d/analytics?granularity=hour", popular.ID), nil)
+		assert.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+journalistToken)
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data model.PostAnalyticsResponse `json:"data"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), result.Data.TotalViews)
+		assert.Equal(t, int64(3), result.Data.WindowViews)
+		assert.NotEmpty(t, result.Data.Series)
+		if assert.Len(t, result.Data.TopReferrers, 1) {
+			assert.Equal(t, "www.google.com", result.Data.TopReferrers[0].Referrer)
+			assert.Equal(t, int64(2), result.Data.TopReferrers[0].Views)
+		}
+		assert.Equal(t, float64(2), result.Data.CategoryAverage)
+	})
+
+	t.Run("Post Analytics - Not Author", func(t *testing.T) {
+		req, err := http.NewRequest("GET", ts.URL+fmt.Sprintf("/api/post/%d/analytics", popular.ID), nil)
+		assert.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+otherJournalistToken)
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("View Counter - Flushes On Shutdown", func(t *testing.T) {
+		var before entity.Post
+		assert.NoError(t, testDB.First(&before, quiet.ID).Error)
+
+		counter := service.NewViewCounter(testDB, repository.NewPostRepository(), repository.NewAnalyticsRepository(),
+			&config.Config{View: config.ViewConfig{FlushInterval: 3600, DedupWindow: 1800}})
+		ctx, cancel := context.WithCancel(context.Background())
+		workers := &sync.WaitGroup{}
+		counter.Start(ctx, workers)
+
+		counted, err := counter.Record(ctx, quiet.ID, []string{"shutdown-visitor"}, "")
+		assert.NoError(t, err)
+		assert.True(t, counted)
+
+		var buffered entity.Post
+		assert.NoError(t, testDB.First(&buffered, quiet.ID).Error)
+		assert.Equal(t, before.ViewCount, buffered.ViewCount, "Views should be buffered until a flush")
+
+		cancel()
+		workers.Wait()
+
+		var after entity.Post
+		assert.NoError(t, testDB.First(&after, quiet.ID).Error)
+		assert.Equal(t, before.ViewCount+1, after.ViewCount, "Buffered views should be flushed on shutdown")
+	})
+}
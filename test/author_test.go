@@ -0,0 +1,159 @@
+package test
+
+import (
+	"bytes"
+	"chrononewsapi/internal/config"
+	"chrononewsapi/internal/constant"
+	"chrononewsapi/internal/entity"
+	"chrononewsapi/internal/model"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorEndpoints(t *testing.T) {
+	ts := httptest.NewServer(testRouter)
+	defer ts.Close()
+
+	clearTables(testDB)
+
+	client := config.NewClient()
+
+	adminToken, err := getAuthToken(t, testDB, ts.URL, "admin-author@test.com", "admin")
+	assert.NoError(t, err, "Failed to get admin token")
+
+	journalistToken, err := getAuthToken(t, testDB, ts.URL, "journalist-author@test.com", "journalist")
+	assert.NoError(t, err, "Failed to get journalist token")
+
+	categoryID, err := createTestCategory(t, client, adminToken, ts.URL)
+	assert.NoError(t, err, "Failed to create test category")
+
+	var journalistUser entity.User
+	err = testDB.Where("email = ?", "journalist-author@test.com").First(&journalistUser).Error
+	assert.NoError(t, err, "Failed to find journalist user for author tests")
+
+	t.Run("Update Profile - Public Author Fields", func(t *testing.T) {
+		var b bytes.Buffer
+		w := multipart.NewWriter(&b)
+		assert.NoError(t, w.WriteField("name", "Budi Santoso"))
+		assert.NoError(t, w.WriteField("email", "journalist-author@test.com"))
+		assert.NoError(t, w.WriteField("phoneNumber", "+6281234500001"))
+		assert.NoError(t, w.WriteField("slug", "Budi Santoso"))
+		assert.NoError(t, w.WriteField("jobTitle", "Senior Reporter"))
+		assert.NoError(t, w.WriteField("bio", "Covers politics and the economy."))
+		assert.NoError(t, w.WriteField("socialLinks", `{"twitter": "https://x.com/budisantoso"}`))
+		assert.NoError(t, w.Close())
+
+		req, err := http.NewRequest("PATCH", ts.URL+"/api/user/current/profile", &b)
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", w.FormDataContentType())
+		req.Header.Set("Authorization", "Bearer "+journalistToken)
+
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data model.UserResponse `json:"data"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		assert.NoError(t, err)
+		assert.Equal(t, "budi-santoso", result.Data.Slug)
+		assert.Equal(t, "Senior Reporter", result.Data.JobTitle)
+		assert.Equal(t, "https://x.com/budisantoso", result.Data.SocialLinks["twitter"])
+	})
+
+	t.Run("Update Profile - Duplicate Slug", func(t *testing.T) {
+		var b bytes.Buffer
+		w := multipart.NewWriter(&b)
+		assert.NoError(t, w.WriteField("name", "Test Admin"))
+		assert.NoError(t, w.WriteField("email", "admin-author@test.com"))
+		assert.NoError(t, w.WriteField("phoneNumber", "+6281234500002"))
+		assert.NoError(t, w.WriteField("slug", "budi-santoso"))
+		assert.NoError(t, w.Close())
+
+		req, err := http.NewRequest("PATCH", ts.URL+"/api/user/current/profile", &b)
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", w.FormDataContentType())
+		req.Header.Set("Authorization", "Bearer "+adminToken)
+
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("Get Author - With Published Posts", func(t *testing.T) {
+		published := entity.Post{UserID: journalistUser.ID, CategoryID: categoryID, Title: "Published By Budi", Summary: "Summary"}
+		assert.NoError(t, testDB.Create(&published).Error)
+		draft := entity.Post{UserID: journalistUser.ID, CategoryID: categoryID, Title: "Draft By Budi", Summary: "Summary", Status: constant.PostStatusDraft}
+		assert.NoError(t, testDB.Create(&draft).Error)
+
+		resp, err := client.Get(ts.URL + "/api/author/budi-santoso")
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data       model.AuthorResponse `json:"data"`
+			Pagination model.Pagination     `json:"pagination"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		assert.NoError(t, err)
+		assert.Equal(t, "Budi Santoso", result.Data.Name)
+		assert.Equal(t, "Covers politics and the economy.", result.Data.Bio)
+		assert.Equal(t, int64(1), result.Pagination.TotalItem)
+		if assert.Len(t, result.Data.Posts, 1) {
+			assert.Equal(t, published.ID, result.Data.Posts[0].ID)
+		}
+	})
+
+	t.Run("Get Author - Not Found", func(t *testing.T) {
+		resp, err := client.Get(ts.URL + "/api/author/nobody-here")
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("Authors Sitemap", func(t *testing.T) {
+		resp, err := client.Get(ts.URL + "/sitemap/authors.xml")
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		body, err := io.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		assert.Contains(t, string(body), "http://test-client.com/author/budi-santoso")
+
+		respIndex, err := client.Get(ts.URL + "/sitemap.xml")
+		assert.NoError(t, err)
+		defer func() {
+			err := respIndex.Body.Close()
+			assert.NoError(t, err)
+		}()
+		indexBody, err := io.ReadAll(respIndex.Body)
+		assert.NoError(t, err)
+		assert.Contains(t, string(indexBody), "/sitemap/authors.xml")
+	})
+}
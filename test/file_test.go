@@ -3,18 +3,28 @@ package test
 import (
 	"bytes"
 	"chrononewsapi/internal/config"
+	"chrononewsapi/internal/constant"
+	"chrononewsapi/internal/entity"
 	"chrononewsapi/internal/model"
+	"chrononewsapi/internal/repository"
+	"chrononewsapi/internal/utility"
+	"encoding/binary"
 	"encoding/json"
+	"fmt"
 	"image"
 	"image/color"
+	"image/gif"
+	"image/jpeg"
 	"image/png"
 	"io"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -29,6 +39,13 @@ func createDummyPNG(t *testing.T) io.Reader {
 	return &buf
 }
 
+// storedFilePath returns where a file's original is kept on local storage.
+func storedFilePath(t *testing.T, id int32) string {
+	var file entity.File
+	assert.NoError(t, testDB.First(&file, id).Error)
+	return filepath.Join(utility.FileObjectFolder(appConfig, &file), file.Name)
+}
+
 func TestFileEndpoints(t *testing.T) {
 	ts := httptest.NewServer(testRouter)
 	defer ts.Close()
@@ -77,16 +94,85 @@ func TestFileEndpoints(t *testing.T) {
 		assert.NotEmpty(t, result.Data.Name, "Image name should not be empty")
 		assert.NotZero(t, result.Data.ID, "Image ID should not be zero")
 
-		t.Cleanup(func() {
-
-			fileName := filepath.Base(result.Data.Name)
+		filePath := storedFilePath(t, result.Data.ID)
+		assert.Equal(t, appConfig.Storage.Private, filepath.Dir(filePath), "Unpublished uploads should be kept private")
 
-			filePath := filepath.Join(appConfig.Storage.Attachment, fileName)
+		t.Cleanup(func() {
 			err := os.Remove(filePath)
 			assert.NoError(t, err, "Failed to delete uploaded test file")
 		})
 	})
 
+	t.Run("Upload Image - Signed Private URL", func(t *testing.T) {
+		content, err := io.ReadAll(createDummyPNG(t))
+		assert.NoError(t, err)
+
+		var b bytes.Buffer
+		w := multipart.NewWriter(&b)
+		fw, err := w.CreateFormFile("image", "embargoed.png")
+		assert.NoError(t, err)
+		_, err = fw.Write(content)
+		assert.NoError(t, err)
+		assert.NoError(t, w.Close())
+
+		req, err := http.NewRequest("POST", ts.URL+"/api/image", &b)
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", w.FormDataContentType())
+		req.Header.Set("Authorization", "Bearer "+journalistToken)
+
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var result struct {
+			Data model.ImageUploadResponse `json:"data"`
+		}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Contains(t, result.Data.Name, "/api/file/private/")
+
+		privateURL, err := url.Parse(result.Data.Name)
+		assert.NoError(t, err)
+
+		get := func(t *testing.T, path string) (int, []byte, http.Header) {
+			resp, err := client.Get(ts.URL + path)
+			assert.NoError(t, err)
+			defer func() {
+				err := resp.Body.Close()
+				assert.NoError(t, err)
+			}()
+			body, err := io.ReadAll(resp.Body)
+			assert.NoError(t, err)
+			return resp.StatusCode, body, resp.Header
+		}
+
+		status, body, header := get(t, privateURL.Path)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "image/png", header.Get("Content-Type"))
+		assert.Equal(t, "nosniff", header.Get("X-Content-Type-Options"))
+		assert.True(t, bytes.Equal(content, body), "Signed URL should serve the stored original")
+
+		status, _, _ = get(t, privateURL.Path+"x")
+		assert.Equal(t, http.StatusForbidden, status, "Tampered token should be rejected")
+
+		expired, err := utility.CreateMediaToken(appConfig.JWT.Secret, storedFilePath(t, result.Data.ID), time.Now().Add(-time.Minute))
+		assert.NoError(t, err)
+		status, _, _ = get(t, "/api/file/private/"+expired)
+		assert.Equal(t, http.StatusForbidden, status, "Expired token should be rejected")
+
+		outside, err := utility.CreateMediaToken(appConfig.JWT.Secret, filepath.Join(appConfig.Storage.Attachment, "any.png"), time.Now().Add(time.Minute))
+		assert.NoError(t, err)
+		status, _, _ = get(t, "/api/file/private/"+outside)
+		assert.Equal(t, http.StatusForbidden, status, "Tokens may only reach the private folder")
+
+		t.Cleanup(func() {
+			assert.NoError(t, os.Remove(storedFilePath(t, result.Data.ID)))
+		})
+	})
+
 	t.Run("Upload Image - Authenticated Non-Admin", func(t *testing.T) {
 		var b bytes.Buffer
 		w := multipart.NewWriter(&b)
@@ -200,4 +286,743 @@ func TestFileEndpoints(t *testing.T) {
 
 		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 	})
+
+	var journalistUser entity.User
+	err = testDB.Where("email = ?", "journalist-file@test.com").First(&journalistUser).Error
+	assert.NoError(t, err, "Failed to find journalist user for file tests")
+
+	var adminUser entity.User
+	err = testDB.Where("email = ?", "admin-file@test.com").First(&adminUser).Error
+	assert.NoError(t, err, "Failed to find admin user for file tests")
+
+	t.Run("Search Files - Journalist Sees Own Uploads", func(t *testing.T) {
+		req, err := http.NewRequest("GET", ts.URL+"/api/file?type=attachment", nil)
+		assert.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+journalistToken)
+
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data []model.FileResponse `json:"data"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		assert.NoError(t, err)
+		if assert.Len(t, result.Data, 1) {
+			assert.Equal(t, journalistUser.ID, result.Data[0].Uploader.ID)
+		}
+	})
+
+	t.Run("Search Files - Private Status", func(t *testing.T) {
+		req, err := http.NewRequest("GET", ts.URL+"/api/file?type=attachment&status=private", nil)
+		assert.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+journalistToken)
+
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data []model.FileResponse `json:"data"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		assert.NoError(t, err)
+		if assert.Len(t, result.Data, 1) {
+			assert.Equal(t, constant.FileStatusPrivate, result.Data[0].Status)
+		}
+	})
+
+	t.Run("Search Files - Admin Sees Everything", func(t *testing.T) {
+		req, err := http.NewRequest("GET", ts.URL+"/api/file?type=attachment&linked=false", nil)
+		assert.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+adminToken)
+
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data       []model.FileResponse `json:"data"`
+			Pagination model.Pagination     `json:"pagination"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), result.Pagination.TotalItem)
+	})
+
+	t.Run("Get File - Other Uploader", func(t *testing.T) {
+		file := entity.File{Name: "admin-only.png", Type: constant.FileTypeAttachment, UploadedByID: &adminUser.ID}
+		assert.NoError(t, testDB.Create(&file).Error)
+
+		req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/file/%d", ts.URL, file.ID), nil)
+		assert.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+journalistToken)
+
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("Delete File - Linked", func(t *testing.T) {
+		file := entity.File{Name: "linked.png", Type: constant.FileTypeProfile, UploadedByID: &journalistUser.ID, UsedByUserID: &journalistUser.ID}
+		assert.NoError(t, testDB.Create(&file).Error)
+
+		req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/api/file/%d", ts.URL, file.ID), nil)
+		assert.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+journalistToken)
+
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("Delete File - Unlinked", func(t *testing.T) {
+		file := entity.File{Name: "unlinked.png", Type: constant.FileTypeAttachment, UploadedByID: &journalistUser.ID}
+		assert.NoError(t, testDB.Create(&file).Error)
+
+		req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/api/file/%d", ts.URL, file.ID), nil)
+		assert.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+journalistToken)
+
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var count int64
+		testDB.Model(&entity.File{}).Where("id = ?", file.ID).Count(&count)
+		assert.Equal(t, int64(0), count)
+	})
+
+	t.Run("Upload Image - Duplicate Content Shares Object", func(t *testing.T) {
+		img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+		img.Set(1, 1, color.RGBA{G: 200, A: 255})
+		var content bytes.Buffer
+		assert.NoError(t, png.Encode(&content, img))
+
+		upload := func(t *testing.T) model.ImageUploadResponse {
+			var b bytes.Buffer
+			w := multipart.NewWriter(&b)
+			fw, err := w.CreateFormFile("image", "duplicate.png")
+			assert.NoError(t, err)
+			_, err = fw.Write(content.Bytes())
+			assert.NoError(t, err)
+			assert.NoError(t, w.Close())
+
+			req, err := http.NewRequest("POST", ts.URL+"/api/image", &b)
+			assert.NoError(t, err)
+			req.Header.Set("Content-Type", w.FormDataContentType())
+			req.Header.Set("Authorization", "Bearer "+journalistToken)
+
+			resp, err := client.Do(req)
+			assert.NoError(t, err)
+			defer func() {
+				err := resp.Body.Close()
+				assert.NoError(t, err)
+			}()
+			assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+			var result struct {
+				Data model.ImageUploadResponse `json:"data"`
+			}
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+			return result.Data
+		}
+
+		remove := func(t *testing.T, id int32) {
+			req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/api/file/%d", ts.URL, id), nil)
+			assert.NoError(t, err)
+			req.Header.Set("Authorization", "Bearer "+journalistToken)
+
+			resp, err := client.Do(req)
+			assert.NoError(t, err)
+			defer func() {
+				err := resp.Body.Close()
+				assert.NoError(t, err)
+			}()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		}
+
+		first := upload(t)
+		second := upload(t)
+		assert.NotEqual(t, first.ID, second.ID)
+		assert.Equal(t, storedFilePath(t, first.ID), storedFilePath(t, second.ID), "Identical content should reuse the stored object")
+
+		var files []entity.File
+		assert.NoError(t, testDB.Where("id IN ?", []int32{first.ID, second.ID}).Find(&files).Error)
+		if assert.Len(t, files, 2) {
+			assert.Len(t, files[0].Hash, 64)
+			assert.Equal(t, files[0].Hash, files[1].Hash)
+		}
+
+		objectPath := storedFilePath(t, first.ID)
+		remove(t, first.ID)
+		assert.FileExists(t, objectPath, "Shared object must survive while another file uses it")
+
+		remove(t, second.ID)
+		assert.NoFileExists(t, objectPath, "Object should be removed with its last reference")
+
+		// A published original waiting for the processor must not be shared.
+		pending := upload(t)
+		privatePath := storedFilePath(t, pending.ID)
+		assert.NoError(t, testDB.Model(&entity.File{}).Where("id = ?", pending.ID).Update("status", constant.FileStatusPending).Error)
+		assert.NoError(t, os.Rename(privatePath, storedFilePath(t, pending.ID)))
+
+		duplicate := upload(t)
+		var stored entity.File
+		assert.NoError(t, testDB.First(&stored, duplicate.ID).Error)
+		assert.Equal(t, constant.FileStatusPrivate, stored.Status)
+		assert.NotEqual(t, storedFilePath(t, pending.ID), storedFilePath(t, duplicate.ID), "Duplicate of a pending file should get its own object")
+		assert.FileExists(t, storedFilePath(t, duplicate.ID))
+
+		remove(t, pending.ID)
+		remove(t, duplicate.ID)
+	})
+
+	t.Run("Upload Image - Strips Metadata And Applies Orientation", func(t *testing.T) {
+		keepCopyright := appConfig.Storage.KeepCopyright
+		appConfig.Storage.KeepCopyright = true
+		defer func() { appConfig.Storage.KeepCopyright = keepCopyright }()
+
+		img := image.NewRGBA(image.Rect(0, 0, 40, 20))
+		for x := 0; x < 40; x++ {
+			for y := 0; y < 20; y++ {
+				img.Set(x, y, color.RGBA{R: 255, A: 255})
+			}
+		}
+		var encoded bytes.Buffer
+		assert.NoError(t, jpeg.Encode(&encoded, img, nil))
+
+		// IFD0 with Make, Orientation (rotate 90 CW) and Copyright.
+		order := binary.LittleEndian
+		var tiff bytes.Buffer
+		tiff.WriteString("II")
+		_ = binary.Write(&tiff, order, []uint16{42})
+		_ = binary.Write(&tiff, order, []uint32{8})
+		_ = binary.Write(&tiff, order, []uint16{3})
+		_ = binary.Write(&tiff, order, []uint16{0x010F, 2})
+		_ = binary.Write(&tiff, order, []uint32{10, 50})
+		_ = binary.Write(&tiff, order, []uint16{0x0112, 3})
+		_ = binary.Write(&tiff, order, []uint32{1, 6})
+		_ = binary.Write(&tiff, order, []uint16{0x8298, 2})
+		_ = binary.Write(&tiff, order, []uint32{10, 60, 0})
+		tiff.WriteString("SECRETCAM\x00ACME News\x00")
+
+		segment := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+		var content bytes.Buffer
+		content.Write([]byte{0xFF, 0xD8, 0xFF, 0xE1})
+		_ = binary.Write(&content, binary.BigEndian, uint16(len(segment)+2))
+		content.Write(segment)
+		content.Write(encoded.Bytes()[2:])
+
+		var b bytes.Buffer
+		w := multipart.NewWriter(&b)
+		fw, err := w.CreateFormFile("image", "rotated.jpg")
+		assert.NoError(t, err)
+		_, err = fw.Write(content.Bytes())
+		assert.NoError(t, err)
+		assert.NoError(t, w.Close())
+
+		req, err := http.NewRequest("POST", ts.URL+"/api/image", &b)
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", w.FormDataContentType())
+		req.Header.Set("Authorization", "Bearer "+journalistToken)
+
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var result struct {
+			Data model.ImageUploadResponse `json:"data"`
+		}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+
+		stored, err := os.ReadFile(storedFilePath(t, result.Data.ID))
+		assert.NoError(t, err)
+		assert.NotContains(t, string(stored), "SECRETCAM", "Camera metadata should be stripped")
+		assert.Contains(t, string(stored), "ACME News", "Copyright should be kept")
+
+		imageConfig, err := jpeg.DecodeConfig(bytes.NewReader(stored))
+		assert.NoError(t, err)
+		assert.Equal(t, 20, imageConfig.Width, "Orientation should be applied to the pixels")
+		assert.Equal(t, 40, imageConfig.Height)
+
+		var file entity.File
+		assert.NoError(t, testDB.First(&file, result.Data.ID).Error)
+		assert.Equal(t, int64(len(stored)), file.Size)
+	})
+
+	t.Run("Upload Image - Animated GIF Frame Limit", func(t *testing.T) {
+		upload := func(t *testing.T, frames int) int {
+			palette := color.Palette{color.Black, color.White}
+			animation := &gif.GIF{}
+			for i := 0; i < frames; i++ {
+				animation.Image = append(animation.Image, image.NewPaletted(image.Rect(0, 0, 2, 2), palette))
+				animation.Delay = append(animation.Delay, 10)
+			}
+
+			var b bytes.Buffer
+			w := multipart.NewWriter(&b)
+			fw, err := w.CreateFormFile("image", "animated.gif")
+			assert.NoError(t, err)
+			assert.NoError(t, gif.EncodeAll(fw, animation))
+			assert.NoError(t, w.Close())
+
+			req, err := http.NewRequest("POST", ts.URL+"/api/image", &b)
+			assert.NoError(t, err)
+			req.Header.Set("Content-Type", w.FormDataContentType())
+			req.Header.Set("Authorization", "Bearer "+journalistToken)
+
+			resp, err := client.Do(req)
+			assert.NoError(t, err)
+			defer func() {
+				err := resp.Body.Close()
+				assert.NoError(t, err)
+			}()
+			return resp.StatusCode
+		}
+
+		assert.Equal(t, http.StatusCreated, upload(t, 3))
+		assert.Equal(t, http.StatusBadRequest, upload(t, 301))
+	})
+
+	t.Run("Upload Image - Quotas", func(t *testing.T) {
+		originalQuota := appConfig.Upload.Journalist
+		defer func() {
+			appConfig.Upload.Journalist = originalQuota
+		}()
+
+		upload := func(t *testing.T) (int, string) {
+			var b bytes.Buffer
+			w := multipart.NewWriter(&b)
+			fw, err := w.CreateFormFile("image", "quota.png")
+			assert.NoError(t, err)
+			_, err = io.Copy(fw, createDummyPNG(t))
+			assert.NoError(t, err)
+			assert.NoError(t, w.Close())
+
+			req, err := http.NewRequest("POST", ts.URL+"/api/image", &b)
+			assert.NoError(t, err)
+			req.Header.Set("Content-Type", w.FormDataContentType())
+			req.Header.Set("Authorization", "Bearer "+journalistToken)
+
+			resp, err := client.Do(req)
+			assert.NoError(t, err)
+			defer func() {
+				err := resp.Body.Close()
+				assert.NoError(t, err)
+			}()
+
+			var result utility.ResponseError
+			_ = json.NewDecoder(resp.Body).Decode(&result)
+			return resp.StatusCode, result.Error
+		}
+
+		appConfig.Upload.Journalist = config.QuotaConfig{StorageBytes: 1}
+		status, message := upload(t)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, status)
+		assert.Contains(t, message, "Storage quota exceeded")
+
+		appConfig.Upload.Journalist = config.QuotaConfig{DailyBytes: 1}
+		status, message = upload(t)
+		assert.Equal(t, http.StatusTooManyRequests, status)
+		assert.Contains(t, message, "Daily upload limit reached")
+
+		appConfig.Upload.Journalist = config.QuotaConfig{StorageBytes: 1 << 30, DailyBytes: 1 << 20}
+		status, _ = upload(t)
+		assert.Equal(t, http.StatusCreated, status)
+
+		req, err := http.NewRequest("GET", ts.URL+"/api/user/current", nil)
+		assert.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+journalistToken)
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data model.UserResponse `json:"data"`
+		}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		if assert.NotNil(t, result.Data.Usage) {
+			assert.Positive(t, result.Data.Usage.StoredBytes)
+			assert.Positive(t, result.Data.Usage.TodayBytes)
+			assert.Equal(t, int64(1<<30), result.Data.Usage.StorageLimit)
+			assert.Equal(t, int64(1<<20), result.Data.Usage.DailyLimit)
+			assert.Greater(t, result.Data.Usage.ResetsAt, time.Now().Unix())
+		}
+	})
+
+	t.Run("Update File Crop", func(t *testing.T) {
+		file := entity.File{Name: "wide.jpg", Type: constant.FileTypeThumbnail, UploadedByID: &journalistUser.ID, Width: 2000, Height: 1000}
+		assert.NoError(t, testDB.Create(&file).Error)
+
+		update := func(t *testing.T, payload string) (int, model.FileResponse) {
+			req, err := http.NewRequest("PATCH", fmt.Sprintf("%s/api/file/%d/crop", ts.URL, file.ID), bytes.NewBufferString(payload))
+			assert.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+journalistToken)
+
+			resp, err := client.Do(req)
+			assert.NoError(t, err)
+			defer func() {
+				err := resp.Body.Close()
+				assert.NoError(t, err)
+			}()
+
+			var result struct {
+				Data model.FileResponse `json:"data"`
+			}
+			if resp.StatusCode == http.StatusOK {
+				assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+			}
+			return resp.StatusCode, result.Data
+		}
+
+		t.Run("Focal Point", func(t *testing.T) {
+			status, result := update(t, `{"focalX": 0.9, "focalY": 0.5}`)
+			assert.Equal(t, http.StatusOK, status)
+			assert.Equal(t, model.ImageCrop{X: 1000, Y: 0, Width: 1000, Height: 1000}, result.Crops["1:1"])
+			assert.Equal(t, model.ImageCrop{X: 223, Y: 0, Width: 1777, Height: 1000}, result.Crops["16:9"])
+			assert.Equal(t, model.ImageCrop{X: 1200, Y: 0, Width: 800, Height: 1000}, result.Crops["4:5"])
+		})
+
+		t.Run("Crop Rectangle", func(t *testing.T) {
+			status, result := update(t, `{"crop": {"x": 100, "y": 0, "width": 1000, "height": 1000}}`)
+			assert.Equal(t, http.StatusOK, status)
+			assert.Nil(t, result.FocalX)
+			assert.Equal(t, model.ImageCrop{X: 100, Y: 0, Width: 1000, Height: 1000}, result.Crops["1:1"])
+			assert.Equal(t, model.ImageCrop{X: 100, Y: 219, Width: 1000, Height: 562}, result.Crops["16:9"])
+		})
+
+		t.Run("Outside Image", func(t *testing.T) {
+			status, _ := update(t, `{"crop": {"x": 1500, "y": 0, "width": 1000, "height": 1000}}`)
+			assert.Equal(t, http.StatusBadRequest, status)
+		})
+
+		t.Run("Focal Point Out Of Range", func(t *testing.T) {
+			status, _ := update(t, `{"focalX": 1.5, "focalY": 0.5}`)
+			assert.Equal(t, http.StatusBadRequest, status)
+		})
+	})
+
+	t.Run("Upload Media", func(t *testing.T) {
+		upload := func(t *testing.T, fileName string, content []byte) (int, model.MediaUploadResponse) {
+			var b bytes.Buffer
+			w := multipart.NewWriter(&b)
+			fw, err := w.CreateFormFile("file", fileName)
+			assert.NoError(t, err)
+			_, err = fw.Write(content)
+			assert.NoError(t, err)
+			assert.NoError(t, w.Close())
+
+			req, err := http.NewRequest("POST", ts.URL+"/api/media", &b)
+			assert.NoError(t, err)
+			req.Header.Set("Content-Type", w.FormDataContentType())
+			req.Header.Set("Authorization", "Bearer "+journalistToken)
+
+			resp, err := client.Do(req)
+			assert.NoError(t, err)
+			defer func() {
+				err := resp.Body.Close()
+				assert.NoError(t, err)
+			}()
+
+			var result struct {
+				Data model.MediaUploadResponse `json:"data"`
+			}
+			if resp.StatusCode == http.StatusCreated {
+				assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+			}
+			return resp.StatusCode, result.Data
+		}
+
+		pdf := []byte("%PDF-1.4\n1 0 obj << /Type /Catalog /Pages 2 0 R >> endobj\n" +
+			"2 0 obj << /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 >> endobj\n" +
+			"3 0 obj << /Type /Page /Parent 2 0 R >> endobj\n" +
+			"4 0 obj << /Type /Page /Parent 2 0 R >> endobj\n%%EOF\n")
+
+		// One second of 8 kHz, 8-bit mono PCM.
+		var wav bytes.Buffer
+		wav.WriteString("RIFF")
+		_ = binary.Write(&wav, binary.LittleEndian, uint32(36+8000))
+		wav.WriteString("WAVEfmt ")
+		_ = binary.Write(&wav, binary.LittleEndian, []uint32{16})
+		_ = binary.Write(&wav, binary.LittleEndian, []uint16{1, 1})
+		_ = binary.Write(&wav, binary.LittleEndian, []uint32{8000, 8000})
+		_ = binary.Write(&wav, binary.LittleEndian, []uint16{1, 8})
+		wav.WriteString("data")
+		_ = binary.Write(&wav, binary.LittleEndian, uint32(8000))
+		wav.Write(make([]byte, 8000))
+
+		t.Run("PDF Document", func(t *testing.T) {
+			status, result := upload(t, "ruling.pdf", pdf)
+			assert.Equal(t, http.StatusCreated, status)
+			assert.Equal(t, constant.MediaKindDocument, result.Kind)
+			assert.Equal(t, "application/pdf", result.MimeType)
+			assert.Equal(t, 2, result.Pages)
+
+			var file entity.File
+			assert.NoError(t, testDB.First(&file, result.ID).Error)
+			assert.Equal(t, constant.FileStatusPrivate, file.Status, "Media should stay private until its post is published")
+			assert.FileExists(t, filepath.Join(appConfig.Storage.Private, file.Name))
+		})
+
+		t.Run("WAV Audio", func(t *testing.T) {
+			status, result := upload(t, "interview.wav", wav.Bytes())
+			assert.Equal(t, http.StatusCreated, status)
+			assert.Equal(t, constant.MediaKindAudio, result.Kind)
+			assert.InDelta(t, 1.0, result.Duration, 0.001)
+		})
+
+		t.Run("Extension Mismatch", func(t *testing.T) {
+			status, _ := upload(t, "ruling.mp4", pdf)
+			assert.Equal(t, http.StatusBadRequest, status)
+		})
+
+		t.Run("Unsupported Type", func(t *testing.T) {
+			status, _ := upload(t, "notes.pdf", []byte("just some plain text"))
+			assert.Equal(t, http.StatusBadRequest, status)
+		})
+
+		t.Run("Infected", func(t *testing.T) {
+			var before int64
+			assert.NoError(t, testDB.Model(&entity.File{}).Count(&before).Error)
+
+			infected := append([]byte("%PDF-1.4\n% "+eicarMarker+"\n"), pdf[9:]...)
+			status, _ := upload(t, "infected.pdf", infected)
+			assert.Equal(t, http.StatusUnprocessableEntity, status)
+
+			var after int64
+			assert.NoError(t, testDB.Model(&entity.File{}).Count(&after).Error)
+			assert.Equal(t, before, after, "Infected upload should not create a file record")
+		})
+	})
+
+	t.Run("Update File Metadata", func(t *testing.T) {
+		file := entity.File{Name: "described.png", Type: constant.FileTypeAttachment, UploadedByID: &journalistUser.ID}
+		assert.NoError(t, testDB.Create(&file).Error)
+
+		payload := `{"altText": "A crowd in the square", "caption": "Protesters gathered at noon", "credit": "Jane Doe/Agency", "license": "CC BY 4.0"}`
+		req, err := http.NewRequest("PATCH", fmt.Sprintf("%s/api/file/%d/metadata", ts.URL, file.ID), bytes.NewBufferString(payload))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+journalistToken)
+
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data model.FileResponse `json:"data"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		assert.NoError(t, err)
+		assert.Equal(t, "A crowd in the square", result.Data.AltText)
+		assert.Equal(t, "CC BY 4.0", result.Data.License)
+
+		var stored entity.File
+		assert.NoError(t, testDB.First(&stored, file.ID).Error)
+		assert.Equal(t, "Jane Doe/Agency", stored.Credit)
+	})
+
+	presign := func(t *testing.T, fileName, contentType string, size int) (*http.Response, model.FilePresignResponse) {
+		payload, err := json.Marshal(model.FilePresign{FileName: fileName, ContentType: contentType, Size: int64(size)})
+		assert.NoError(t, err)
+		req, err := http.NewRequest("POST", ts.URL+"/api/file/presign", bytes.NewBuffer(payload))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+journalistToken)
+
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+
+		var result struct {
+			Data model.FilePresignResponse `json:"data"`
+		}
+		if resp.StatusCode == http.StatusCreated {
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		}
+		return resp, result.Data
+	}
+
+	putUpload := func(t *testing.T, presigned model.FilePresignResponse, body []byte) int {
+		uploadURL, err := url.Parse(presigned.UploadURL)
+		assert.NoError(t, err)
+		req, err := http.NewRequest(presigned.Method, ts.URL+uploadURL.Path, bytes.NewReader(body))
+		assert.NoError(t, err)
+		for key, value := range presigned.Headers {
+			req.Header.Set(key, value)
+		}
+
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		return resp.StatusCode
+	}
+
+	confirmUpload := func(t *testing.T, id int32) int {
+		req, err := http.NewRequest("POST", fmt.Sprintf("%s/api/file/%d/confirm", ts.URL, id), nil)
+		assert.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+journalistToken)
+
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		return resp.StatusCode
+	}
+
+	t.Run("Presigned Upload - Success", func(t *testing.T) {
+		body, err := io.ReadAll(createDummyPNG(t))
+		assert.NoError(t, err)
+
+		resp, presigned := presign(t, "photo.png", "image/png", len(body))
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t, http.MethodPut, presigned.Method)
+
+		var file entity.File
+		assert.NoError(t, testDB.First(&file, presigned.ID).Error)
+		assert.Equal(t, constant.FileStatusUploading, file.Status)
+
+		assert.Equal(t, http.StatusBadRequest, confirmUpload(t, presigned.ID), "Confirm before upload should fail")
+		assert.Equal(t, http.StatusOK, putUpload(t, presigned, body))
+		assert.Equal(t, http.StatusOK, confirmUpload(t, presigned.ID))
+
+		assert.NoError(t, testDB.First(&file, presigned.ID).Error)
+		assert.Equal(t, constant.FileStatusPrivate, file.Status)
+		assert.Equal(t, 1, file.Width)
+		assert.Equal(t, 1, file.Height)
+
+		assert.Equal(t, http.StatusConflict, confirmUpload(t, presigned.ID))
+
+		storedPath := filepath.Join(appConfig.Storage.Private, file.Name)
+		assert.FileExists(t, storedPath)
+		assert.NoFileExists(t, filepath.Join(appConfig.Storage.Private, "upload-"+file.Name), "Upload object should be removed once confirmed")
+
+		replacement := bytes.Repeat([]byte("x"), len(body))
+		assert.Equal(t, http.StatusConflict, putUpload(t, presigned, replacement), "Upload URL should not work once confirmed")
+		stored, err := os.ReadFile(storedPath)
+		assert.NoError(t, err)
+		assert.NotEqual(t, replacement, stored)
+		assert.Equal(t, file.Size, int64(len(stored)))
+	})
+
+	t.Run("Presigned Upload - Quota Usage", func(t *testing.T) {
+		body, err := io.ReadAll(createDummyPNG(t))
+		assert.NoError(t, err)
+
+		resp, presigned := presign(t, "quota.png", "image/png", len(body))
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var file entity.File
+		assert.NoError(t, testDB.First(&file, presigned.ID).Error)
+		userID := *file.UploadedByID
+
+		dailyBytes := func() int64 {
+			var total int64
+			testDB.Table("upload_usage").Select("COALESCE(SUM(bytes), 0)").Where("user_id = ?", userID).Scan(&total)
+			return total
+		}
+		before := dailyBytes()
+
+		abandoned, abandonedPresign := presign(t, "abandoned.png", "image/png", len(body))
+		assert.Equal(t, http.StatusCreated, abandoned.StatusCode)
+		assert.Equal(t, before, dailyBytes(), "Presigning alone should not use the daily quota")
+
+		assert.Equal(t, http.StatusOK, putUpload(t, presigned, body))
+		assert.Equal(t, http.StatusOK, confirmUpload(t, presigned.ID))
+		assert.Equal(t, before+int64(len(body)), dailyBytes(), "Confirmed upload should use the daily quota")
+
+		fileRepository := repository.NewFileRepository()
+		cutoff := time.Now().Add(-time.Hour).Unix()
+		stored, err := fileRepository.SumSizeByUploader(testDB, userID, cutoff)
+		assert.NoError(t, err)
+
+		assert.NoError(t, testDB.Model(&entity.File{}).Where("id = ?", abandonedPresign.ID).
+			Update("created_at", cutoff-1).Error)
+		shared := entity.File{Name: file.Name, Type: file.Type, Status: constant.FileStatusPrivate, UploadedByID: &userID, Size: file.Size}
+		assert.NoError(t, testDB.Create(&shared).Error)
+
+		after, err := fileRepository.SumSizeByUploader(testDB, userID, cutoff)
+		assert.NoError(t, err)
+		assert.Equal(t, stored-int64(len(body)), after, "Expired presigns and shared objects should not be counted")
+	})
+
+	t.Run("Presigned Upload - Extension Mismatch", func(t *testing.T) {
+		resp, _ := presign(t, "photo.gif", "image/png", 100)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("Presigned Upload - Too Large", func(t *testing.T) {
+		resp, _ := presign(t, "photo.png", "image/png", 11*1024*1024)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("Presigned Upload - Not An Image", func(t *testing.T) {
+		body := []byte("this is definitely not a png file")
+
+		resp, presigned := presign(t, "fake.png", "image/png", len(body))
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t, http.StatusOK, putUpload(t, presigned, body))
+		assert.Equal(t, http.StatusBadRequest, confirmUpload(t, presigned.ID))
+
+		var count int64
+		testDB.Model(&entity.File{}).Where("id = ?", presigned.ID).Count(&count)
+		assert.Equal(t, int64(0), count, "Rejected upload should be removed")
+	})
+
+	t.Run("Presigned Upload - Tampered Token", func(t *testing.T) {
+		body, err := io.ReadAll(createDummyPNG(t))
+		assert.NoError(t, err)
+
+		resp, presigned := presign(t, "photo.png", "image/png", len(body))
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		presigned.UploadURL += "x"
+		assert.Equal(t, http.StatusForbidden, putUpload(t, presigned, body))
+	})
 }
@@ -0,0 +1,181 @@
+package test
+
+import (
+	"chrononewsapi/internal/config"
+	"chrononewsapi/internal/constant"
+	"chrononewsapi/internal/entity"
+	"chrononewsapi/internal/model"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrontpageEndpoints(t *testing.T) {
+	ts := httptest.NewServer(testRouter)
+	defer ts.Close()
+
+	clearTables(testDB)
+
+	client := config.NewClient()
+
+	adminToken, err := getAuthToken(t, testDB, ts.URL, "admin-frontpage@test.com", "admin")
+	assert.NoError(t, err, "Failed to get admin token")
+
+	journalistToken, err := getAuthToken(t, testDB, ts.URL, "journalist-frontpage@test.com", "journalist")
+	assert.NoError(t, err, "Failed to get journalist token")
+
+	var adminUser entity.User
+	err = testDB.Where("email = ?", "admin-frontpage@test.com").First(&adminUser).Error
+	assert.NoError(t, err, "Failed to find admin user for frontpage tests")
+
+	categoryID, err := createTestCategory(t, client, adminToken, ts.URL)
+	assert.NoError(t, err, "Failed to create test category")
+
+	posts := make([]entity.Post, 3)
+	for i := range posts {
+		posts[i] = entity.Post{UserID: adminUser.ID, CategoryID: categoryID, Title: fmt.Sprintf("Frontpage Post %d", i+1), Summary: "Summary"}
+		assert.NoError(t, testDB.Create(&posts[i]).Error)
+	}
+	draft := entity.Post{UserID: adminUser.ID, CategoryID: categoryID, Title: "Frontpage Draft", Summary: "Summary", Status: constant.PostStatusDraft}
+	assert.NoError(t, testDB.Create(&draft).Error)
+
+	doJSON := func(t *testing.T, method, path, token, body string) *http.Response {
+		req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		return resp
+	}
+
+	t.Run("Update Slot - As Journalist", func(t *testing.T) {
+		body := fmt.Sprintf(`{"postIDs": [%d]}`, posts[0].ID)
+		resp := doJSON(t, "PUT", "/api/frontpage/hero", journalistToken, body)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("Update Slot - Unknown Slot", func(t *testing.T) {
+		body := fmt.Sprintf(`{"postIDs": [%d]}`, posts[0].ID)
+		resp := doJSON(t, "PUT", "/api/frontpage/sidebar", adminToken, body)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("Update Slot - Draft Post", func(t *testing.T) {
+		body := fmt.Sprintf(`{"postIDs": [%d]}`, draft.ID)
+		resp := doJSON(t, "PUT", "/api/frontpage/hero", adminToken, body)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("Update Slot", func(t *testing.T) {
+		body := fmt.Sprintf(`{"postIDs": [%d, %d]}`, posts[2].ID, posts[0].ID)
+		resp := doJSON(t, "PUT", "/api/frontpage/secondary", adminToken, body)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("Update Placement - Pin And Breaking", func(t *testing.T) {
+		body := fmt.Sprintf(`{"pinned": true, "breakingUntil": %d}`, time.Now().Add(time.Hour).Unix())
+		resp := doJSON(t, "PATCH", fmt.Sprintf("/api/post/%d/placement", posts[1].ID), adminToken, body)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data model.PostResponseWithPreload `json:"data"`
+		}
+		err := json.NewDecoder(resp.Body).Decode(&result)
+		assert.NoError(t, err)
+		assert.True(t, result.Data.Pinned)
+		assert.NotZero(t, result.Data.BreakingUntil)
+	})
+
+	t.Run("Update Placement - Breaking In The Past", func(t *testing.T) {
+		body := fmt.Sprintf(`{"pinned": true, "breakingUntil": %d}`, time.Now().Add(-time.Hour).Unix())
+		resp := doJSON(t, "PATCH", fmt.Sprintf("/api/post/%d/placement", posts[1].ID), adminToken, body)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("Get Frontpage", func(t *testing.T) {
+		resp := doJSON(t, "GET", "/api/frontpage", "", "")
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data model.FrontpageResponse `json:"data"`
+		}
+		err := json.NewDecoder(resp.Body).Decode(&result)
+		assert.NoError(t, err)
+
+		if assert.Len(t, result.Data.Breaking, 1) {
+			assert.Equal(t, posts[1].ID, result.Data.Breaking[0].ID)
+		}
+
+		assert.Len(t, result.Data.Slots, len(constant.FrontpageSlots))
+		for _, slot := range result.Data.Slots {
+			if slot.Name != constant.FrontpageSlotSecondary {
+				assert.Empty(t, slot.Posts)
+				continue
+			}
+			if assert.Len(t, slot.Posts, 2) {
+				assert.Equal(t, posts[2].ID, slot.Posts[0].ID)
+				assert.Equal(t, posts[0].ID, slot.Posts[1].ID)
+			}
+		}
+
+		if assert.Len(t, result.Data.Pinned, 1) {
+			assert.Equal(t, categoryID, result.Data.Pinned[0].Category.ID)
+			assert.Len(t, result.Data.Pinned[0].Posts, 1)
+		}
+	})
+
+	t.Run("Search By Category - Pinned First", func(t *testing.T) {
+		resp := doJSON(t, "GET", "/api/post?page=1&size=10&categoryName=Test%20Category", "", "")
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data []model.PostResponseWithPreload `json:"data"`
+		}
+		err := json.NewDecoder(resp.Body).Decode(&result)
+		assert.NoError(t, err)
+		if assert.NotEmpty(t, result.Data) {
+			assert.Equal(t, posts[1].ID, result.Data[0].ID)
+		}
+	})
+}
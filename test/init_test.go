@@ -1,16 +1,20 @@
 package test
 
 import (
+	"bufio"
 	"bytes"
 	"chrononewsapi/internal/bootstrap"
 	"chrononewsapi/internal/config"
 	"chrononewsapi/internal/entity"
 	"chrononewsapi/internal/model"
 	"context"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -22,6 +26,10 @@ import (
 	"gorm.io/gorm"
 )
 
+// eicarMarker is part of the EICAR test file. The fake clamd reports any
+// stream containing it as infected.
+const eicarMarker = "EICAR-STANDARD-ANTIVIRUS-TEST-FILE"
+
 var (
 	testDB      *gorm.DB
 	testRouter  *chi.Mux
@@ -43,6 +51,7 @@ func convertTestConfigToAppConfig(testCfg *TestConfig) *config.Config {
 				Category: "/category",
 				Reset:    "/reset",
 				Forgot:   "/forgot",
+				Author:   "/author",
 			},
 		},
 		DB:      testCfg.DB,
@@ -58,6 +67,9 @@ func convertTestConfigToAppConfig(testCfg *TestConfig) *config.Config {
 			Exp: 2,
 		},
 		SMTP: testCfg.SMTP,
+		View: config.ViewConfig{
+			DedupWindow: 1800,
+		},
 	}
 }
 
@@ -78,19 +90,32 @@ func setupTestServer() {
 	thumbnailsDir := filepath.Join(testTempDir, "thumbnails")
 	attachmentsDir := filepath.Join(testTempDir, "attachments")
 	profilesDir := filepath.Join(testTempDir, "profiles")
+	privateDir := filepath.Join(testTempDir, "private")
 
 	_ = os.MkdirAll(thumbnailsDir, 0755)
 	_ = os.MkdirAll(attachmentsDir, 0755)
 	_ = os.MkdirAll(profilesDir, 0755)
+	_ = os.MkdirAll(privateDir, 0755)
 
 	appConfig.Storage.Thumbnail = thumbnailsDir
 	appConfig.Storage.Attachment = attachmentsDir
 	appConfig.Storage.Profile = profilesDir
+	appConfig.Storage.Private = privateDir
+
+	clamdAddress, err := startFakeClamd()
+	if err != nil {
+		slog.Error("Failed to start fake clamd for tests", "err", err)
+		os.Exit(1)
+	}
+	appConfig.Scanner = config.ScannerConfig{
+		Mode:  "clamd",
+		Clamd: config.ClamdConfig{Address: clamdAddress, Timeout: 5},
+	}
 
 	validator := config.NewValidator()
 	client := config.NewClient()
 
-	bootstrap.Init(testRouter, testDB, appConfig, validator, client, nil)
+	bootstrap.Init(context.Background(), testRouter, testDB, appConfig, validator, client, nil)
 
 	if err := config.Migrate(context.Background(), testDB); err != nil {
 		slog.Error("Failed to migrate database for tests", "err", err)
@@ -98,6 +123,59 @@ func setupTestServer() {
 	}
 }
 
+// startFakeClamd serves the clamd INSTREAM command on a local port for the
+// lifetime of the test process.
+func startFakeClamd() (string, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveFakeClamd(conn)
+		}
+	}()
+
+	return listener.Addr().String(), nil
+}
+
+func serveFakeClamd(conn net.Conn) {
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	reader := bufio.NewReader(conn)
+	if command, err := reader.ReadString(0); err != nil || command != "zINSTREAM\x00" {
+		return
+	}
+
+	var stream bytes.Buffer
+	header := make([]byte, 4)
+	for {
+		if _, err := io.ReadFull(reader, header); err != nil {
+			return
+		}
+		size := binary.BigEndian.Uint32(header)
+		if size == 0 {
+			break
+		}
+		if _, err := io.CopyN(&stream, reader, int64(size)); err != nil {
+			return
+		}
+	}
+
+	reply := "stream: OK\x00"
+	if bytes.Contains(stream.Bytes(), []byte(eicarMarker)) {
+		reply = "stream: Eicar-Test-Signature FOUND\x00"
+	}
+	_, _ = conn.Write([]byte(reply))
+}
+
 func getAuthToken(t *testing.T, db *gorm.DB, serverURL, email, role string) (string, error) {
 	var user entity.User
 	err := db.Where("email = ?", email).First(&user).Error
@@ -171,7 +249,14 @@ func TestMain(m *testing.M) {
 func clearTables(db *gorm.DB) {
 	db.Exec("DELETE FROM source_files_to_delete")
 	db.Exec("DELETE FROM dead_letter_queue")
+	db.Exec("DELETE FROM review_event")
+	db.Exec("DELETE FROM review_comment")
+	db.Exec("DELETE FROM review")
 	db.Exec("DELETE FROM reset")
+	db.Exec("DELETE FROM post_view_bucket")
+	db.Exec("DELETE FROM upload_usage")
+	db.Exec("DELETE FROM frontpage_slot")
+	db.Exec("DELETE FROM post_author")
 	db.Exec("DELETE FROM file")
 	db.Exec("DELETE FROM post")
 	db.Exec("DELETE FROM category")
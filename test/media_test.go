@@ -0,0 +1,126 @@
+package test
+
+import (
+	"chrononewsapi/internal/constant"
+	"chrononewsapi/internal/entity"
+	"chrononewsapi/internal/handler/controller"
+	"chrononewsapi/internal/repository"
+	"chrononewsapi/internal/service"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMediaServing(t *testing.T) {
+	root := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "photo.jpg"), []byte("jpeg original"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "photo.webp"), []byte("webp variant"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "plain.png"), []byte("png original"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, ".hidden"), []byte("hidden"), 0644))
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "nested"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "nested", "inner.jpg"), []byte("inner"), 0644))
+
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "processed.jpg"), []byte("processed original"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "processed-1200.webp"), []byte("processed variant"), 0644))
+	assert.NoError(t, testDB.Where("name = ?", "processed.jpg").Delete(&entity.File{}).Error)
+	assert.NoError(t, testDB.Create(&entity.File{
+		Name:   "processed.jpg",
+		Type:   constant.FileTypeAttachment,
+		Status: constant.FileStatusCompressed,
+		Width:  1200,
+		Variants: []entity.FileVariant{
+			{Name: "small", Width: 480, Format: constant.ImageFormatWebP, File: "processed-480.webp"},
+			{Name: "large", Width: 1200, Format: constant.ImageFormatWebP, File: "processed-1200.webp"},
+		},
+	}).Error)
+
+	fileService := &service.FileService{DB: testDB, FileRepository: repository.NewFileRepository()}
+	ts := httptest.NewServer(http.StripPrefix("/media", controller.NewMediaController(fileService).Serve(root)))
+	defer ts.Close()
+
+	client := &http.Client{}
+
+	get := func(t *testing.T, path string, header map[string]string) (*http.Response, string) {
+		req, err := http.NewRequest("GET", ts.URL+path, nil)
+		assert.NoError(t, err)
+		for key, value := range header {
+			req.Header.Set(key, value)
+		}
+
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		body, err := io.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		return resp, string(body)
+	}
+
+	t.Run("Serve File", func(t *testing.T) {
+		resp, body := get(t, "/media/photo.jpg", nil)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "jpeg original", body)
+		assert.Equal(t, "image/jpeg", resp.Header.Get("Content-Type"))
+		assert.Equal(t, "nosniff", resp.Header.Get("X-Content-Type-Options"))
+		assert.Contains(t, resp.Header.Get("Cache-Control"), "immutable")
+		assert.NotEmpty(t, resp.Header.Get("ETag"))
+		assert.Equal(t, "Accept", resp.Header.Get("Vary"))
+	})
+
+	t.Run("Not Modified", func(t *testing.T) {
+		resp, _ := get(t, "/media/photo.jpg", nil)
+		etag := resp.Header.Get("ETag")
+
+		resp, body := get(t, "/media/photo.jpg", map[string]string{"If-None-Match": etag})
+		assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+		assert.Empty(t, body)
+	})
+
+	t.Run("Range Request", func(t *testing.T) {
+		resp, body := get(t, "/media/photo.jpg", map[string]string{"Range": "bytes=0-3"})
+		assert.Equal(t, http.StatusPartialContent, resp.StatusCode)
+		assert.Equal(t, "jpeg", body)
+	})
+
+	t.Run("WebP Negotiation", func(t *testing.T) {
+		resp, body := get(t, "/media/photo.jpg", map[string]string{"Accept": "image/avif,image/webp,*/*;q=0.8"})
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "webp variant", body)
+		assert.Equal(t, "image/webp", resp.Header.Get("Content-Type"))
+
+		original, _ := get(t, "/media/photo.jpg", nil)
+		assert.NotEqual(t, original.Header.Get("ETag"), resp.Header.Get("ETag"))
+
+		_, body = get(t, "/media/photo.jpg", map[string]string{"Accept": "image/webp;q=0"})
+		assert.Equal(t, "jpeg original", body, "WebP refused with q=0")
+
+		_, body = get(t, "/media/plain.png", map[string]string{"Accept": "image/webp"})
+		assert.Equal(t, "png original", body, "Original is served when there is no WebP file")
+
+		_, body = get(t, "/media/processed.jpg", map[string]string{"Accept": "image/webp"})
+		assert.Equal(t, "processed variant", body, "Full-size WebP variant recorded on the file is served")
+	})
+
+	t.Run("No Listings Or Hidden Files", func(t *testing.T) {
+		for _, path := range []string{"/media/", "/media/nested", "/media/nested/inner.jpg", "/media/.hidden", "/media/missing.jpg", "/media/..%2fetc%2fpasswd"} {
+			resp, _ := get(t, path, nil)
+			assert.Equal(t, http.StatusNotFound, resp.StatusCode, path)
+		}
+	})
+
+	t.Run("Method Not Allowed", func(t *testing.T) {
+		req, err := http.NewRequest("DELETE", ts.URL+"/media/photo.jpg", nil)
+		assert.NoError(t, err)
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		assert.NoError(t, resp.Body.Close())
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	})
+}
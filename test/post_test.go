@@ -3,6 +3,7 @@ package test
 import (
 	"bytes"
 	"chrononewsapi/internal/config"
+	"chrononewsapi/internal/constant"
 	"chrononewsapi/internal/entity"
 	"chrononewsapi/internal/model"
 	"encoding/json"
@@ -153,6 +154,7 @@ func TestPostEndpoints(t *testing.T) {
 		err = json.NewDecoder(resp.Body).Decode(&result)
 		assert.NoError(t, err)
 		assert.Equal(t, "Journalist Post", result.Data.Title)
+		assert.Equal(t, constant.PostStatusDraft, result.Data.Status, "Journalist posts are published through review")
 		journalistPostID = result.Data.ID
 	})
 
@@ -202,6 +204,161 @@ func TestPostEndpoints(t *testing.T) {
 		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 	})
 
+	t.Run("Create Post - Sanitizes Content", func(t *testing.T) {
+		content := `<script>alert(1)</script><p onclick="steal()">Safe <a href="java&#x09;script:alert(1)">link</a> ` +
+			`<a href="https://example.com">ok</a><font>text</font><img src="x" onerror="alert(1)"></p>`
+
+		var b bytes.Buffer
+		w := multipart.NewWriter(&b)
+		assert.NoError(t, w.WriteField("title", "Post with unsafe markup"))
+		assert.NoError(t, w.WriteField("summary", "Summary"))
+		assert.NoError(t, w.WriteField("content", content))
+		assert.NoError(t, w.WriteField("categoryID", fmt.Sprintf("%d", categoryID)))
+		assert.NoError(t, w.Close())
+
+		req, err := http.NewRequest("POST", ts.URL+"/api/post", &b)
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", w.FormDataContentType())
+		req.Header.Set("Authorization", "Bearer "+journalistToken)
+
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var result struct {
+			Data model.PostResponse `json:"data"`
+		}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Contains(t, result.Data.Sanitized, model.ContentRemoval{Tag: "script", Reason: "tag not allowed", Count: 1})
+		assert.Contains(t, result.Data.Sanitized, model.ContentRemoval{Tag: "p", Attribute: "onclick", Reason: "attribute not allowed", Count: 1})
+		assert.Contains(t, result.Data.Sanitized, model.ContentRemoval{Tag: "a", Attribute: "href", Reason: "URL scheme not allowed", Count: 1})
+		assert.Contains(t, result.Data.Sanitized, model.ContentRemoval{Tag: "img", Attribute: "onerror", Reason: "attribute not allowed", Count: 1})
+
+		var post entity.Post
+		assert.NoError(t, testDB.First(&post, result.Data.ID).Error)
+		for _, unsafe := range []string{"<script", "onclick", "onerror", "javascript", "<font"} {
+			assert.NotContains(t, post.Content, unsafe)
+		}
+		assert.Contains(t, post.Content, `<a href="https://example.com">ok</a>`)
+		assert.Contains(t, post.Content, "text", "Content of a disallowed tag should be kept")
+	})
+
+	t.Run("Create Post - Unscanned File", func(t *testing.T) {
+		file := entity.File{Name: "quarantined.png", Type: constant.FileTypeAttachment, Status: constant.FileStatusUploading, ScanStatus: constant.ScanStatusPending}
+		assert.NoError(t, testDB.Create(&file).Error)
+
+		var b bytes.Buffer
+		w := multipart.NewWriter(&b)
+		assert.NoError(t, w.WriteField("title", "Post with unscanned image"))
+		assert.NoError(t, w.WriteField("summary", "Summary"))
+		assert.NoError(t, w.WriteField("content", fmt.Sprintf(`<p><img data-id="%d"></p>`, file.ID)))
+		assert.NoError(t, w.WriteField("categoryID", fmt.Sprintf("%d", categoryID)))
+		assert.NoError(t, w.Close())
+
+		req, err := http.NewRequest("POST", ts.URL+"/api/post", &b)
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", w.FormDataContentType())
+		req.Header.Set("Authorization", "Bearer "+adminToken)
+
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("Create Post - Publishes Private File", func(t *testing.T) {
+		file := entity.File{Name: "embargoed.png", Type: constant.FileTypeAttachment, Status: constant.FileStatusPrivate}
+		assert.NoError(t, testDB.Create(&file).Error)
+		privatePath := filepath.Join(appConfig.Storage.Private, file.Name)
+		assert.NoError(t, os.WriteFile(privatePath, []byte("embargoed"), 0644))
+
+		var b bytes.Buffer
+		w := multipart.NewWriter(&b)
+		assert.NoError(t, w.WriteField("title", "Post with embargoed image"))
+		assert.NoError(t, w.WriteField("summary", "Summary"))
+		assert.NoError(t, w.WriteField("content", fmt.Sprintf(`<p><img data-id="%d"></p>`, file.ID)))
+		assert.NoError(t, w.WriteField("categoryID", fmt.Sprintf("%d", categoryID)))
+		assert.NoError(t, w.Close())
+
+		req, err := http.NewRequest("POST", ts.URL+"/api/post", &b)
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", w.FormDataContentType())
+		req.Header.Set("Authorization", "Bearer "+adminToken)
+
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		assert.NoError(t, testDB.First(&file, file.ID).Error)
+		assert.Equal(t, constant.FileStatusPending, file.Status, "Published files should be queued for processing")
+		assert.FileExists(t, filepath.Join(appConfig.Storage.Attachment, file.Name))
+		assert.NoFileExists(t, privatePath, "Private copy should be removed once published")
+	})
+
+	t.Run("Preview Draft Post - Signs Private Files", func(t *testing.T) {
+		file := entity.File{Name: "draft-image.png", Type: constant.FileTypeAttachment, Status: constant.FileStatusPrivate}
+		assert.NoError(t, testDB.Create(&file).Error)
+		assert.NoError(t, os.WriteFile(filepath.Join(appConfig.Storage.Private, file.Name), []byte("draft"), 0644))
+
+		var b bytes.Buffer
+		w := multipart.NewWriter(&b)
+		assert.NoError(t, w.WriteField("title", "Draft with private files"))
+		assert.NoError(t, w.WriteField("summary", "Summary"))
+		assert.NoError(t, w.WriteField("content", fmt.Sprintf(`<p><img data-id="%d"></p>`, file.ID)))
+		assert.NoError(t, w.WriteField("categoryID", fmt.Sprintf("%d", categoryID)))
+		fw, err := w.CreateFormFile("thumbnail", "thumbnail.png")
+		assert.NoError(t, err)
+		_, err = io.Copy(fw, createDummyPNG(t))
+		assert.NoError(t, err)
+		assert.NoError(t, w.Close())
+
+		req, err := http.NewRequest("POST", ts.URL+"/api/post", &b)
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", w.FormDataContentType())
+		req.Header.Set("Authorization", "Bearer "+journalistToken)
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var created struct {
+			Data model.PostResponse `json:"data"`
+		}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+
+		req, err = http.NewRequest("GET", ts.URL+fmt.Sprintf("/api/post/%d/preview", created.Data.ID), nil)
+		assert.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+journalistToken)
+		respPreview, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := respPreview.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusOK, respPreview.StatusCode)
+
+		var preview struct {
+			Data model.PostResponseWithPreload `json:"data"`
+		}
+		assert.NoError(t, json.NewDecoder(respPreview.Body).Decode(&preview))
+		assert.Contains(t, preview.Data.Content, "/api/file/private/", "Private images should get a signed URL")
+		assert.Contains(t, preview.Data.Thumbnail, "/api/file/private/", "A private thumbnail should get a signed URL")
+	})
+
 	t.Run("Create Post - Unauthenticated", func(t *testing.T) {
 		var b bytes.Buffer
 		w := multipart.NewWriter(&b)
@@ -286,6 +443,120 @@ func TestPostEndpoints(t *testing.T) {
 		assert.Equal(t, newPostID, result.Data.ID)
 	})
 
+	t.Run("Get Post By ID - Image Metadata", func(t *testing.T) {
+		image := entity.File{Name: "figure.png", Type: constant.FileTypeAttachment, AltText: "Flooded street", Caption: "Water rose overnight", Credit: "Staff photographer"}
+		assert.NoError(t, testDB.Create(&image).Error)
+
+		post := entity.Post{
+			UserID:     adminUser.ID,
+			CategoryID: categoryID,
+			Title:      "Post With Figure",
+			Summary:    "Summary",
+			Content:    fmt.Sprintf(`<p>Intro</p><p><img data-id="%d"></p>`, image.ID),
+		}
+		assert.NoError(t, testDB.Create(&post).Error)
+
+		resp, err := client.Get(ts.URL + fmt.Sprintf("/api/post/%d", post.ID))
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data model.PostResponseWithPreload `json:"data"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		assert.NoError(t, err)
+		assert.Contains(t, result.Data.Content, `alt="Flooded street"`)
+		assert.Contains(t, result.Data.Content, "<figure>")
+		assert.Contains(t, result.Data.Content, `<figcaption>Water rose overnight<span class="credit">Staff photographer</span></figcaption>`)
+	})
+
+	t.Run("Get Post By ID - Responsive Variants", func(t *testing.T) {
+		ready := entity.File{
+			Name:   "ready.jpg",
+			Type:   constant.FileTypeAttachment,
+			Status: constant.FileStatusCompressed,
+			Width:  2000,
+			Variants: []entity.FileVariant{
+				{Name: "320w", Width: 320, Format: "jpeg", File: "ready-320.jpg"},
+				{Name: "320w-webp", Width: 320, Format: "webp", File: "ready-320.webp"},
+				{Name: "1280w", Width: 1280, Format: "jpeg", File: "ready-1280.jpg"},
+			},
+		}
+		assert.NoError(t, testDB.Create(&ready).Error)
+		pending := entity.File{
+			Name:     "pending.jpg",
+			Type:     constant.FileTypeAttachment,
+			Status:   constant.FileStatusPending,
+			Variants: []entity.FileVariant{{Name: "320w", Width: 320, Format: "jpeg", File: "pending-320.jpg"}},
+		}
+		assert.NoError(t, testDB.Create(&pending).Error)
+
+		post := entity.Post{
+			UserID:     adminUser.ID,
+			CategoryID: categoryID,
+			Title:      "Post With Variants",
+			Summary:    "Summary",
+			Content:    fmt.Sprintf(`<img data-id="%d"><img data-id="%d">`, ready.ID, pending.ID),
+		}
+		assert.NoError(t, testDB.Create(&post).Error)
+
+		resp, err := client.Get(ts.URL + fmt.Sprintf("/api/post/%d", post.ID))
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data model.PostResponseWithPreload `json:"data"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		assert.NoError(t, err)
+		assert.Contains(t, result.Data.Content, "ready-320.webp 320w")
+		assert.Contains(t, result.Data.Content, "ready-1280.jpg 1280w")
+		assert.Contains(t, result.Data.Content, "ready.jpg 2000w")
+		assert.NotContains(t, result.Data.Content, "ready-320.jpg")
+		assert.NotContains(t, result.Data.Content, "pending-320.jpg")
+		assert.Equal(t, 1, strings.Count(result.Data.Content, "srcset="))
+	})
+
+	t.Run("Get Post By ID - Embedded Media", func(t *testing.T) {
+		document := entity.File{Name: "ruling.pdf", Type: constant.FileTypeAttachment, Status: constant.FileStatusCompressed, Kind: constant.MediaKindDocument, MimeType: "application/pdf"}
+		assert.NoError(t, testDB.Create(&document).Error)
+		clip := entity.File{Name: "interview.mp3", Type: constant.FileTypeAttachment, Status: constant.FileStatusCompressed, Kind: constant.MediaKindAudio, MimeType: "audio/mpeg"}
+		assert.NoError(t, testDB.Create(&clip).Error)
+
+		post := entity.Post{
+			UserID:     adminUser.ID,
+			CategoryID: categoryID,
+			Title:      "Post With Media",
+			Summary:    "Summary",
+			Content:    fmt.Sprintf(`<p><a data-id="%d">Read the ruling</a></p><audio data-id="%d"></audio><video data-id="99999"></video>`, document.ID, clip.ID),
+		}
+		assert.NoError(t, testDB.Create(&post).Error)
+
+		resp, err := client.Get(ts.URL + fmt.Sprintf("/api/post/%d", post.ID))
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data model.PostResponseWithPreload `json:"data"`
+		}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Regexp(t, `<a data-id="\d+" href="[^"]*/ruling\.pdf" type="application/pdf">`, result.Data.Content)
+		assert.Regexp(t, `<audio data-id="\d+" src="[^"]*/interview\.mp3" controls="" preload="metadata">`, result.Data.Content)
+		assert.Contains(t, result.Data.Content, `<video data-id="99999" src="">`)
+	})
+
 	t.Run("Get Post By ID - Not Found", func(t *testing.T) {
 		req, err := http.NewRequest("GET", ts.URL+"/api/post/99999", nil)
 		assert.NoError(t, err)
@@ -470,6 +741,89 @@ func TestPostEndpoints(t *testing.T) {
 		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 	})
 
+	t.Run("Update Post - As Journalist (Co-author)", func(t *testing.T) {
+		var journalistUser entity.User
+		err := testDB.Where("email = ?", "journalist-post@test.com").First(&journalistUser).Error
+		assert.NoError(t, err)
+
+		var b bytes.Buffer
+		w := multipart.NewWriter(&b)
+		assert.NoError(t, w.WriteField("title", "Co-written Post"))
+		assert.NoError(t, w.WriteField("summary", "This post has two bylines."))
+		assert.NoError(t, w.WriteField("content", "<p>Co-written content.</p>"))
+		assert.NoError(t, w.WriteField("categoryID", fmt.Sprintf("%d", categoryID)))
+		assert.NoError(t, w.WriteField("authors", fmt.Sprintf(`[{"userID": %d, "role": "reporter"}, {"userID": %d, "role": "photographer"}]`, adminUser.ID, journalistUser.ID)))
+		assert.NoError(t, w.Close())
+
+		reqCreate, err := http.NewRequest("POST", ts.URL+"/api/post", &b)
+		assert.NoError(t, err)
+		reqCreate.Header.Set("Content-Type", w.FormDataContentType())
+		reqCreate.Header.Set("Authorization", "Bearer "+adminToken)
+		respCreate, err := client.Do(reqCreate)
+		assert.NoError(t, err)
+		defer func() {
+			err := respCreate.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusCreated, respCreate.StatusCode)
+
+		var createResult struct {
+			Data model.PostResponse `json:"data"`
+		}
+		err = json.NewDecoder(respCreate.Body).Decode(&createResult)
+		assert.NoError(t, err)
+
+		b.Reset()
+		w = multipart.NewWriter(&b)
+		assert.NoError(t, w.WriteField("title", "Co-written Post (edited)"))
+		assert.NoError(t, w.WriteField("summary", "Edited by the co-author."))
+		assert.NoError(t, w.WriteField("content", "<p>Co-written content.</p>"))
+		assert.NoError(t, w.WriteField("categoryID", fmt.Sprintf("%d", categoryID)))
+		assert.NoError(t, w.Close())
+
+		req, err := http.NewRequest("PUT", ts.URL+fmt.Sprintf("/api/post/%d", createResult.Data.ID), &b)
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", w.FormDataContentType())
+		req.Header.Set("Authorization", "Bearer "+journalistToken)
+
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var updateResult struct {
+			Data model.PostResponse `json:"data"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&updateResult)
+		assert.NoError(t, err)
+		assert.Equal(t, adminUser.ID, updateResult.Data.UserID, "Co-author edits should not change the owner")
+		assert.Equal(t, constant.PostStatusDraft, updateResult.Data.Status, "Journalist edits should take the post back to review")
+
+		reqGet, err := http.NewRequest("GET", ts.URL+fmt.Sprintf("/api/post/%d/preview", createResult.Data.ID), nil)
+		assert.NoError(t, err)
+		reqGet.Header.Set("Authorization", "Bearer "+journalistToken)
+		respGet, err := client.Do(reqGet)
+		assert.NoError(t, err)
+		defer func() {
+			err := respGet.Body.Close()
+			assert.NoError(t, err)
+		}()
+
+		var getResult struct {
+			Data model.PostResponseWithPreload `json:"data"`
+		}
+		err = json.NewDecoder(respGet.Body).Decode(&getResult)
+		assert.NoError(t, err)
+		if assert.Len(t, getResult.Data.Authors, 2) {
+			assert.Equal(t, adminUser.ID, getResult.Data.Authors[0].ID)
+			assert.Equal(t, journalistUser.ID, getResult.Data.Authors[1].ID)
+			assert.Equal(t, "photographer", getResult.Data.Authors[1].Role)
+		}
+	})
+
 	t.Run("Increment Post View", func(t *testing.T) {
 		req, err := http.NewRequest("PATCH", ts.URL+fmt.Sprintf("/api/post/%d/view", newPostID), nil)
 		assert.NoError(t, err)
@@ -488,6 +842,49 @@ func TestPostEndpoints(t *testing.T) {
 		assert.Equal(t, int64(1), post.ViewCount)
 	})
 
+	t.Run("Increment Post View - Deduplicated Per Visitor", func(t *testing.T) {
+		req, err := http.NewRequest("PATCH", ts.URL+fmt.Sprintf("/api/post/%d/view", newPostID), nil)
+		assert.NoError(t, err)
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.NoError(t, resp.Body.Close())
+
+		var post entity.Post
+		err = testDB.First(&post, newPostID).Error
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), post.ViewCount, "Repeat view from the same visitor should not be counted")
+
+		// Neither a new visitor ID nor a forged forwarding header from an
+		// untrusted client makes the same client count again.
+		for i, header := range []string{"X-Visitor-ID", "X-Forwarded-For", "X-Real-IP"} {
+			req, err = http.NewRequest("PATCH", ts.URL+fmt.Sprintf("/api/post/%d/view", newPostID), nil)
+			assert.NoError(t, err)
+			req.Header.Set(header, fmt.Sprintf("203.0.113.%d", i+1))
+			resp, err = client.Do(req)
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.NoError(t, resp.Body.Close())
+		}
+
+		err = testDB.First(&post, newPostID).Error
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), post.ViewCount, "Spoofed headers should not bypass deduplication")
+
+		req, err = http.NewRequest("PATCH", ts.URL+fmt.Sprintf("/api/post/%d/view", newPostID), nil)
+		assert.NoError(t, err)
+		req.Header.Set("User-Agent", "another-browser")
+		req.Header.Set("X-Visitor-ID", "another-visitor")
+		resp, err = client.Do(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.NoError(t, resp.Body.Close())
+
+		err = testDB.First(&post, newPostID).Error
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), post.ViewCount)
+	})
+
 	t.Run("Increment Post View - Not Found", func(t *testing.T) {
 		req, err := http.NewRequest("PATCH", ts.URL+"/api/post/99999/view", nil)
 		assert.NoError(t, err)
@@ -560,6 +957,7 @@ func TestPostEndpoints(t *testing.T) {
 		err = testDB.First(&fileAfterDelete, fileBeforeDelete.ID).Error
 		assert.NoError(t, err, "File record should still exist after post deletion")
 		assert.Nil(t, fileAfterDelete.UsedByPostID, "File's used_by_post_id should be set to NULL")
+		assert.NotNil(t, fileAfterDelete.UnlinkedAt, "File's unlinked_at should be set")
 	})
 
 	t.Run("Delete Post - As Journalist (Owner)", func(t *testing.T) {
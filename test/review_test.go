@@ -0,0 +1,295 @@
+package test
+
+import (
+	"bytes"
+	"chrononewsapi/internal/config"
+	"chrononewsapi/internal/constant"
+	"chrononewsapi/internal/entity"
+	"chrononewsapi/internal/model"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewEndpoints(t *testing.T) {
+	ts := httptest.NewServer(testRouter)
+	defer ts.Close()
+
+	clearTables(testDB)
+
+	client := config.NewClient()
+
+	adminToken, err := getAuthToken(t, testDB, ts.URL, "admin-review@test.com", "admin")
+	assert.NoError(t, err, "Failed to get admin token")
+
+	journalistToken, err := getAuthToken(t, testDB, ts.URL, "journalist-review@test.com", "journalist")
+	assert.NoError(t, err, "Failed to get journalist token")
+
+	otherJournalistToken, err := getAuthToken(t, testDB, ts.URL, "other-journalist-review@test.com", "journalist")
+	assert.NoError(t, err, "Failed to get other journalist token")
+
+	var adminUser entity.User
+	err = testDB.Where("email = ?", "admin-review@test.com").First(&adminUser).Error
+	assert.NoError(t, err, "Failed to find admin user for review tests")
+
+	categoryID, err := createTestCategory(t, client, adminToken, ts.URL)
+	assert.NoError(t, err, "Failed to create test category")
+
+	var draftPostID int32
+
+	doJSON := func(t *testing.T, method, path, token, body string) *http.Response {
+		req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		return resp
+	}
+
+	t.Run("Create Draft Post - As Journalist", func(t *testing.T) {
+		var b bytes.Buffer
+		w := multipart.NewWriter(&b)
+		assert.NoError(t, w.WriteField("title", "Draft Post"))
+		assert.NoError(t, w.WriteField("summary", "This post needs review."))
+		assert.NoError(t, w.WriteField("content", "<p>Draft content.</p>"))
+		assert.NoError(t, w.WriteField("categoryID", fmt.Sprintf("%d", categoryID)))
+		assert.NoError(t, w.WriteField("draft", "true"))
+		assert.NoError(t, w.Close())
+
+		req, err := http.NewRequest("POST", ts.URL+"/api/post", &b)
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", w.FormDataContentType())
+		req.Header.Set("Authorization", "Bearer "+journalistToken)
+
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var result struct {
+			Data model.PostResponse `json:"data"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		assert.NoError(t, err)
+		assert.Equal(t, constant.PostStatusDraft, result.Data.Status)
+		draftPostID = result.Data.ID
+	})
+
+	t.Run("Get Draft Post - Hidden From Guests", func(t *testing.T) {
+		resp := doJSON(t, "GET", fmt.Sprintf("/api/post/%d", draftPostID), "", "")
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("Preview Draft Post - As Author", func(t *testing.T) {
+		resp := doJSON(t, "GET", fmt.Sprintf("/api/post/%d/preview", draftPostID), journalistToken, "")
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data model.PostResponseWithPreload `json:"data"`
+		}
+		err := json.NewDecoder(resp.Body).Decode(&result)
+		assert.NoError(t, err)
+		assert.Equal(t, constant.PostStatusDraft, result.Data.Status)
+		assert.Contains(t, result.Data.Content, "Draft content.")
+	})
+
+	t.Run("Preview Draft Post - Not Author", func(t *testing.T) {
+		resp := doJSON(t, "GET", fmt.Sprintf("/api/post/%d/preview", draftPostID), otherJournalistToken, "")
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("Submit Review - Not Owner", func(t *testing.T) {
+		resp := doJSON(t, "POST", fmt.Sprintf("/api/post/%d/review", draftPostID), otherJournalistToken, "")
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("Submit Review", func(t *testing.T) {
+		resp := doJSON(t, "POST", fmt.Sprintf("/api/post/%d/review", draftPostID), journalistToken, "")
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data model.ReviewResponse `json:"data"`
+		}
+		err := json.NewDecoder(resp.Body).Decode(&result)
+		assert.NoError(t, err)
+		assert.Equal(t, constant.PostStatusInReview, result.Data.Status)
+		assert.Len(t, result.Data.Events, 1)
+	})
+
+	t.Run("Submit Review - Already In Review", func(t *testing.T) {
+		resp := doJSON(t, "POST", fmt.Sprintf("/api/post/%d/review", draftPostID), journalistToken, "")
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("Update Post - In Review", func(t *testing.T) {
+		var b bytes.Buffer
+		w := multipart.NewWriter(&b)
+		assert.NoError(t, w.WriteField("title", "Edited During Review"))
+		assert.NoError(t, w.WriteField("summary", "This edit should be refused."))
+		assert.NoError(t, w.WriteField("content", "<p>Edited content.</p>"))
+		assert.NoError(t, w.WriteField("categoryID", fmt.Sprintf("%d", categoryID)))
+		assert.NoError(t, w.Close())
+
+		req, err := http.NewRequest("PUT", ts.URL+fmt.Sprintf("/api/post/%d", draftPostID), &b)
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", w.FormDataContentType())
+		req.Header.Set("Authorization", "Bearer "+journalistToken)
+
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("Assign Editor - As Journalist", func(t *testing.T) {
+		body := fmt.Sprintf(`{"editorID": %d}`, adminUser.ID)
+		resp := doJSON(t, "PATCH", fmt.Sprintf("/api/post/%d/review/editor", draftPostID), journalistToken, body)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("Assign Editor", func(t *testing.T) {
+		body := fmt.Sprintf(`{"editorID": %d}`, adminUser.ID)
+		resp := doJSON(t, "PATCH", fmt.Sprintf("/api/post/%d/review/editor", draftPostID), adminToken, body)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data model.ReviewResponse `json:"data"`
+		}
+		err := json.NewDecoder(resp.Body).Decode(&result)
+		assert.NoError(t, err)
+		assert.NotNil(t, result.Data.Editor)
+		assert.Equal(t, adminUser.ID, result.Data.Editor.ID)
+	})
+
+	t.Run("Preview Post In Review - As Editor", func(t *testing.T) {
+		resp := doJSON(t, "GET", fmt.Sprintf("/api/post/%d/preview", draftPostID), adminToken, "")
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data model.PostResponseWithPreload `json:"data"`
+		}
+		err := json.NewDecoder(resp.Body).Decode(&result)
+		assert.NoError(t, err)
+		assert.Equal(t, constant.PostStatusInReview, result.Data.Status)
+		assert.Contains(t, result.Data.Content, "Draft content.")
+	})
+
+	t.Run("Add Inline Note", func(t *testing.T) {
+		body := `{"anchor": "Draft content.", "body": "Please add a source."}`
+		resp := doJSON(t, "POST", fmt.Sprintf("/api/post/%d/review/comment", draftPostID), adminToken, body)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	})
+
+	t.Run("Request Changes", func(t *testing.T) {
+		body := `{"decision": "request_changes", "note": "Needs a source."}`
+		resp := doJSON(t, "PATCH", fmt.Sprintf("/api/post/%d/review/decision", draftPostID), adminToken, body)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data model.ReviewResponse `json:"data"`
+		}
+		err := json.NewDecoder(resp.Body).Decode(&result)
+		assert.NoError(t, err)
+		assert.Equal(t, constant.PostStatusChangesRequested, result.Data.Status)
+	})
+
+	t.Run("Approve Review", func(t *testing.T) {
+		resp := doJSON(t, "POST", fmt.Sprintf("/api/post/%d/review", draftPostID), journalistToken, "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.NoError(t, resp.Body.Close())
+
+		resp = doJSON(t, "PATCH", fmt.Sprintf("/api/post/%d/review/decision", draftPostID), adminToken, `{"decision": "approve"}`)
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data model.ReviewResponse `json:"data"`
+		}
+		err := json.NewDecoder(resp.Body).Decode(&result)
+		assert.NoError(t, err)
+		assert.Equal(t, constant.PostStatusPublished, result.Data.Status)
+		assert.Len(t, result.Data.Events, 5)
+		assert.Len(t, result.Data.Comments, 1)
+	})
+
+	t.Run("Get Approved Post - Visible To Guests", func(t *testing.T) {
+		resp := doJSON(t, "GET", fmt.Sprintf("/api/post/%d", draftPostID), "", "")
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("Get Review - Not Owner", func(t *testing.T) {
+		resp := doJSON(t, "GET", fmt.Sprintf("/api/post/%d/review", draftPostID), otherJournalistToken, "")
+		defer func() {
+			err := resp.Body.Close()
+			assert.NoError(t, err)
+		}()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
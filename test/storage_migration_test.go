@@ -0,0 +1,135 @@
+package test
+
+import (
+	"bytes"
+	"chrononewsapi/internal/adapter"
+	"chrononewsapi/internal/config"
+	"chrononewsapi/internal/constant"
+	"chrononewsapi/internal/entity"
+	"chrononewsapi/internal/repository"
+	"chrononewsapi/internal/service"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStorageMigration(t *testing.T) {
+	ctx := context.Background()
+
+	newMigrator := func(source, target adapter.StorageAdapter, statePath string) *service.StorageMigrator {
+		sourceConfig := *appConfig
+		sourceConfig.Storage = config.StorageConfig{Mode: "memory", Thumbnail: "./storage/thumbnail", Attachment: "./storage/attachment", Profile: "./storage/profile_picture"}
+		targetConfig := *appConfig
+		targetConfig.Storage = config.StorageConfig{Mode: "memory", Thumbnail: "thumbnail", Attachment: "attachment", Profile: "profile_picture"}
+
+		migrator := service.NewStorageMigrator(testDB, repository.NewFileRepository(), source, target, &sourceConfig, &targetConfig)
+		migrator.Concurrency = 2
+		migrator.BatchSize = 1
+		migrator.StatePath = statePath
+		return migrator
+	}
+
+	seed := func(t *testing.T) (adapter.StorageAdapter, []entity.File) {
+		clearTables(testDB)
+
+		files := []entity.File{
+			{
+				Name:   "present.png",
+				Type:   constant.FileTypeAttachment,
+				Status: constant.FileStatusCompressed,
+				Variants: []entity.FileVariant{
+					{Name: "w480_webp", Width: 480, Format: "webp", File: "present-480.webp"},
+				},
+			},
+			{Name: "thumb.png", Type: constant.FileTypeThumbnail, Status: constant.FileStatusPending},
+			{Name: "missing.png", Type: constant.FileTypeProfile, Status: constant.FileStatusPending},
+		}
+		for i := range files {
+			assert.NoError(t, testDB.Create(&files[i]).Error)
+		}
+
+		source := adapter.NewMemoryStorage("http://localhost")
+		assert.NoError(t, source.Put(ctx, "storage/attachment/present.png", bytes.NewReader([]byte("original")), "image/png"))
+		assert.NoError(t, source.Put(ctx, "storage/attachment/present-480.webp", bytes.NewReader([]byte("variant")), "image/webp"))
+		assert.NoError(t, source.Put(ctx, "storage/thumbnail/thumb.png", bytes.NewReader([]byte("thumbnail")), "image/png"))
+		return source, files
+	}
+
+	t.Run("Copy Objects And Report Missing", func(t *testing.T) {
+		source, files := seed(t)
+		target := adapter.NewMemoryStorage("http://cdn.localhost")
+		statePath := filepath.Join(t.TempDir(), "migrate.state")
+
+		report, err := newMigrator(source, target, statePath).Run(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), report.Files)
+		assert.Equal(t, int64(3), report.Copied)
+		assert.Empty(t, report.Failed)
+		if assert.Len(t, report.Missing, 1) {
+			assert.Equal(t, files[2].ID, report.Missing[0].FileID)
+		}
+
+		body, err := target.Get(ctx, "attachment/present-480.webp")
+		assert.NoError(t, err)
+		if body != nil {
+			data, _ := io.ReadAll(body)
+			assert.Equal(t, "variant", string(data))
+		}
+
+		state, err := os.ReadFile(statePath)
+		assert.NoError(t, err)
+		assert.Equal(t, strconv.Itoa(int(files[2].ID)), strings.TrimSpace(string(state)))
+	})
+
+	t.Run("Resume Skips Verified Objects", func(t *testing.T) {
+		source, _ := seed(t)
+		target := adapter.NewMemoryStorage("http://cdn.localhost")
+
+		_, err := newMigrator(source, target, "").Run(ctx)
+		assert.NoError(t, err)
+
+		report, err := newMigrator(source, target, "").Run(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), report.Copied)
+		assert.Equal(t, int64(3), report.Skipped)
+	})
+
+	t.Run("Resume From Checkpoint", func(t *testing.T) {
+		source, files := seed(t)
+		target := adapter.NewMemoryStorage("http://cdn.localhost")
+		statePath := filepath.Join(t.TempDir(), "migrate.state")
+		assert.NoError(t, os.WriteFile(statePath, []byte(strconv.Itoa(int(files[1].ID))), 0644))
+
+		report, err := newMigrator(source, target, statePath).Run(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), report.Files)
+		assert.Equal(t, int64(0), report.Copied)
+		assert.Len(t, report.Missing, 1)
+	})
+
+	t.Run("Dry Run Writes Nothing", func(t *testing.T) {
+		source, _ := seed(t)
+		target := adapter.NewMemoryStorage("http://cdn.localhost")
+		statePath := filepath.Join(t.TempDir(), "migrate.state")
+
+		migrator := newMigrator(source, target, statePath)
+		migrator.DryRun = true
+		report, err := migrator.Run(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), report.Copied)
+		assert.Len(t, report.Missing, 1)
+
+		objects, err := target.List(ctx, "")
+		assert.NoError(t, err)
+		assert.Empty(t, objects)
+
+		_, err = os.Stat(statePath)
+		assert.True(t, os.IsNotExist(err))
+	})
+}
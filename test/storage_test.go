@@ -0,0 +1,87 @@
+package test
+
+import (
+	"bytes"
+	"chrononewsapi/internal/adapter"
+	"chrononewsapi/internal/config"
+	"context"
+	"io"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/webdav"
+)
+
+func TestStorageDrivers(t *testing.T) {
+	davServer := httptest.NewServer(&webdav.Handler{
+		FileSystem: webdav.NewMemFS(),
+		LockSystem: webdav.NewMemLS(),
+	})
+	defer davServer.Close()
+
+	drivers := map[string]func(t *testing.T) (adapter.StorageAdapter, string){
+		"Memory": func(t *testing.T) (adapter.StorageAdapter, string) {
+			return adapter.NewMemoryStorage("http://localhost"), "media"
+		},
+		"Local": func(t *testing.T) (adapter.StorageAdapter, string) {
+			return adapter.NewLocalStorage("http://localhost"), filepath.Join(t.TempDir(), "media")
+		},
+		"WebDAV": func(t *testing.T) (adapter.StorageAdapter, string) {
+			return adapter.NewWebDAVStorage(nil, config.WebDAVConfig{Endpoint: davServer.URL}, "http://cdn.localhost"), "media"
+		},
+	}
+
+	for name, newDriver := range drivers {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			storage, root := newDriver(t)
+			source := filepath.Join(root, "attachment", "source.txt")
+			destination := filepath.Join(root, "thumbnail", "copy.txt")
+			content := []byte("chrono storage")
+
+			err := storage.Put(ctx, source, bytes.NewReader(content), "text/plain")
+			assert.NoError(t, err)
+
+			body, err := storage.Get(ctx, source)
+			assert.NoError(t, err)
+			if body != nil {
+				got, _ := io.ReadAll(body)
+				_ = body.Close()
+				assert.Equal(t, content, got)
+			}
+
+			head, err := adapter.ReadHead(ctx, storage, source, 6)
+			assert.NoError(t, err)
+			assert.Equal(t, []byte("chrono"), head)
+
+			info, err := storage.Stat(ctx, source)
+			assert.NoError(t, err)
+			if info != nil {
+				assert.Equal(t, int64(len(content)), info.Size)
+			}
+
+			err = storage.Copy(ctx, source, destination)
+			assert.NoError(t, err)
+
+			objects, err := storage.List(ctx, root)
+			assert.NoError(t, err)
+			assert.Len(t, objects, 2)
+
+			err = storage.Delete(ctx, source)
+			assert.NoError(t, err)
+
+			_, err = storage.Stat(ctx, source)
+			assert.ErrorIs(t, err, adapter.ErrObjectNotFound)
+
+			err = storage.Delete(ctx, source)
+			assert.NoError(t, err, "Deleting a missing object should not fail")
+
+			_, err = storage.PresignPut(ctx, destination, "text/plain", int64(len(content)), 0)
+			assert.ErrorIs(t, err, adapter.ErrPresignNotSupported)
+
+			assert.Contains(t, storage.URL("storage/attachment/a.png"), "/storage/attachment/a.png")
+		})
+	}
+}